@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+const (
+	apiVersionContextKey contextKey = "apiVersion"
+
+	// currentAPIVersion is also served under the unversioned /api/ prefix,
+	// for clients written before versioning.
+	currentAPIVersion = "v1"
+)
+
+// apiVersion registers the routes of one version of the API under
+// /api/<name>/. Handlers shared between versions can pick their serializers
+// by apiVersionFromContext.
+type apiVersion struct {
+	name string
+	mux  *http.ServeMux
+	// routes are the registered patterns, relative to the prefix, in order.
+	routes []string
+}
+
+// HandleFunc registers handler for pattern, a method and a path relative to
+// the version's prefix such as "GET /chirps". Routes can respond in other
+// formats than JSON through middlewareNegotiate.
+func (v *apiVersion) HandleFunc(pattern string, handler http.HandlerFunc) {
+	v.routes = append(v.routes, pattern)
+	method, path, _ := strings.Cut(pattern, " ")
+	handler = middlewareNegotiate(handler)
+	versioned := func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), apiVersionContextKey, v.name)
+		handler(w, r.WithContext(ctx))
+	}
+
+	v.mux.HandleFunc(method+" /api/"+v.name+path, versioned)
+	if v.name == currentAPIVersion {
+		v.mux.HandleFunc(method+" /api"+path, versioned)
+	}
+}
+
+// apiVersionFromContext returns the API version the request was routed to,
+// or "" outside the API.
+func apiVersionFromContext(ctx context.Context) string {
+	version, _ := ctx.Value(apiVersionContextKey).(string)
+	return version
+}
+
+// canonicalAPIPattern maps a pattern under the unversioned /api/ alias to
+// the same route under the current version, so both count as one route.
+func canonicalAPIPattern(pattern string) string {
+	method, path, _ := strings.Cut(pattern, " ")
+	rest, ok := strings.CutPrefix(path, "/api/")
+	if !ok || strings.HasPrefix(rest, currentAPIVersion+"/") {
+		return pattern
+	}
+	return method + " /api/" + currentAPIVersion + "/" + rest
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	backgroundTaskWorkers   = 4
+	backgroundTaskQueueSize = 256
+)
+
+// backgroundTasks runs work that outlives the request that started it, such
+// as building exports and sending emails, on a fixed number of workers that
+// shutdown drains.
+type backgroundTasks struct {
+	queue chan func(ctx context.Context)
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func newBackgroundTasks() *backgroundTasks {
+	return &backgroundTasks{queue: make(chan func(ctx context.Context), backgroundTaskQueueSize)}
+}
+
+// enqueue schedules task and reports whether it was accepted. It never
+// blocks; tasks are refused when the queue is full or shutdown has begun.
+func (t *backgroundTasks) enqueue(task func(ctx context.Context)) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return false
+	}
+	select {
+	case t.queue <- task:
+		return true
+	default:
+		return false
+	}
+}
+
+// run processes queued tasks until close is called and the queue is empty,
+// and returns once every worker has stopped. Cancelling ctx doesn't stop the
+// workers early; it tells the remaining tasks to give up quickly.
+func (t *backgroundTasks) run(ctx context.Context) {
+	var wg sync.WaitGroup
+	wg.Add(backgroundTaskWorkers)
+	for i := 0; i < backgroundTaskWorkers; i++ {
+		go func() {
+			defer wg.Done()
+			for task := range t.queue {
+				task(ctx)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// close stops accepting tasks, letting run return once the queued ones are
+// done.
+func (t *backgroundTasks) close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.closed {
+		t.closed = true
+		close(t.queue)
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"reflect"
+	"slices"
+
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/validate"
+)
+
+const defaultMaxBodyBytes = 1 << 20
+
+// uploadRoutes are the mux patterns that accept media files rather than
+// JSON, and so get the larger upload limit.
+var uploadRoutes = []string{
+	"POST /api/v1/chirps/{chirpID}/media",
+	"PUT /api/v1/users/me/avatar",
+}
+
+// bodyLimits caps the size of request bodies by the kind of route.
+type bodyLimits struct {
+	json   int64
+	upload int64
+}
+
+// loadBodyLimits reads MAX_BODY_BYTES for JSON requests. Uploads may be as
+// large as the media size limit plus room for the multipart framing.
+func loadBodyLimits(mediaMaxBytes int64) (bodyLimits, error) {
+	maxBytes, err := envInt("MAX_BODY_BYTES", defaultMaxBodyBytes)
+	if err != nil {
+		return bodyLimits{}, err
+	}
+	return bodyLimits{
+		json:   int64(maxBytes),
+		upload: mediaMaxBytes + 1<<20,
+	}, nil
+}
+
+// isUploadRoute reports whether mux would serve r with one of uploadRoutes.
+func isUploadRoute(mux *http.ServeMux, r *http.Request) bool {
+	_, pattern := mux.Handler(r)
+	return slices.Contains(uploadRoutes, canonicalAPIPattern(pattern))
+}
+
+// middleware wraps every request body in an http.MaxBytesReader with the
+// limit of the route mux would serve it with.
+func (l bodyLimits) middleware(mux *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit := l.json
+		if isUploadRoute(mux, r) {
+			limit = l.upload
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// decodeParameters decodes the JSON request body into params and checks it
+// against the rules in its validate tags. On failure it writes a 413 if the
+// body is over its size limit, a 500 if it couldn't be read, or a 400 if it
+// is missing, malformed or has invalid fields, and returns false.
+func decodeParameters(w http.ResponseWriter, r *http.Request, params any) bool {
+	// Reading the body up front separates failures to read it from errors in
+	// its content, which also come from fields' UnmarshalJSON methods, such
+	// as an invalid UUID or time.
+	dat, err := io.ReadAll(r.Body)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		respondWithError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		return false
+	}
+	if err != nil {
+		log.Printf("Error reading parameters: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't read parameters")
+		return false
+	}
+
+	err = json.NewDecoder(bytes.NewReader(dat)).Decode(params)
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+	switch {
+	case err == nil:
+	case errors.Is(err, io.EOF):
+		respondInvalidParameters(w, "Request body is empty", []validate.FieldError{})
+		return false
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		respondInvalidParameters(w, "Request body is not valid JSON", []validate.FieldError{})
+		return false
+	case errors.As(err, &typeErr):
+		respondInvalidParameters(w, "Invalid parameters", []validate.FieldError{{
+			Field:   typeErr.Field,
+			Message: "must be " + jsonTypeName(typeErr.Type),
+		}})
+		return false
+	default:
+		respondInvalidParameters(w, "Invalid parameters: "+err.Error(), []validate.FieldError{})
+		return false
+	}
+
+	if fields := validate.Struct(params); len(fields) > 0 {
+		respondInvalidParameters(w, "Invalid parameters", fields)
+		return false
+	}
+	return true
+}
+
+func respondInvalidParameters(w http.ResponseWriter, msg string, fields []validate.FieldError) {
+	respondWithJSON(w, http.StatusBadRequest, validationErrorReturnVals{
+		errorReturnVals: newErrorReturnVals(http.StatusBadRequest, msg),
+		Fields:          fields,
+	})
+}
+
+// jsonTypeName describes the JSON value a field of type t takes.
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
+
+// validationErrorReturnVals is the error body for requests with invalid
+// fields.
+type validationErrorReturnVals struct {
+	errorReturnVals
+	Fields []validate.FieldError `json:"fields"`
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/google/uuid"
+)
+
+func TestDecodeParameters(t *testing.T) {
+	type parameters struct {
+		Name   string    `json:"name" validate:"required"`
+		Count  int       `json:"count"`
+		UserID uuid.UUID `json:"user_id"`
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		maxBytes   int64
+		wantOK     bool
+		wantStatus int
+		wantField  string
+	}{
+		{"valid", `{"name": "chirpy", "count": 1}`, 0, true, http.StatusOK, ""},
+		{"empty body", ``, 0, false, http.StatusBadRequest, ""},
+		{"malformed JSON", `{"name": `, 0, false, http.StatusBadRequest, ""},
+		{"syntax error", `{name: "chirpy"}`, 0, false, http.StatusBadRequest, ""},
+		{"wrong type", `{"name": "chirpy", "count": "one"}`, 0, false, http.StatusBadRequest, "count"},
+		{"invalid UUID", `{"name": "chirpy", "user_id": "nope"}`, 0, false, http.StatusBadRequest, ""},
+		{"missing required field", `{"count": 1}`, 0, false, http.StatusBadRequest, "name"},
+		{"too large", `{"name": "chirpy"}`, 4, false, http.StatusRequestEntityTooLarge, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/things", strings.NewReader(tt.body))
+			if tt.maxBytes > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, tt.maxBytes)
+			}
+
+			params := parameters{}
+			if got := decodeParameters(w, r, &params); got != tt.wantOK {
+				t.Fatalf("decodeParameters() = %v, want %v", got, tt.wantOK)
+			}
+			if tt.wantOK {
+				return
+			}
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			resp := validationErrorReturnVals{}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("couldn't decode error body: %s", err)
+			}
+			if resp.Error == "" {
+				t.Errorf("error message is empty")
+			}
+			if tt.wantField != "" && (len(resp.Fields) == 0 || resp.Fields[0].Field != tt.wantField) {
+				t.Errorf("fields = %v, want an error for %q", resp.Fields, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestDecodeParametersReadError(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/things", iotest.ErrReader(errors.New("connection reset")))
+
+	params := struct {
+		Name string `json:"name"`
+	}{}
+	if decodeParameters(w, r, &params) {
+		t.Fatal("decodeParameters() = true, want false")
+	}
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+const maxEntityLength = 64
+
+var (
+	hashtagPattern = regexp.MustCompile(`(?:^|[^\w#@])#(\w+)`)
+	mentionPattern = regexp.MustCompile(`(?:^|[^\w#@])@(\w+)`)
+)
+
+// extractHashtags returns the distinct hashtags in a chirp body, lowercased
+// and without the leading '#', in order of first appearance.
+func extractHashtags(body string) []string {
+	return extractEntities(hashtagPattern, body)
+}
+
+// extractMentions returns the distinct @handles in a chirp body, lowercased
+// and without the leading '@', in order of first appearance.
+func extractMentions(body string) []string {
+	return extractEntities(mentionPattern, body)
+}
+
+func extractEntities(pattern *regexp.Regexp, body string) []string {
+	var entities []string
+	seen := make(map[string]bool)
+	for _, match := range pattern.FindAllStringSubmatch(body, -1) {
+		entity := strings.ToLower(match[1])
+		if len(entity) > maxEntityLength || seen[entity] {
+			continue
+		}
+		seen[entity] = true
+		entities = append(entities, entity)
+	}
+	return entities
+}
+
+// indexChirpEntities records the hashtags and mentions in the chirp's
+// current body. Mentions of handles that don't exist, or of users who have
+// blocked the author, are ignored.
+func indexChirpEntities(ctx context.Context, q *database.Queries, chirp database.Chirp) error {
+	for _, tag := range extractHashtags(chirp.Body) {
+		err := q.AddChirpHashtag(ctx, database.AddChirpHashtagParams{
+			ChirpID: chirp.ID,
+			Tag:     tag,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	handles := extractMentions(chirp.Body)
+	if len(handles) == 0 {
+		return nil
+	}
+	userIDs, err := q.GetMentionableUserIDs(ctx, database.GetMentionableUserIDsParams{
+		Handles:  handles,
+		AuthorID: chirp.UserID,
+	})
+	if err != nil {
+		return err
+	}
+	for _, userID := range userIDs {
+		err := q.AddMention(ctx, database.AddMentionParams{
+			ChirpID: chirp.ID,
+			UserID:  userID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clearChirpEntities removes what indexChirpEntities recorded, before a
+// chirp's body is replaced.
+func clearChirpEntities(ctx context.Context, q *database.Queries, chirp database.Chirp) error {
+	err := q.DeleteChirpHashtags(ctx, chirp.ID)
+	if err != nil {
+		return err
+	}
+	return q.DeleteMentions(ctx, chirp.ID)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/ratelimit"
+)
+
+const defaultRedMaxChirpLength = 280
+
+// redPerks is what Chirpy Red members get over everyone else. Each perk is
+// configured on its own so the offering can be tuned without a deploy.
+type redPerks struct {
+	// maxChirpLength is the chirp length limit for members. It never drops
+	// below the standard limit.
+	maxChirpLength int
+	// editingRequiresRed reserves chirp editing for members.
+	editingRequiresRed bool
+	// chirpLimiter and redChirpLimiter cap how many chirps standard users
+	// and members may post. Nil means no cap.
+	chirpLimiter    *ratelimit.SlidingWindow
+	redChirpLimiter *ratelimit.SlidingWindow
+}
+
+// loadRedPerks reads the Chirpy Red configuration: RED_MAX_CHIRP_LENGTH,
+// RED_ONLY_CHIRP_EDITING, and CHIRP_RATE_LIMIT and RED_CHIRP_RATE_LIMIT
+// chirps per CHIRP_RATE_LIMIT_WINDOW per user. The rate limits are off when
+// unset.
+func loadRedPerks() (redPerks, error) {
+	maxChirpLength, err := envInt("RED_MAX_CHIRP_LENGTH", defaultRedMaxChirpLength)
+	if err != nil {
+		return redPerks{}, err
+	}
+	editingRequiresRed, err := envBool("RED_ONLY_CHIRP_EDITING", false)
+	if err != nil {
+		return redPerks{}, err
+	}
+	window, err := envDuration("CHIRP_RATE_LIMIT_WINDOW", time.Hour)
+	if err != nil {
+		return redPerks{}, err
+	}
+	chirpLimiter, err := loadChirpLimiter("CHIRP_RATE_LIMIT", window)
+	if err != nil {
+		return redPerks{}, err
+	}
+	redChirpLimiter, err := loadChirpLimiter("RED_CHIRP_RATE_LIMIT", window)
+	if err != nil {
+		return redPerks{}, err
+	}
+	return redPerks{
+		maxChirpLength:     maxChirpLength,
+		editingRequiresRed: editingRequiresRed,
+		chirpLimiter:       chirpLimiter,
+		redChirpLimiter:    redChirpLimiter,
+	}, nil
+}
+
+func loadChirpLimiter(key string, window time.Duration) (*ratelimit.SlidingWindow, error) {
+	if os.Getenv(key) == "" {
+		return nil, nil
+	}
+	limit, err := envInt(key, 0)
+	if err != nil {
+		return nil, err
+	}
+	return ratelimit.NewSlidingWindow(limit, window), nil
+}
+
+// maxChirpLengthFor returns the chirp length limit that applies to a user.
+func (cfg *apiConfig) maxChirpLengthFor(isChirpyRed bool) int {
+	if isChirpyRed {
+		return max(cfg.redPerks.maxChirpLength, cfg.maxChirpLength)
+	}
+	return cfg.maxChirpLength
+}
+
+// isChirpyRed looks up whether the authenticated user is a Chirpy Red member,
+// responding with an error if it can't tell.
+func (cfg *apiConfig) isChirpyRed(w http.ResponseWriter, r *http.Request, userID uuid.UUID) (isChirpyRed, ok bool) {
+	user, err := cfg.dbQueries.GetUserByID(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error getting user: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get user")
+		return false, false
+	}
+	return user.IsChirpyRed, true
+}
+
+// allowChirps applies the posting rate limit for the user's tier to n new
+// chirps, responding with 429 when they would exceed it.
+func (cfg *apiConfig) allowChirps(w http.ResponseWriter, userID uuid.UUID, isChirpyRed bool, n int) bool {
+	limiter := cfg.redPerks.chirpLimiter
+	if isChirpyRed {
+		limiter = cfg.redPerks.redChirpLimiter
+	}
+	if limiter == nil {
+		return true
+	}
+	allowed, retryAfter := limiter.AllowN(userID.String(), n)
+	if !allowed {
+		respondTooManyRequests(w, retryAfter)
+		return false
+	}
+	return true
+}
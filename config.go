@@ -0,0 +1,310 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/auth"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/captcha"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/langdetect"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/mailer"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/ratelimit"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/storage"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// minJWTSecretLength is the shortest HS256 secret we accept, in bytes.
+const minJWTSecretLength = 32
+
+// envDuration reads a Go duration string (e.g. "15m") from the environment,
+// falling back to the given default when the variable is unset.
+func envDuration(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", key, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%s must be positive", key)
+	}
+	return d, nil
+}
+
+// envInt reads a positive integer from the environment, falling back to the
+// given default when the variable is unset.
+func envInt(key string, fallback int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", key, err)
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("%s must be positive", key)
+	}
+	return n, nil
+}
+
+// envList reads a comma-separated list from the environment, dropping empty
+// entries.
+func envList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// envBool reads a boolean ("true", "1", "false", ...) from the environment,
+// falling back to the given default when the variable is unset.
+func envBool(key string, fallback bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", key, err)
+	}
+	return b, nil
+}
+
+// loadJWTConfig returns the access token signer and lifetime, refusing
+// configurations that would issue insecure tokens. JWT_SIGNING_METHOD picks
+// HS256 (the default, keyed by JWT_SECRET) or RS256 (keyed by the
+// comma-separated PEM files in JWT_RSA_KEY_FILES, signing key first).
+func loadJWTConfig() (auth.TokenSigner, time.Duration, error) {
+	ttl, err := envDuration("ACCESS_TOKEN_TTL", defaultAccessTokenTTL)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch method := os.Getenv("JWT_SIGNING_METHOD"); method {
+	case "", "HS256":
+		secret := os.Getenv("JWT_SECRET")
+		if secret == "" {
+			return nil, 0, fmt.Errorf("JWT_SECRET must be set")
+		}
+		if len(secret) < minJWTSecretLength {
+			return nil, 0, fmt.Errorf("JWT_SECRET must be at least %d bytes", minJWTSecretLength)
+		}
+		return auth.HMACSigner{Secret: secret}, ttl, nil
+	case "RS256":
+		files := envList("JWT_RSA_KEY_FILES")
+		if len(files) == 0 {
+			return nil, 0, fmt.Errorf("JWT_RSA_KEY_FILES must be set for RS256")
+		}
+		keySet, err := auth.LoadRSAKeySet(files...)
+		if err != nil {
+			return nil, 0, err
+		}
+		return keySet, ttl, nil
+	default:
+		return nil, 0, fmt.Errorf("unsupported JWT_SIGNING_METHOD %q", method)
+	}
+}
+
+// loadMailer returns an SMTP mailer when SMTP_ADDR is set, and otherwise a
+// mailer that only logs messages.
+func loadMailer() mailer.Mailer {
+	addr := os.Getenv("SMTP_ADDR")
+	if addr == "" {
+		return mailer.LogMailer{}
+	}
+	return mailer.SMTPMailer{
+		Addr:     addr,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	}
+}
+
+// lockoutPolicy controls when repeated failed logins lock an account.
+type lockoutPolicy struct {
+	maxFailures int
+	window      time.Duration
+	duration    time.Duration
+}
+
+func loadLockoutPolicy() (lockoutPolicy, error) {
+	maxFailures, err := envInt("LOGIN_MAX_FAILURES", 5)
+	if err != nil {
+		return lockoutPolicy{}, err
+	}
+	window, err := envDuration("LOGIN_FAILURE_WINDOW", 15*time.Minute)
+	if err != nil {
+		return lockoutPolicy{}, err
+	}
+	duration, err := envDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute)
+	if err != nil {
+		return lockoutPolicy{}, err
+	}
+	return lockoutPolicy{
+		maxFailures: maxFailures,
+		window:      window,
+		duration:    duration,
+	}, nil
+}
+
+func loadPasswordPolicy() (auth.PasswordPolicy, error) {
+	minLength, err := envInt("PASSWORD_MIN_LENGTH", 8)
+	if err != nil {
+		return auth.PasswordPolicy{}, err
+	}
+	policy := auth.PasswordPolicy{MinLength: minLength}
+
+	flags := []struct {
+		key      string
+		fallback bool
+		dst      *bool
+	}{
+		{"PASSWORD_REQUIRE_UPPER", true, &policy.RequireUpper},
+		{"PASSWORD_REQUIRE_LOWER", true, &policy.RequireLower},
+		{"PASSWORD_REQUIRE_DIGIT", true, &policy.RequireDigit},
+		{"PASSWORD_REQUIRE_SYMBOL", false, &policy.RequireSymbol},
+		{"PASSWORD_DENY_COMMON", true, &policy.DenyCommonList},
+	}
+	for _, f := range flags {
+		*f.dst, err = envBool(f.key, f.fallback)
+		if err != nil {
+			return auth.PasswordPolicy{}, err
+		}
+	}
+	return policy, nil
+}
+
+// loadPasswordHasher picks the algorithm for new password hashes from
+// PASSWORD_HASH_ALGORITHM ("bcrypt" by default, or "argon2id"). Existing
+// hashes keep verifying either way since the hash format names its algorithm.
+func loadPasswordHasher() (auth.PasswordHasher, error) {
+	switch algorithm := os.Getenv("PASSWORD_HASH_ALGORITHM"); algorithm {
+	case "", "bcrypt":
+		cost, err := envInt("BCRYPT_COST", bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("BCRYPT_COST must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+		return auth.BcryptHasher{Cost: cost}, nil
+	case "argon2id":
+		hasher := auth.DefaultArgon2idHasher
+		memory, err := envInt("ARGON2_MEMORY_KIB", int(hasher.Memory))
+		if err != nil {
+			return nil, err
+		}
+		iterations, err := envInt("ARGON2_ITERATIONS", int(hasher.Iterations))
+		if err != nil {
+			return nil, err
+		}
+		parallelism, err := envInt("ARGON2_PARALLELISM", int(hasher.Parallelism))
+		if err != nil {
+			return nil, err
+		}
+		if parallelism > 255 {
+			return nil, fmt.Errorf("ARGON2_PARALLELISM must be at most 255")
+		}
+		hasher.Memory = uint32(memory)
+		hasher.Iterations = uint32(iterations)
+		hasher.Parallelism = uint8(parallelism)
+		return hasher, nil
+	default:
+		return nil, fmt.Errorf("unsupported PASSWORD_HASH_ALGORITHM %q", algorithm)
+	}
+}
+
+// loadAuthRateLimiter returns the limiter shared by the login and signup
+// endpoints: AUTH_RATE_LIMIT requests per AUTH_RATE_LIMIT_WINDOW per IP.
+func loadAuthRateLimiter() (*ratelimit.SlidingWindow, error) {
+	limit, err := envInt("AUTH_RATE_LIMIT", 10)
+	if err != nil {
+		return nil, err
+	}
+	window, err := envDuration("AUTH_RATE_LIMIT_WINDOW", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	return ratelimit.NewSlidingWindow(limit, window), nil
+}
+
+// loadMediaStore returns where uploaded media is kept and the per-upload
+// size limit.
+func loadMediaStore() (storage.Store, int64, error) {
+	maxBytes, err := envInt("MEDIA_MAX_BYTES", defaultMediaMaxBytes)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	dir := os.Getenv("MEDIA_DIR")
+	if dir == "" {
+		dir = "media"
+	}
+	baseURL := os.Getenv("MEDIA_BASE_URL")
+	if baseURL == "" {
+		baseURL = "/app/media"
+	}
+	return storage.DiskStore{Dir: dir, BaseURL: baseURL}, int64(maxBytes), nil
+}
+
+// loadExportStore returns the store for data exports, in EXPORT_DIR. Exports
+// are served only to their owner by downloadExportHandler, so the directory
+// must be outside the one the /app/ file server serves. It defaults to the
+// user cache directory.
+func loadExportStore() (storage.Store, error) {
+	dir := os.Getenv("EXPORT_DIR")
+	if dir == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("EXPORT_DIR must be set: %w", err)
+		}
+		dir = filepath.Join(cacheDir, "chirpy", "exports")
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	served, err := filepath.Abs(".")
+	if err != nil {
+		return nil, err
+	}
+	if rel, err := filepath.Rel(served, abs); err == nil && filepath.IsLocal(rel) {
+		return nil, fmt.Errorf("EXPORT_DIR %s is served publicly by /app/", dir)
+	}
+	return storage.DiskStore{Dir: abs}, nil
+}
+
+// loadCaptchaVerifier returns the signup CAPTCHA verifier selected by
+// CAPTCHA_PROVIDER, or nil when CAPTCHA checks are disabled.
+func loadCaptchaVerifier() (captcha.Verifier, error) {
+	provider := os.Getenv("CAPTCHA_PROVIDER")
+	if provider == "" || provider == "none" {
+		return nil, nil
+	}
+	secret := os.Getenv("CAPTCHA_SECRET")
+	if secret == "" {
+		return nil, fmt.Errorf("CAPTCHA_SECRET must be set when CAPTCHA_PROVIDER is %q", provider)
+	}
+	return captcha.NewVerifier(provider, secret)
+}
+
+// loadLanguageDetector returns the chirp language detector selected by
+// LANGUAGE_DETECTOR, defaulting to the built-in heuristic.
+func loadLanguageDetector() (langdetect.Detector, error) {
+	name := os.Getenv("LANGUAGE_DETECTOR")
+	if name == "" {
+		name = "heuristic"
+	}
+	return langdetect.New(name)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	defaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+	defaultCORSHeaders = []string{"Authorization", "Content-Type"}
+	// corsExposedHeaders are the response headers browsers let cross-origin
+	// scripts read besides the CORS-safelisted ones: pagination, conditional
+	// requests and rate limits.
+	corsExposedHeaders = []string{
+		"ETag",
+		"Link",
+		"X-Total-Count",
+		"RateLimit-Limit",
+		"RateLimit-Remaining",
+		"RateLimit-Reset",
+		"Retry-After",
+	}
+)
+
+const defaultCORSMaxAge = 10 * time.Minute
+
+// corsPolicy says which cross-origin browser clients may call the API. With
+// no allowed origins every cross-origin request is left to the browser to
+// refuse, as before.
+type corsPolicy struct {
+	// origins are exact origins such as "https://app.example.com", or "*"
+	// for any.
+	origins          []string
+	methods          []string
+	headers          []string
+	allowCredentials bool
+	maxAge           time.Duration
+}
+
+// loadCORSPolicy reads the comma-separated CORS_ALLOWED_ORIGINS,
+// CORS_ALLOWED_METHODS and CORS_ALLOWED_HEADERS, plus CORS_ALLOW_CREDENTIALS
+// and CORS_MAX_AGE, the time browsers may cache a preflight response.
+// Credentials can't be allowed for any origin, or every site could make
+// requests with its visitors' cookies.
+func loadCORSPolicy() (corsPolicy, error) {
+	policy := corsPolicy{
+		origins: envList("CORS_ALLOWED_ORIGINS"),
+		methods: envList("CORS_ALLOWED_METHODS"),
+		headers: envList("CORS_ALLOWED_HEADERS"),
+	}
+	if len(policy.methods) == 0 {
+		policy.methods = defaultCORSMethods
+	}
+	if len(policy.headers) == 0 {
+		policy.headers = defaultCORSHeaders
+	}
+
+	var err error
+	policy.allowCredentials, err = envBool("CORS_ALLOW_CREDENTIALS", false)
+	if err != nil {
+		return corsPolicy{}, err
+	}
+	if policy.allowCredentials && slices.Contains(policy.origins, "*") {
+		return corsPolicy{}, errors.New("CORS_ALLOW_CREDENTIALS can't be combined with a * origin in CORS_ALLOWED_ORIGINS")
+	}
+	policy.maxAge, err = envDuration("CORS_MAX_AGE", defaultCORSMaxAge)
+	if err != nil {
+		return corsPolicy{}, err
+	}
+	return policy, nil
+}
+
+func (p corsPolicy) allowsOrigin(origin string) bool {
+	return slices.Contains(p.origins, "*") || slices.Contains(p.origins, origin)
+}
+
+// middleware adds CORS headers for allowed origins and answers their
+// preflight requests itself, so handlers never see them.
+func (p corsPolicy) middleware(next http.Handler) http.Handler {
+	if len(p.origins) == 0 {
+		return next
+	}
+	methods := strings.Join(p.methods, ", ")
+	headers := strings.Join(p.headers, ", ")
+	maxAge := strconv.Itoa(int(p.maxAge.Seconds()))
+	exposed := strings.Join(corsExposedHeaders, ", ")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Origin")
+		origin := r.Header.Get("Origin")
+		if origin == "" || !p.allowsOrigin(origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Browsers refuse a wildcard origin on credentialed requests, so
+		// the origin is always echoed back.
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		if p.allowCredentials {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+			w.Header().Set("Access-Control-Expose-Headers", exposed)
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Access-Control-Request-Method")
+		w.Header().Add("Vary", "Access-Control-Request-Headers")
+		w.Header().Set("Access-Control-Allow-Methods", methods)
+		w.Header().Set("Access-Control-Allow-Headers", headers)
+		w.Header().Set("Access-Control-Max-Age", maxAge)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
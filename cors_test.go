@@ -0,0 +1,89 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLoadCORSPolicyCredentials(t *testing.T) {
+	tests := []struct {
+		name        string
+		origins     string
+		credentials string
+		wantErr     bool
+	}{
+		{"exact origin with credentials", "https://app.example.com", "true", false},
+		{"wildcard without credentials", "*", "false", false},
+		{"wildcard with credentials", "*", "true", true},
+		{"wildcard among origins with credentials", "https://app.example.com,*", "true", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CORS_ALLOWED_ORIGINS", tt.origins)
+			t.Setenv("CORS_ALLOW_CREDENTIALS", tt.credentials)
+
+			_, err := loadCORSPolicy()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("loadCORSPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	policy := corsPolicy{
+		origins:          []string{"https://app.example.com"},
+		methods:          defaultCORSMethods,
+		headers:          defaultCORSHeaders,
+		allowCredentials: true,
+		maxAge:           defaultCORSMaxAge,
+	}
+	handler := policy.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Run("allowed origin", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/chirps", nil)
+		r.Header.Set("Origin", "https://app.example.com")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+			t.Errorf("Access-Control-Allow-Origin = %q, want the request origin", got)
+		}
+		if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+			t.Errorf("Access-Control-Allow-Credentials = %q, want true", got)
+		}
+		if got := w.Header().Get("Access-Control-Expose-Headers"); got == "" {
+			t.Error("Access-Control-Expose-Headers is missing")
+		}
+	})
+
+	t.Run("other origin", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/chirps", nil)
+		r.Header.Set("Origin", "https://evil.example.com")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+			t.Errorf("Access-Control-Allow-Origin = %q, want none", got)
+		}
+	})
+
+	t.Run("preflight", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodOptions, "/api/v1/chirps", nil)
+		r.Header.Set("Origin", "https://app.example.com")
+		r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != http.StatusNoContent {
+			t.Errorf("status = %d, want %d", w.Code, http.StatusNoContent)
+		}
+		if got := w.Header().Get("Access-Control-Allow-Methods"); got == "" {
+			t.Error("Access-Control-Allow-Methods is missing")
+		}
+	})
+}
@@ -3,7 +3,18 @@ module github.com/ja8mpi/bootdev-chirpy-server-go
 go 1.24.4
 
 require (
-	github.com/google/uuid v1.6.0 // indirect
-	github.com/joho/godotenv v1.5.1 // indirect
-	github.com/lib/pq v1.10.9 // indirect
+	github.com/google/uuid v1.6.0
+	github.com/joho/godotenv v1.5.1
+	github.com/lib/pq v1.10.9
+	golang.org/x/crypto v0.39.0
+)
+
+require (
+	github.com/golang-jwt/jwt/v5 v5.2.2
+	golang.org/x/net v0.41.0
+)
+
+require (
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/text v0.26.0 // indirect
 )
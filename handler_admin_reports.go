@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+const (
+	moderationActionHideChirp     = "hide_chirp"
+	moderationActionSuspendAuthor = "suspend_author"
+
+	defaultReportsLimit = 50
+	maxReportsLimit     = 500
+)
+
+// listReportsHandler is the moderation queue: reports oldest first,
+// optionally filtered by ?status=.
+func (cfg *apiConfig) listReportsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	params := database.ListChirpReportsParams{MaxResults: defaultReportsLimit}
+
+	if v := query.Get("status"); v != "" {
+		if v != reportStatusOpen && v != reportStatusDismissed && v != reportStatusResolved {
+			respondWithError(w, http.StatusBadRequest, "Invalid status")
+			return
+		}
+		params.Status = sql.NullString{String: v, Valid: true}
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > maxReportsLimit {
+			respondWithError(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		params.MaxResults = int32(limit)
+	}
+
+	rows, err := cfg.dbQueries.ListChirpReports(r.Context(), params)
+	if err != nil {
+		log.Printf("Error listing reports: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list reports")
+		return
+	}
+
+	reports := make([]ChirpReport, 0, len(rows))
+	for _, row := range rows {
+		reports = append(reports, chirpReportFromDB(row))
+	}
+	respondWithJSON(w, http.StatusOK, reports)
+}
+
+// dismissReportHandler closes a report without acting on the chirp.
+func (cfg *apiConfig) dismissReportHandler(w http.ResponseWriter, r *http.Request) {
+	cfg.resolveReport(w, r, reportStatusDismissed, "", nil)
+}
+
+// hideReportedChirpHandler removes the reported chirp from every listing.
+// Unlike a deletion by its author, a hidden chirp can't be restored.
+func (cfg *apiConfig) hideReportedChirpHandler(w http.ResponseWriter, r *http.Request) {
+	cfg.resolveReport(w, r, reportStatusResolved, moderationActionHideChirp, func(ctx context.Context, q *database.Queries, report database.ChirpReport) error {
+		err := q.HideChirp(ctx, report.ChirpID)
+		if err != nil {
+			return err
+		}
+		return q.UnpinChirp(ctx, uuid.NullUUID{UUID: report.ChirpID, Valid: true})
+	})
+}
+
+// suspendReportedAuthorHandler suspends the author of the reported chirp:
+// they can no longer log in, and their sessions and access tokens are
+// revoked.
+func (cfg *apiConfig) suspendReportedAuthorHandler(w http.ResponseWriter, r *http.Request) {
+	var authorID uuid.UUID
+	ok := cfg.resolveReport(w, r, reportStatusResolved, moderationActionSuspendAuthor, func(ctx context.Context, q *database.Queries, report database.ChirpReport) error {
+		chirp, err := q.GetChirpIncludingDeleted(ctx, report.ChirpID)
+		if err != nil {
+			return err
+		}
+		authorID = chirp.UserID
+
+		err = q.SuspendUser(ctx, authorID)
+		if err != nil {
+			return err
+		}
+		_, err = q.RevokeAllRefreshTokensForUser(ctx, authorID)
+		if err != nil {
+			return err
+		}
+		return q.RevokeAllPersonalAccessTokensForUser(ctx, authorID)
+	})
+	if ok {
+		cfg.recordAuthEvent(r, authorID, authEventAccountSuspended, "")
+	}
+}
+
+// resolveReport closes the open report named by the {reportID} path value
+// with the given status and action, recording the acting admin. apply, if
+// set, carries out the action in the same transaction. It writes the
+// response and reports whether the report was resolved.
+func (cfg *apiConfig) resolveReport(w http.ResponseWriter, r *http.Request, status, action string, apply func(context.Context, *database.Queries, database.ChirpReport) error) bool {
+	adminID, _ := userIDFromContext(r.Context())
+
+	reportID, err := uuid.Parse(r.PathValue("reportID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid report ID")
+		return false
+	}
+
+	report, err := cfg.dbQueries.GetChirpReport(r.Context(), reportID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Report not found")
+		return false
+	}
+	if err != nil {
+		log.Printf("Error getting report: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get report")
+		return false
+	}
+	if report.Status != reportStatusOpen {
+		respondWithError(w, http.StatusConflict, "Report has already been resolved")
+		return false
+	}
+
+	resolved, err := cfg.applyModeration(r.Context(), database.ResolveChirpReportParams{
+		ID:         report.ID,
+		Status:     status,
+		Action:     sql.NullString{String: action, Valid: action != ""},
+		ResolvedBy: uuid.NullUUID{UUID: adminID, Valid: true},
+	}, apply)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusConflict, "Report has already been resolved")
+		return false
+	}
+	if err != nil {
+		log.Printf("Error resolving report: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't resolve report")
+		return false
+	}
+
+	respondWithJSON(w, http.StatusOK, chirpReportFromDB(resolved))
+	return true
+}
+
+func (cfg *apiConfig) applyModeration(ctx context.Context, params database.ResolveChirpReportParams, apply func(context.Context, *database.Queries, database.ChirpReport) error) (database.ChirpReport, error) {
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return database.ChirpReport{}, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	report, err := qtx.ResolveChirpReport(ctx, params)
+	if err != nil {
+		return database.ChirpReport{}, err
+	}
+	if apply != nil {
+		err = apply(ctx, qtx, report)
+		if err != nil {
+			return database.ChirpReport{}, err
+		}
+	}
+
+	return report, tx.Commit()
+}
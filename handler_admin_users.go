@@ -0,0 +1,119 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+const impersonationTokenTTL = 15 * time.Minute
+
+func (cfg *apiConfig) unlockUserHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	rows, err := cfg.dbQueries.UnlockUser(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error unlocking user: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't unlock user")
+		return
+	}
+	if rows == 0 {
+		respondWithError(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	err = cfg.dbQueries.ClearFailedLoginAttempts(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error clearing failed logins: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't unlock user")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// verifyUserHandler grants or revokes a user's verified badge, shown next to
+// their name on profiles and chirps. Users can't set it themselves.
+func (cfg *apiConfig) verifyUserHandler(w http.ResponseWriter, r *http.Request) {
+	type verifyParameters struct {
+		Verified bool `json:"verified"`
+	}
+
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	params := verifyParameters{}
+	if !decodeParameters(w, r, &params) {
+		return
+	}
+
+	rows, err := cfg.dbQueries.SetUserVerified(r.Context(), database.SetUserVerifiedParams{
+		ID:         userID,
+		IsVerified: params.Verified,
+	})
+	if err != nil {
+		log.Printf("Error verifying user: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't verify user")
+		return
+	}
+	if rows == 0 {
+		respondWithError(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// impersonateUserHandler issues a short-lived access token acting as another
+// user so support staff can reproduce their bugs. The token names the admin
+// in its "act" claim and no refresh token is issued.
+func (cfg *apiConfig) impersonateUserHandler(w http.ResponseWriter, r *http.Request) {
+	type impersonateResponse struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+
+	adminID, _ := userIDFromContext(r.Context())
+
+	userID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	user, err := cfg.dbQueries.GetUserByID(r.Context(), userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error looking up user: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't impersonate user")
+		return
+	}
+
+	token, err := cfg.tokenSigner.MakeImpersonationJWT(user.ID, adminID, impersonationTokenTTL)
+	if err != nil {
+		log.Printf("Error creating impersonation token: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't impersonate user")
+		return
+	}
+
+	cfg.recordAuthEvent(r, adminID, authEventImpersonation, user.ID.String())
+	respondWithJSON(w, http.StatusOK, impersonateResponse{
+		Token:     token,
+		ExpiresAt: time.Now().UTC().Add(impersonationTokenTTL),
+	})
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/auth"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+// createAPIKeyHandler provisions a key for a machine caller. The plaintext
+// key is only returned here; the database keeps its hash.
+func (cfg *apiConfig) createAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	type apiKeyParameters struct {
+		Name string `json:"name" validate:"required"`
+	}
+	type apiKeyResponse struct {
+		ID        uuid.UUID `json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+		Name      string    `json:"name"`
+		Key       string    `json:"key"`
+	}
+
+	params := apiKeyParameters{}
+	if !decodeParameters(w, r, &params) {
+		return
+	}
+
+	key, err := auth.MakeAPIKey()
+	if err != nil {
+		log.Printf("Error generating API key: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't generate API key")
+		return
+	}
+
+	apiKey, err := cfg.dbQueries.CreateAPIKey(r.Context(), database.CreateAPIKeyParams{
+		Name:    params.Name,
+		KeyHash: auth.HashToken(key),
+	})
+	if err != nil {
+		log.Printf("Error saving API key: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't save API key")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, apiKeyResponse{
+		ID:        apiKey.ID,
+		CreatedAt: apiKey.CreatedAt,
+		Name:      apiKey.Name,
+		Key:       key,
+	})
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/avatar"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+// uploadAvatarHandler sets the authenticated user's avatar from the image in
+// the multipart "file" field. The image is cropped to a square and scaled
+// to avatar.Size pixels before it's stored, replacing any previous avatar.
+func (cfg *apiConfig) uploadAvatarHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	data, _, err := cfg.readUploadedImage(w, r)
+	if errors.Is(err, errMediaTooLarge) {
+		respondWithError(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resized, err := avatar.Process(data)
+	if errors.Is(err, avatar.ErrUnsupportedFormat) {
+		respondWithError(w, http.StatusBadRequest, "Avatars must be JPEG, PNG or GIF images")
+		return
+	}
+	if errors.Is(err, avatar.ErrTooLarge) {
+		respondWithError(w, http.StatusRequestEntityTooLarge, "Image dimensions are too large")
+		return
+	}
+	if err != nil {
+		log.Printf("Error processing avatar: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't upload avatar")
+		return
+	}
+
+	previous, err := cfg.dbQueries.GetUserByID(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error getting user: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't upload avatar")
+		return
+	}
+
+	key, err := cfg.putMedia(r.Context(), "avatars/", resized, "image/png")
+	if err != nil {
+		log.Printf("Error storing avatar: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't upload avatar")
+		return
+	}
+
+	user, err := cfg.dbQueries.SetUserAvatar(r.Context(), database.SetUserAvatarParams{
+		ID:        userID,
+		AvatarKey: sql.NullString{String: key, Valid: true},
+	})
+	if err != nil {
+		log.Printf("Error setting avatar: %s", err)
+		cfg.deleteMedia(r.Context(), key)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't upload avatar")
+		return
+	}
+	if previous.AvatarKey.Valid {
+		cfg.deleteMedia(r.Context(), previous.AvatarKey.String)
+	}
+
+	respondWithJSON(w, http.StatusOK, cfg.userFromDB(user))
+}
+
+// avatarURL is the public URL of the user's avatar, or nil if they haven't
+// uploaded one.
+func (cfg *apiConfig) avatarURL(user database.User) *string {
+	if !user.AvatarKey.Valid {
+		return nil
+	}
+	url := cfg.mediaStore.URL(user.AvatarKey.String)
+	return &url
+}
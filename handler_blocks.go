@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+// blockHandler makes the authenticated user block {userID} (an ID or
+// handle). Any follows and follow requests between the two are removed.
+// Blocked users' chirps are left out of the blocker's listings, and they can
+// no longer follow, reply to or mention the blocker. Blocking someone twice
+// is not an error.
+func (cfg *apiConfig) blockHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	blocked, ok := cfg.lookupActiveUser(w, r)
+	if !ok {
+		return
+	}
+	if blocked.ID == userID {
+		respondWithError(w, http.StatusBadRequest, "You can't block yourself")
+		return
+	}
+
+	err := cfg.blockUser(r.Context(), userID, blocked.ID)
+	if err != nil {
+		log.Printf("Error blocking user: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't block user")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cfg *apiConfig) blockUser(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	err = qtx.Block(ctx, database.BlockParams{
+		BlockerID: blockerID,
+		BlockedID: blockedID,
+	})
+	if err != nil {
+		return err
+	}
+	err = qtx.DeleteFollowsBetween(ctx, database.DeleteFollowsBetweenParams{
+		UserA: blockerID,
+		UserB: blockedID,
+	})
+	if err != nil {
+		return err
+	}
+	err = qtx.DeleteFollowRequestsBetween(ctx, database.DeleteFollowRequestsBetweenParams{
+		UserA: blockerID,
+		UserB: blockedID,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// unblockHandler undoes blockHandler. Follows removed by the block are not
+// restored. Unblocking someone who isn't blocked is not an error.
+func (cfg *apiConfig) unblockHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	blocked, ok := cfg.lookupActiveUser(w, r)
+	if !ok {
+		return
+	}
+
+	err := cfg.dbQueries.Unblock(r.Context(), database.UnblockParams{
+		BlockerID: userID,
+		BlockedID: blocked.ID,
+	})
+	if err != nil {
+		log.Printf("Error unblocking user: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't unblock user")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+func (cfg *apiConfig) bookmarkChirpHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	chirp, ok := cfg.lookupChirp(w, r)
+	if !ok {
+		return
+	}
+
+	err := cfg.dbQueries.AddBookmark(r.Context(), database.AddBookmarkParams{
+		UserID:  userID,
+		ChirpID: chirp.ID,
+	})
+	if err != nil {
+		log.Printf("Error bookmarking chirp: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't bookmark chirp")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cfg *apiConfig) deleteBookmarkHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	chirp, ok := cfg.lookupChirp(w, r)
+	if !ok {
+		return
+	}
+
+	err := cfg.dbQueries.DeleteBookmark(r.Context(), database.DeleteBookmarkParams{
+		UserID:  userID,
+		ChirpID: chirp.ID,
+	})
+	if err != nil {
+		log.Printf("Error deleting bookmark: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't delete bookmark")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listBookmarksHandler lists the authenticated user's bookmarked chirps,
+// most recently bookmarked first, paged with ?limit= and ?after=.
+// Bookmarks are private, so there is no way to list another user's.
+func (cfg *apiConfig) listBookmarksHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	page, err := parsePageParams(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows, err := cfg.dbQueries.ListBookmarkedChirps(r.Context(), database.ListBookmarkedChirpsParams{
+		UserID:         userID,
+		AfterCreatedAt: page.afterCreatedAt,
+		AfterID:        page.afterID,
+		MaxResults:     page.limit,
+	})
+	if err != nil {
+		log.Printf("Error listing bookmarks: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list bookmarks")
+		return
+	}
+
+	total, err := cfg.dbQueries.CountBookmarkedChirps(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error counting bookmarks: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list bookmarks")
+		return
+	}
+
+	chirps := make([]Chirp, 0, len(rows))
+	for _, row := range rows {
+		chirps = append(chirps, chirpFromDB(row.Chirp))
+	}
+	err = cfg.loadChirpDetails(r.Context(), chirps)
+	if err != nil {
+		log.Printf("Error loading chirp details: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list bookmarks")
+		return
+	}
+
+	cfg.views.recordChirps(chirps)
+
+	resp := chirpsPage{Chirps: chirps}
+	if n := len(rows); n > 0 {
+		resp.NextCursor = page.nextCursor(n, rows[n-1].BookmarkedAt, rows[n-1].Chirp.ID)
+	}
+	setCursorPageHeaders(w, r, resp.NextCursor, total)
+	respondWithJSON(w, http.StatusOK, resp)
+}
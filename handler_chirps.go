@@ -0,0 +1,547 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+// defaultMaxChirpLength is the chirp length limit, in characters, unless
+// CHIRP_MAX_LENGTH overrides it.
+const defaultMaxChirpLength = 140
+
+var errChirpTooLong = errors.New("Chirp is too long")
+
+type Chirp struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Body      string    `json:"body"`
+	UserID    uuid.UUID `json:"user_id"`
+	// Author summarizes the user behind UserID. It is filled in by
+	// loadChirpDetails.
+	Author *UserSummary `json:"author"`
+	// InReplyToID is the chirp this one replies to, e.g. the previous chirp
+	// of a thread.
+	InReplyToID *uuid.UUID `json:"in_reply_to_id"`
+	ReplyPolicy string     `json:"reply_policy"`
+	// Language is the detected ISO 639-1 code, or "und" when unknown.
+	Language string `json:"language"`
+	// DeletedAt is only ever set on the admin listing of deleted chirps.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+
+	LikeCount     int64 `json:"like_count"`
+	LikedByMe     bool  `json:"liked_by_me"`
+	RechirpCount  int64 `json:"rechirp_count"`
+	RechirpedByMe bool  `json:"rechirped_by_me"`
+	ViewCount     int64 `json:"view_count"`
+
+	Media       []MediaAttachment `json:"media"`
+	LinkPreview *LinkPreview      `json:"link_preview"`
+}
+
+// chirpsPage is one page of a paginated chirp listing.
+type chirpsPage struct {
+	Chirps     []Chirp `json:"chirps"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+func chirpFromDB(chirp database.Chirp) Chirp {
+	c := Chirp{
+		ID:          chirp.ID,
+		CreatedAt:   chirp.CreatedAt,
+		UpdatedAt:   chirp.UpdatedAt,
+		Body:        chirp.Body,
+		UserID:      chirp.UserID,
+		ViewCount:   chirp.ViewCount,
+		ReplyPolicy: chirp.ReplyPolicy,
+		Language:    chirp.Language,
+		Media:       []MediaAttachment{},
+	}
+	if chirp.InReplyToID.Valid {
+		c.InReplyToID = &chirp.InReplyToID.UUID
+	}
+	if chirp.DeletedAt.Valid {
+		c.DeletedAt = &chirp.DeletedAt.Time
+	}
+	return c
+}
+
+// chirpsFromDB converts chirps and loads their details, for listing handlers.
+func (cfg *apiConfig) chirpsFromDB(ctx context.Context, rows []database.Chirp) ([]Chirp, error) {
+	chirps := make([]Chirp, 0, len(rows))
+	for _, row := range rows {
+		chirps = append(chirps, chirpFromDB(row))
+	}
+	return chirps, cfg.loadChirpDetails(ctx, chirps)
+}
+
+// loadChirpDetails fills in everything about chirps that lives outside the
+// chirps table: authors, engagement stats, media attachments and link
+// previews.
+func (cfg *apiConfig) loadChirpDetails(ctx context.Context, chirps []Chirp) error {
+	err := cfg.loadChirpAuthors(ctx, chirps)
+	if err != nil {
+		return err
+	}
+	err = cfg.loadChirpStats(ctx, chirps)
+	if err != nil {
+		return err
+	}
+	err = cfg.loadChirpMedia(ctx, chirps)
+	if err != nil {
+		return err
+	}
+	return cfg.loadLinkPreviews(ctx, chirps)
+}
+
+// loadChirpAuthors fills in the author summary of chirps in place.
+func (cfg *apiConfig) loadChirpAuthors(ctx context.Context, chirps []Chirp) error {
+	if len(chirps) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(chirps))
+	for _, chirp := range chirps {
+		ids = append(ids, chirp.UserID)
+	}
+
+	rows, err := cfg.dbQueries.ListUsersByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	authors := make(map[uuid.UUID]UserSummary, len(rows))
+	for _, row := range rows {
+		authors[row.ID] = cfg.userSummaryFromDB(row)
+	}
+	for i := range chirps {
+		if author, ok := authors[chirps[i].UserID]; ok {
+			chirps[i].Author = &author
+		}
+	}
+	return nil
+}
+
+// respondWithChirp writes a single chirp together with its details.
+func (cfg *apiConfig) respondWithChirp(w http.ResponseWriter, r *http.Request, code int, dbChirp database.Chirp) {
+	chirps, err := cfg.chirpsFromDB(r.Context(), []database.Chirp{dbChirp})
+	if err != nil {
+		log.Printf("Error loading chirp details: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp")
+		return
+	}
+	respondWithJSON(w, code, chirps[0])
+}
+
+// chirpLength counts characters rather than bytes, so that emoji and
+// non-Latin scripts get the same limit as ASCII.
+func chirpLength(body string) int {
+	return utf8.RuneCountInString(body)
+}
+
+// validateChirp checks the chirp's length and returns its body with banned
+// words censored.
+func validateChirp(body string, maxLength int) (string, error) {
+	if chirpLength(body) > maxLength {
+		return "", errChirpTooLong
+	}
+	cleaned, _ := processWords(body)
+	return cleaned, nil
+}
+
+func (cfg *apiConfig) createChirpHandler(w http.ResponseWriter, r *http.Request) {
+	type chirpParameters struct {
+		Body        string     `json:"body"`
+		PublishAt   *time.Time `json:"publish_at"`
+		InReplyToID *uuid.UUID `json:"in_reply_to_id"`
+		ReplyPolicy string     `json:"reply_policy"`
+	}
+
+	userID, _ := userIDFromContext(r.Context())
+
+	params := chirpParameters{}
+	if !decodeParameters(w, r, &params) {
+		return
+	}
+
+	isChirpyRed, ok := cfg.isChirpyRed(w, r, userID)
+	if !ok {
+		return
+	}
+
+	cleaned, err := validateChirp(params.Body, cfg.maxChirpLengthFor(isChirpyRed))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if params.ReplyPolicy == "" {
+		params.ReplyPolicy = replyPolicyEveryone
+	}
+	if !slices.Contains(replyPolicies, params.ReplyPolicy) {
+		respondWithError(w, http.StatusBadRequest, "Invalid reply_policy")
+		return
+	}
+
+	scheduled := params.PublishAt != nil && params.PublishAt.After(time.Now())
+	if scheduled && (params.InReplyToID != nil || params.ReplyPolicy != replyPolicyEveryone) {
+		respondWithError(w, http.StatusBadRequest, "Scheduled chirps can't be replies or restrict replies")
+		return
+	}
+
+	var inReplyTo uuid.NullUUID
+	if params.InReplyToID != nil {
+		parent, err := cfg.dbQueries.GetChirp(r.Context(), *params.InReplyToID)
+		if err == nil {
+			err = cfg.checkChirpVisible(r.Context(), parent)
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Chirp being replied to not found")
+			return
+		}
+		if err != nil {
+			log.Printf("Error getting chirp: %s", err)
+			respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp")
+			return
+		}
+		err = cfg.checkReplyPolicy(r.Context(), parent, userID)
+		if errors.Is(err, errReplyNotAllowed) {
+			respondWithError(w, http.StatusForbidden, replyPolicyMessages[parent.ReplyPolicy])
+			return
+		}
+		if errors.Is(err, errReplyBlocked) {
+			respondWithError(w, http.StatusForbidden, "You can't reply to this chirp")
+			return
+		}
+		if err != nil {
+			log.Printf("Error checking reply policy: %s", err)
+			respondWithError(w, http.StatusInternalServerError, "Couldn't create chirp")
+			return
+		}
+		inReplyTo = uuid.NullUUID{UUID: parent.ID, Valid: true}
+	}
+
+	// The quota is only charged once the chirp is known to be valid.
+	if !cfg.allowChirps(w, userID, isChirpyRed, 1) {
+		return
+	}
+
+	if scheduled {
+		scheduled, err := cfg.dbQueries.CreateScheduledChirp(r.Context(), database.CreateScheduledChirpParams{
+			Body:      cleaned,
+			UserID:    userID,
+			PublishAt: params.PublishAt.UTC(),
+		})
+		if err != nil {
+			log.Printf("Error scheduling chirp: %s", err)
+			respondWithError(w, http.StatusInternalServerError, "Couldn't schedule chirp")
+			return
+		}
+		respondWithJSON(w, http.StatusAccepted, scheduledChirpFromDB(scheduled))
+		return
+	}
+
+	chirp, err := cfg.createChirp(r.Context(), database.CreateChirpParams{
+		Body:        cleaned,
+		UserID:      userID,
+		InReplyToID: inReplyTo,
+		ReplyPolicy: params.ReplyPolicy,
+	})
+	if err != nil {
+		log.Printf("Error creating chirp: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create chirp")
+		return
+	}
+
+	cfg.respondWithChirp(w, r, http.StatusCreated, chirp)
+}
+
+// createChirp stores a validated chirp together with its hashtags and
+// mentions in one transaction.
+func (cfg *apiConfig) createChirp(ctx context.Context, params database.CreateChirpParams) (database.Chirp, error) {
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	chirp, err := cfg.insertChirp(ctx, qtx, params)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	cfg.linkPreviews.enqueue(chirp)
+	return chirp, nil
+}
+
+// insertChirp stores a chirp, tagged with its detected language, and indexes
+// its hashtags and mentions. q should be bound to a transaction so the chirp
+// is never visible unindexed.
+func (cfg *apiConfig) insertChirp(ctx context.Context, q *database.Queries, params database.CreateChirpParams) (database.Chirp, error) {
+	if params.ReplyPolicy == "" {
+		params.ReplyPolicy = replyPolicyEveryone
+	}
+	params.Language = cfg.languages.Detect(params.Body)
+	chirp, err := q.CreateChirp(ctx, params)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	err = indexChirpEntities(ctx, q, chirp)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	return chirp, nil
+}
+
+// listChirpsHandler returns all chirps, or only those written by
+// ?author_id= when given, optionally narrowed to one ?lang=. ?sort=desc lists
+// newest first; the default is oldest first. With ?limit= or ?after= the
+// response is a page wrapped in {"chirps": [...], "next_cursor": "..."};
+// without them it is a plain array of every chirp, as older clients expect.
+func (cfg *apiConfig) listChirpsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	sortOrder := query.Get("sort")
+	if sortOrder == "" {
+		sortOrder = "asc"
+	}
+	if sortOrder != "asc" && sortOrder != "desc" {
+		respondWithError(w, http.StatusBadRequest, "sort must be asc or desc")
+		return
+	}
+
+	var authorID uuid.NullUUID
+	if v := query.Get("author_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid author_id")
+			return
+		}
+		authorID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	language, err := parseLanguage(query.Get("lang"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := parsePageParams(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows, err := cfg.listChirps(r.Context(), authorID, language, sortOrder == "desc", page)
+	if err != nil {
+		log.Printf("Error listing chirps: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list chirps")
+		return
+	}
+
+	chirps, err := cfg.chirpsFromDB(r.Context(), rows)
+	if err != nil {
+		log.Printf("Error loading chirp details: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list chirps")
+		return
+	}
+	cfg.views.recordChirps(chirps)
+	if !page.paginated {
+		respondWithJSON(w, http.StatusOK, chirps)
+		return
+	}
+
+	total, err := cfg.countChirps(r.Context(), authorID, language)
+	if err != nil {
+		log.Printf("Error counting chirps: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list chirps")
+		return
+	}
+
+	resp := chirpsPage{Chirps: chirps}
+	if n := len(rows); n > 0 {
+		resp.NextCursor = page.nextCursor(n, rows[n-1].CreatedAt, rows[n-1].ID)
+	}
+	setCursorPageHeaders(w, r, resp.NextCursor, total)
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// listChirps picks the keyset query matching the filter and sort order.
+func (cfg *apiConfig) listChirps(ctx context.Context, authorID uuid.NullUUID, language sql.NullString, desc bool, page pageParams) ([]database.Chirp, error) {
+	viewer := viewerFromContext(ctx)
+	switch {
+	case authorID.Valid && desc:
+		return cfg.dbQueries.ListChirpsByAuthorDesc(ctx, database.ListChirpsByAuthorDescParams{
+			UserID:         authorID.UUID,
+			Language:       language,
+			ViewerID:       viewer,
+			AfterCreatedAt: page.afterCreatedAt,
+			AfterID:        page.afterID,
+			MaxResults:     page.maxResults(),
+		})
+	case authorID.Valid:
+		return cfg.dbQueries.ListChirpsByAuthor(ctx, database.ListChirpsByAuthorParams{
+			UserID:         authorID.UUID,
+			Language:       language,
+			ViewerID:       viewer,
+			AfterCreatedAt: page.afterCreatedAt,
+			AfterID:        page.afterID,
+			MaxResults:     page.maxResults(),
+		})
+	case desc:
+		return cfg.dbQueries.ListChirpsDesc(ctx, database.ListChirpsDescParams{
+			Language:       language,
+			ViewerID:       viewer,
+			AfterCreatedAt: page.afterCreatedAt,
+			AfterID:        page.afterID,
+			MaxResults:     page.maxResults(),
+		})
+	default:
+		return cfg.dbQueries.ListChirps(ctx, database.ListChirpsParams{
+			Language:       language,
+			ViewerID:       viewer,
+			AfterCreatedAt: page.afterCreatedAt,
+			AfterID:        page.afterID,
+			MaxResults:     page.maxResults(),
+		})
+	}
+}
+
+// countChirps counts the chirps listChirps pages through.
+func (cfg *apiConfig) countChirps(ctx context.Context, authorID uuid.NullUUID, language sql.NullString) (int64, error) {
+	viewer := viewerFromContext(ctx)
+	if authorID.Valid {
+		return cfg.dbQueries.CountChirpsByAuthor(ctx, database.CountChirpsByAuthorParams{
+			UserID:   authorID.UUID,
+			Language: language,
+			ViewerID: viewer,
+		})
+	}
+	return cfg.dbQueries.CountChirps(ctx, database.CountChirpsParams{
+		Language: language,
+		ViewerID: viewer,
+	})
+}
+
+func (cfg *apiConfig) getChirpHandler(w http.ResponseWriter, r *http.Request) {
+	chirp, ok := cfg.lookupChirp(w, r)
+	if !ok {
+		return
+	}
+
+	cfg.views.record(chirp.ID)
+	cfg.respondWithChirp(w, r, http.StatusOK, chirp)
+}
+
+// lookupChirp loads the chirp named by the {chirpID} path value. Chirps of
+// private accounts the requester can't see are reported as not found. On
+// failure it writes a 400, 404 or 500 response and returns false.
+func (cfg *apiConfig) lookupChirp(w http.ResponseWriter, r *http.Request) (database.Chirp, bool) {
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid chirp ID")
+		return database.Chirp{}, false
+	}
+
+	chirp, err := cfg.dbQueries.GetChirp(r.Context(), chirpID)
+	if err == nil {
+		err = cfg.checkChirpVisible(r.Context(), chirp)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Chirp not found")
+		return database.Chirp{}, false
+	}
+	if err != nil {
+		log.Printf("Error getting chirp: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp")
+		return database.Chirp{}, false
+	}
+	return chirp, true
+}
+
+// checkChirpVisible returns sql.ErrNoRows if the chirp's author is a private
+// account the requester can't see.
+func (cfg *apiConfig) checkChirpVisible(ctx context.Context, chirp database.Chirp) error {
+	author, err := cfg.dbQueries.GetUserByID(ctx, chirp.UserID)
+	if err != nil {
+		return err
+	}
+	visible, err := cfg.canViewChirpsOf(ctx, author)
+	if err != nil {
+		return err
+	}
+	if !visible {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// getOwnedChirp is lookupChirp that additionally requires the authenticated
+// user to be the chirp's author, responding 403 otherwise.
+func (cfg *apiConfig) getOwnedChirp(w http.ResponseWriter, r *http.Request) (database.Chirp, bool) {
+	userID, _ := userIDFromContext(r.Context())
+
+	chirp, ok := cfg.lookupChirp(w, r)
+	if !ok {
+		return database.Chirp{}, false
+	}
+	if chirp.UserID != userID {
+		respondWithError(w, http.StatusForbidden, "You are not the author of this chirp")
+		return database.Chirp{}, false
+	}
+	return chirp, true
+}
+
+// deleteChirpHandler soft-deletes a chirp: it disappears from every listing
+// but can be restored by its author within the restore window.
+func (cfg *apiConfig) deleteChirpHandler(w http.ResponseWriter, r *http.Request) {
+	chirp, ok := cfg.getOwnedChirp(w, r)
+	if !ok {
+		return
+	}
+
+	err := cfg.deleteChirp(r.Context(), chirp.ID)
+	if err != nil {
+		log.Printf("Error deleting chirp: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't delete chirp")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// deleteChirp marks a chirp deleted and unpins it from its author's profile.
+func (cfg *apiConfig) deleteChirp(ctx context.Context, chirpID uuid.UUID) error {
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	err = qtx.SoftDeleteChirp(ctx, chirpID)
+	if err != nil {
+		return err
+	}
+	err = qtx.UnpinChirp(ctx, uuid.NullUUID{UUID: chirpID, Valid: true})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+// createThreadHandler posts several chirps at once as a thread: each chirp
+// replies to the one before it. Either every chirp is created or none is.
+func (cfg *apiConfig) createThreadHandler(w http.ResponseWriter, r *http.Request) {
+	type threadParameters struct {
+		Bodies []string `json:"bodies" validate:"required,max=25"`
+	}
+
+	userID, _ := userIDFromContext(r.Context())
+
+	params := threadParameters{}
+	if !decodeParameters(w, r, &params) {
+		return
+	}
+
+	isChirpyRed, ok := cfg.isChirpyRed(w, r, userID)
+	if !ok {
+		return
+	}
+
+	cleaned := make([]string, 0, len(params.Bodies))
+	for i, body := range params.Bodies {
+		c, err := validateChirp(body, cfg.maxChirpLengthFor(isChirpyRed))
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Chirp %d: %s", i+1, err))
+			return
+		}
+		cleaned = append(cleaned, c)
+	}
+	if !cfg.allowChirps(w, userID, isChirpyRed, len(cleaned)) {
+		return
+	}
+
+	thread, err := cfg.createThread(r.Context(), userID, cleaned)
+	if err != nil {
+		log.Printf("Error creating thread: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create thread")
+		return
+	}
+
+	chirps := make([]Chirp, 0, len(thread))
+	for _, chirp := range thread {
+		chirps = append(chirps, chirpFromDB(chirp))
+	}
+	respondWithJSON(w, http.StatusCreated, chirps)
+}
+
+func (cfg *apiConfig) createThread(ctx context.Context, userID uuid.UUID, bodies []string) ([]database.Chirp, error) {
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	thread := make([]database.Chirp, 0, len(bodies))
+	var inReplyTo uuid.NullUUID
+	for _, body := range bodies {
+		chirp, err := cfg.insertChirp(ctx, qtx, database.CreateChirpParams{
+			Body:        body,
+			UserID:      userID,
+			InReplyToID: inReplyTo,
+		})
+		if err != nil {
+			return nil, err
+		}
+		thread = append(thread, chirp)
+		inReplyTo = uuid.NullUUID{UUID: chirp.ID, Valid: true}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	for _, chirp := range thread {
+		cfg.linkPreviews.enqueue(chirp)
+	}
+	return thread, nil
+}
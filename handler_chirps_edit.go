@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+// ChirpRevision is a previous body of an edited chirp.
+type ChirpRevision struct {
+	Body       string    `json:"body"`
+	CreatedAt  time.Time `json:"created_at"`
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
+func (cfg *apiConfig) updateChirpHandler(w http.ResponseWriter, r *http.Request) {
+	type chirpParameters struct {
+		Body string `json:"body"`
+	}
+
+	chirp, ok := cfg.getOwnedChirp(w, r)
+	if !ok {
+		return
+	}
+
+	isChirpyRed, ok := cfg.isChirpyRed(w, r, chirp.UserID)
+	if !ok {
+		return
+	}
+	if cfg.redPerks.editingRequiresRed && !isChirpyRed {
+		respondWithError(w, http.StatusForbidden, "Editing chirps requires Chirpy Red")
+		return
+	}
+
+	params := chirpParameters{}
+	if !decodeParameters(w, r, &params) {
+		return
+	}
+
+	cleaned, err := validateChirp(params.Body, cfg.maxChirpLengthFor(isChirpyRed))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	updated, err := cfg.editChirp(r.Context(), chirp, cleaned)
+	if err != nil {
+		log.Printf("Error updating chirp: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't update chirp")
+		return
+	}
+
+	cfg.respondWithChirp(w, r, http.StatusOK, updated)
+}
+
+// editChirp archives the chirp's current body as a revision and replaces it,
+// re-indexing its hashtags and mentions and dropping its link preview, in
+// one transaction. A new preview is fetched in the background.
+func (cfg *apiConfig) editChirp(ctx context.Context, chirp database.Chirp, body string) (database.Chirp, error) {
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	err = qtx.CreateChirpRevision(ctx, database.CreateChirpRevisionParams{
+		ChirpID:   chirp.ID,
+		Body:      chirp.Body,
+		CreatedAt: chirp.UpdatedAt,
+	})
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	updated, err := qtx.UpdateChirpBody(ctx, database.UpdateChirpBodyParams{
+		ID:       chirp.ID,
+		Body:     body,
+		Language: cfg.languages.Detect(body),
+	})
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	err = clearChirpEntities(ctx, qtx, chirp)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	err = indexChirpEntities(ctx, qtx, updated)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	err = qtx.DeleteLinkPreview(ctx, chirp.ID)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	cfg.linkPreviews.enqueue(updated)
+	return updated, nil
+}
+
+func (cfg *apiConfig) chirpHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	chirp, ok := cfg.lookupChirp(w, r)
+	if !ok {
+		return
+	}
+
+	rows, err := cfg.dbQueries.ListChirpRevisions(r.Context(), chirp.ID)
+	if err != nil {
+		log.Printf("Error listing chirp revisions: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp history")
+		return
+	}
+
+	revisions := make([]ChirpRevision, 0, len(rows))
+	for _, row := range rows {
+		revisions = append(revisions, ChirpRevision{
+			Body:       row.Body,
+			CreatedAt:  row.CreatedAt,
+			ReplacedAt: row.ReplacedAt,
+		})
+	}
+	respondWithJSON(w, http.StatusOK, revisions)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultRestoreWindow = 30 * 24 * time.Hour
+
+	defaultDeletedChirpsLimit = 50
+	maxDeletedChirpsLimit     = 500
+)
+
+// restoreChirpHandler undoes the soft delete of one of the authenticated
+// user's chirps, as long as it was deleted within the restore window.
+func (cfg *apiConfig) restoreChirpHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid chirp ID")
+		return
+	}
+
+	chirp, err := cfg.dbQueries.GetChirpIncludingDeleted(r.Context(), chirpID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Chirp not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error getting chirp: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp")
+		return
+	}
+	if chirp.UserID != userID {
+		respondWithError(w, http.StatusForbidden, "You are not the author of this chirp")
+		return
+	}
+	if chirp.HiddenAt.Valid {
+		respondWithError(w, http.StatusForbidden, "Chirp was removed by a moderator")
+		return
+	}
+	if !chirp.DeletedAt.Valid {
+		respondWithError(w, http.StatusConflict, "Chirp is not deleted")
+		return
+	}
+	if time.Since(chirp.DeletedAt.Time) > cfg.restoreWindow {
+		respondWithError(w, http.StatusGone, "Chirp can no longer be restored")
+		return
+	}
+
+	err = cfg.dbQueries.RestoreChirp(r.Context(), chirp.ID)
+	if err != nil {
+		log.Printf("Error restoring chirp: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't restore chirp")
+		return
+	}
+
+	chirp.DeletedAt = sql.NullTime{}
+	cfg.respondWithChirp(w, r, http.StatusOK, chirp)
+}
+
+// listDeletedChirpsHandler shows admins the most recently deleted chirps,
+// including those past the restore window.
+func (cfg *apiConfig) listDeletedChirpsHandler(w http.ResponseWriter, r *http.Request) {
+	limit := defaultDeletedChirpsLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxDeletedChirpsLimit {
+			respondWithError(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		limit = n
+	}
+
+	rows, err := cfg.dbQueries.ListDeletedChirps(r.Context(), int32(limit))
+	if err != nil {
+		log.Printf("Error listing deleted chirps: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list deleted chirps")
+		return
+	}
+
+	chirps := make([]Chirp, 0, len(rows))
+	for _, row := range rows {
+		chirps = append(chirps, chirpFromDB(row))
+	}
+	respondWithJSON(w, http.StatusOK, chirps)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+// searchChirpsHandler finds chirps matching ?q= (web search syntax: quoted
+// phrases, "or", -exclusions), best matches first. Relevance ranks can't be
+// used as a stable keyset, so results are paged with ?limit= and ?offset=.
+// ?lang= restricts results to one detected language.
+func (cfg *apiConfig) searchChirpsHandler(w http.ResponseWriter, r *http.Request) {
+	type searchResult struct {
+		Chirp
+		Rank float32 `json:"rank"`
+	}
+	type searchResponse struct {
+		Chirps     []searchResult `json:"chirps"`
+		NextOffset *int           `json:"next_offset"`
+	}
+
+	query := r.URL.Query()
+	q := query.Get("q")
+	if q == "" {
+		respondWithError(w, http.StatusBadRequest, "q is required")
+		return
+	}
+
+	language, err := parseLanguage(query.Get("lang"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := parsePageParams(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	offset := 0
+	if v := query.Get("offset"); v != "" {
+		offset, err = strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			respondWithError(w, http.StatusBadRequest, "Invalid offset")
+			return
+		}
+	}
+
+	rows, err := cfg.dbQueries.SearchChirps(r.Context(), database.SearchChirpsParams{
+		Query:      q,
+		Language:   language,
+		ViewerID:   viewerFromContext(r.Context()),
+		MaxResults: page.limit,
+		Skip:       int32(offset),
+	})
+	if err != nil {
+		log.Printf("Error searching chirps: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't search chirps")
+		return
+	}
+
+	total, err := cfg.dbQueries.CountSearchChirps(r.Context(), database.CountSearchChirpsParams{
+		Query:    q,
+		Language: language,
+		ViewerID: viewerFromContext(r.Context()),
+	})
+	if err != nil {
+		log.Printf("Error counting chirp search results: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't search chirps")
+		return
+	}
+
+	chirps := make([]Chirp, 0, len(rows))
+	for _, row := range rows {
+		chirps = append(chirps, chirpFromDB(row.Chirp))
+	}
+	err = cfg.loadChirpDetails(r.Context(), chirps)
+	if err != nil {
+		log.Printf("Error loading chirp details: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't search chirps")
+		return
+	}
+
+	cfg.views.recordChirps(chirps)
+
+	resp := searchResponse{Chirps: make([]searchResult, 0, len(rows))}
+	for i, row := range rows {
+		resp.Chirps = append(resp.Chirps, searchResult{
+			Chirp: chirps[i],
+			Rank:  row.Rank,
+		})
+	}
+	if len(rows) == int(page.limit) {
+		next := offset + len(rows)
+		resp.NextOffset = &next
+	}
+	setOffsetPageHeaders(w, r, offset, int(page.limit), total)
+	respondWithJSON(w, http.StatusOK, resp)
+}
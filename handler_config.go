@@ -0,0 +1,22 @@
+package main
+
+import "net/http"
+
+// configHandler tells clients the server's content limits, so composers can
+// enforce them before the user hits send. Chirpy Red members get
+// max_red_chirp_length instead of max_chirp_length.
+func (cfg *apiConfig) configHandler(w http.ResponseWriter, r *http.Request) {
+	type clientConfig struct {
+		MaxChirpLength    int   `json:"max_chirp_length"`
+		MaxRedChirpLength int   `json:"max_red_chirp_length"`
+		MaxMediaPerChirp  int   `json:"max_media_per_chirp"`
+		MaxMediaBytes     int64 `json:"max_media_bytes"`
+	}
+
+	respondWithJSON(w, http.StatusOK, clientConfig{
+		MaxChirpLength:    cfg.maxChirpLength,
+		MaxRedChirpLength: cfg.maxChirpLengthFor(true),
+		MaxMediaPerChirp:  maxMediaPerChirp,
+		MaxMediaBytes:     cfg.mediaMaxBytes,
+	})
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+// KnownDevice is an IP address and user agent pair a user has logged in from.
+type KnownDevice struct {
+	ID          uuid.UUID `json:"id"`
+	IPAddress   string    `json:"ip_address"`
+	UserAgent   string    `json:"user_agent"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+}
+
+func knownDeviceFromDB(device database.KnownDevice) KnownDevice {
+	return KnownDevice{
+		ID:          device.ID,
+		IPAddress:   device.IpAddress,
+		UserAgent:   device.UserAgent,
+		FirstSeenAt: device.FirstSeenAt,
+		LastSeenAt:  device.LastSeenAt,
+	}
+}
+
+// checkNewDevice records the device a user just logged in from and notifies
+// them if it hasn't been seen before. Errors are only logged so that a
+// notification problem never blocks a login.
+func (cfg *apiConfig) checkNewDevice(ctx context.Context, user database.User, ip, userAgent string) {
+	isNew, err := cfg.dbQueries.UpsertKnownDevice(ctx, database.UpsertKnownDeviceParams{
+		UserID:    user.ID,
+		IpAddress: ip,
+		UserAgent: userAgent,
+	})
+	if err != nil {
+		log.Printf("Error recording known device: %s", err)
+		return
+	}
+	if !isNew {
+		return
+	}
+
+	body := fmt.Sprintf(
+		"Your Chirpy account was just accessed from a new device.\n\nIP address: %s\nBrowser: %s\n\nIf this wasn't you, reset your password and log out of all sessions.",
+		ip, userAgent,
+	)
+	err = cfg.notify(ctx, user, notificationKindNewDevice, "New login to your Chirpy account", body)
+	if err != nil {
+		log.Printf("Error sending new device notification: %s", err)
+	}
+}
+
+func (cfg *apiConfig) listDevicesHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	rows, err := cfg.dbQueries.ListKnownDevicesForUser(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing known devices: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list devices")
+		return
+	}
+
+	devices := make([]KnownDevice, 0, len(rows))
+	for _, row := range rows {
+		devices = append(devices, knownDeviceFromDB(row))
+	}
+	respondWithJSON(w, http.StatusOK, devices)
+}
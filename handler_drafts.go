@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+// Draft is an unpublished chirp body. Drafts are only visible to their
+// author.
+type Draft struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Body      string    `json:"body"`
+}
+
+func draftFromDB(draft database.Draft) Draft {
+	return Draft{
+		ID:        draft.ID,
+		CreatedAt: draft.CreatedAt,
+		UpdatedAt: draft.UpdatedAt,
+		Body:      draft.Body,
+	}
+}
+
+type draftParameters struct {
+	Body string `json:"body"`
+}
+
+func (cfg *apiConfig) createDraftHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	params := draftParameters{}
+	if !decodeParameters(w, r, &params) {
+		return
+	}
+
+	isChirpyRed, ok := cfg.isChirpyRed(w, r, userID)
+	if !ok {
+		return
+	}
+	if chirpLength(params.Body) > cfg.maxChirpLengthFor(isChirpyRed) {
+		respondWithError(w, http.StatusBadRequest, errChirpTooLong.Error())
+		return
+	}
+
+	draft, err := cfg.dbQueries.CreateDraft(r.Context(), database.CreateDraftParams{
+		Body:   params.Body,
+		UserID: userID,
+	})
+	if err != nil {
+		log.Printf("Error creating draft: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create draft")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, draftFromDB(draft))
+}
+
+func (cfg *apiConfig) listDraftsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	rows, err := cfg.dbQueries.ListDrafts(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing drafts: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list drafts")
+		return
+	}
+
+	drafts := make([]Draft, 0, len(rows))
+	for _, row := range rows {
+		drafts = append(drafts, draftFromDB(row))
+	}
+	respondWithJSON(w, http.StatusOK, drafts)
+}
+
+func (cfg *apiConfig) getDraftHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	draftID, err := uuid.Parse(r.PathValue("draftID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid draft ID")
+		return
+	}
+
+	draft, err := cfg.dbQueries.GetDraft(r.Context(), database.GetDraftParams{
+		ID:     draftID,
+		UserID: userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Draft not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error getting draft: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get draft")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, draftFromDB(draft))
+}
+
+func (cfg *apiConfig) updateDraftHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	draftID, err := uuid.Parse(r.PathValue("draftID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid draft ID")
+		return
+	}
+
+	params := draftParameters{}
+	if !decodeParameters(w, r, &params) {
+		return
+	}
+
+	isChirpyRed, ok := cfg.isChirpyRed(w, r, userID)
+	if !ok {
+		return
+	}
+	if chirpLength(params.Body) > cfg.maxChirpLengthFor(isChirpyRed) {
+		respondWithError(w, http.StatusBadRequest, errChirpTooLong.Error())
+		return
+	}
+
+	draft, err := cfg.dbQueries.UpdateDraft(r.Context(), database.UpdateDraftParams{
+		ID:     draftID,
+		UserID: userID,
+		Body:   params.Body,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Draft not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error updating draft: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't update draft")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, draftFromDB(draft))
+}
+
+func (cfg *apiConfig) deleteDraftHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	draftID, err := uuid.Parse(r.PathValue("draftID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid draft ID")
+		return
+	}
+
+	deleted, err := cfg.dbQueries.DeleteDraft(r.Context(), database.DeleteDraftParams{
+		ID:     draftID,
+		UserID: userID,
+	})
+	if err != nil {
+		log.Printf("Error deleting draft: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't delete draft")
+		return
+	}
+	if deleted == 0 {
+		respondWithError(w, http.StatusNotFound, "Draft not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// publishDraftHandler turns a draft into a chirp. The draft is removed in
+// the same transaction, so it is published at most once even if the client
+// retries.
+func (cfg *apiConfig) publishDraftHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	draftID, err := uuid.Parse(r.PathValue("draftID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid draft ID")
+		return
+	}
+
+	isChirpyRed, ok := cfg.isChirpyRed(w, r, userID)
+	if !ok {
+		return
+	}
+	if !cfg.allowChirps(w, userID, isChirpyRed, 1) {
+		return
+	}
+
+	chirp, err := cfg.publishDraft(r.Context(), userID, draftID, cfg.maxChirpLengthFor(isChirpyRed))
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Draft not found")
+		return
+	}
+	if errors.Is(err, errChirpTooLong) {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Error publishing draft: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't publish draft")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, chirpFromDB(chirp))
+}
+
+func (cfg *apiConfig) publishDraft(ctx context.Context, userID, draftID uuid.UUID, maxLength int) (database.Chirp, error) {
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	draft, err := qtx.TakeDraft(ctx, database.TakeDraftParams{
+		ID:     draftID,
+		UserID: userID,
+	})
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	cleaned, err := validateChirp(draft.Body, maxLength)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	chirp, err := cfg.insertChirp(ctx, qtx, database.CreateChirpParams{
+		Body:   cleaned,
+		UserID: userID,
+	})
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	cfg.linkPreviews.enqueue(chirp)
+	return chirp, nil
+}
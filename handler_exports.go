@@ -0,0 +1,296 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+const (
+	exportStatusPending = "pending"
+	exportStatusReady   = "ready"
+	exportStatusFailed  = "failed"
+
+	// exportTimeout bounds how long building one archive may take.
+	exportTimeout = 10 * time.Minute
+	// exportRecordTimeout bounds recording the outcome of an export, which
+	// still happens when shutdown has cancelled building it.
+	exportRecordTimeout = 5 * time.Second
+)
+
+// Export is a user's request for an archive of their data.
+type Export struct {
+	ID          uuid.UUID `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Status      string    `json:"status"`
+	DownloadURL string    `json:"download_url,omitempty"`
+}
+
+func exportFromDB(export database.Export) Export {
+	e := Export{
+		ID:        export.ID,
+		CreatedAt: export.CreatedAt,
+		UpdatedAt: export.UpdatedAt,
+		Status:    export.Status,
+	}
+	if export.Status == exportStatusReady {
+		e.DownloadURL = "/api/v1/users/me/exports/" + export.ID.String() + "/download"
+	}
+	return e
+}
+
+// createExportHandler starts building a zip archive of the authenticated
+// user's chirps, media, likes and follows. The response is 202; clients poll the
+// export until it is ready and then download it.
+func (cfg *apiConfig) createExportHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	export, err := cfg.dbQueries.CreateExport(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error creating export: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create export")
+		return
+	}
+
+	queued := cfg.tasks.enqueue(func(ctx context.Context) { cfg.runExport(ctx, export) })
+	if !queued {
+		err = cfg.dbQueries.FailExport(r.Context(), database.FailExportParams{
+			ID:    export.ID,
+			Error: sql.NullString{String: "server busy", Valid: true},
+		})
+		if err != nil {
+			log.Printf("Error recording failed export %s: %s", export.ID, err)
+		}
+		respondWithError(w, http.StatusServiceUnavailable, "Couldn't start export, try again later")
+		return
+	}
+
+	respondWithJSON(w, http.StatusAccepted, exportFromDB(export))
+}
+
+func (cfg *apiConfig) getExportHandler(w http.ResponseWriter, r *http.Request) {
+	export, ok := cfg.lookupExport(w, r)
+	if !ok {
+		return
+	}
+	respondWithJSON(w, http.StatusOK, exportFromDB(export))
+}
+
+func (cfg *apiConfig) downloadExportHandler(w http.ResponseWriter, r *http.Request) {
+	export, ok := cfg.lookupExport(w, r)
+	if !ok {
+		return
+	}
+	if export.Status != exportStatusReady {
+		respondWithError(w, http.StatusConflict, "Export is not ready")
+		return
+	}
+
+	f, err := cfg.exportStore.Open(r.Context(), export.StorageKey.String)
+	if err != nil {
+		log.Printf("Error opening export: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't open export")
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="chirpy-export.zip"`)
+	w.WriteHeader(http.StatusOK)
+	_, err = io.Copy(w, f)
+	if err != nil {
+		log.Printf("Error sending export: %s", err)
+	}
+}
+
+// lookupExport loads the authenticated user's export named by the
+// {exportID} path value, writing an error response on failure.
+func (cfg *apiConfig) lookupExport(w http.ResponseWriter, r *http.Request) (database.Export, bool) {
+	userID, _ := userIDFromContext(r.Context())
+
+	exportID, err := uuid.Parse(r.PathValue("exportID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid export ID")
+		return database.Export{}, false
+	}
+
+	export, err := cfg.dbQueries.GetExport(r.Context(), database.GetExportParams{
+		ID:     exportID,
+		UserID: userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Export not found")
+		return database.Export{}, false
+	}
+	if err != nil {
+		log.Printf("Error getting export: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get export")
+		return database.Export{}, false
+	}
+	return export, true
+}
+
+// runExport builds the archive for export as a background task and records
+// the outcome. If shutdown cancels ctx first, the export is marked failed
+// rather than left pending.
+func (cfg *apiConfig) runExport(ctx context.Context, export database.Export) {
+	buildCtx, cancel := context.WithTimeout(ctx, exportTimeout)
+	defer cancel()
+	key, err := cfg.buildExport(buildCtx, export.UserID)
+
+	ctx, cancel = context.WithTimeout(context.WithoutCancel(ctx), exportRecordTimeout)
+	defer cancel()
+	if err != nil {
+		log.Printf("Error building export %s: %s", export.ID, err)
+		err = cfg.dbQueries.FailExport(ctx, database.FailExportParams{
+			ID:    export.ID,
+			Error: sql.NullString{String: err.Error(), Valid: true},
+		})
+		if err != nil {
+			log.Printf("Error recording failed export %s: %s", export.ID, err)
+		}
+		return
+	}
+
+	err = cfg.dbQueries.CompleteExport(ctx, database.CompleteExportParams{
+		ID:         export.ID,
+		StorageKey: sql.NullString{String: key, Valid: true},
+	})
+	if err != nil {
+		log.Printf("Error completing export %s: %s", export.ID, err)
+	}
+}
+
+// buildExport writes a zip of the user's data to the export store and
+// returns its key.
+func (cfg *apiConfig) buildExport(ctx context.Context, userID uuid.UUID) (string, error) {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(cfg.writeExport(ctx, userID, pw))
+	}()
+
+	name := make([]byte, 16)
+	_, err := rand.Read(name)
+	if err != nil {
+		pr.CloseWithError(err)
+		return "", err
+	}
+	key := "exports/" + userID.String() + "/" + hex.EncodeToString(name) + ".zip"
+
+	err = cfg.exportStore.Put(ctx, key, pr)
+	pr.CloseWithError(err)
+	if err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+func (cfg *apiConfig) writeExport(ctx context.Context, userID uuid.UUID, w io.Writer) error {
+	type exportedLike struct {
+		ChirpID   uuid.UUID `json:"chirp_id"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+	type exportedFollow struct {
+		UserID    uuid.UUID `json:"user_id"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	zw := zip.NewWriter(w)
+
+	chirps, err := cfg.dbQueries.ListChirpsByAuthor(ctx, database.ListChirpsByAuthorParams{
+		UserID:   userID,
+		ViewerID: uuid.NullUUID{UUID: userID, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+	exportedChirps := make([]Chirp, 0, len(chirps))
+	for _, chirp := range chirps {
+		exportedChirps = append(exportedChirps, chirpFromDB(chirp))
+	}
+	err = cfg.loadChirpMedia(ctx, exportedChirps)
+	if err != nil {
+		return err
+	}
+	err = writeZipJSON(zw, "chirps.json", exportedChirps)
+	if err != nil {
+		return err
+	}
+
+	likes, err := cfg.dbQueries.ListLikesByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	exportedLikes := make([]exportedLike, 0, len(likes))
+	for _, like := range likes {
+		exportedLikes = append(exportedLikes, exportedLike{ChirpID: like.ChirpID, CreatedAt: like.CreatedAt})
+	}
+	err = writeZipJSON(zw, "likes.json", exportedLikes)
+	if err != nil {
+		return err
+	}
+
+	follows, err := cfg.dbQueries.ListFollowsByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	exportedFollows := make([]exportedFollow, 0, len(follows))
+	for _, follow := range follows {
+		exportedFollows = append(exportedFollows, exportedFollow{UserID: follow.FolloweeID, CreatedAt: follow.CreatedAt})
+	}
+	err = writeZipJSON(zw, "follows.json", exportedFollows)
+	if err != nil {
+		return err
+	}
+
+	media, err := cfg.dbQueries.ListMediaAttachmentsByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, m := range media {
+		err = cfg.copyMediaToZip(ctx, zw, "media/"+m.ChirpID.String()+"/"+path.Base(m.StorageKey), m.StorageKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func writeZipJSON(zw *zip.Writer, name string, v any) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (cfg *apiConfig) copyMediaToZip(ctx context.Context, zw *zip.Writer, name, key string) error {
+	src, err := cfg.mediaStore.Open(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, src)
+	return err
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+// UserSummary is the short form of a user used in lists of users.
+type UserSummary struct {
+	ID          uuid.UUID `json:"id"`
+	Handle      string    `json:"handle"`
+	DisplayName *string   `json:"display_name"`
+	AvatarURL   *string   `json:"avatar_url"`
+	IsVerified  bool      `json:"is_verified"`
+}
+
+func (cfg *apiConfig) userSummaryFromDB(user database.User) UserSummary {
+	s := UserSummary{
+		ID:         user.ID,
+		Handle:     user.Handle,
+		AvatarURL:  cfg.avatarURL(user),
+		IsVerified: user.IsVerified,
+	}
+	if user.DisplayName.Valid {
+		s.DisplayName = &user.DisplayName.String
+	}
+	return s
+}
+
+// followEntry is one user in a followers or following list.
+type followEntry struct {
+	UserSummary
+	FollowedAt time.Time `json:"followed_at"`
+}
+
+type followPage struct {
+	Users      []followEntry `json:"users"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+// listFollowersHandler lists who follows {userID}, most recent follows
+// first, paged with ?limit= and ?after=.
+func (cfg *apiConfig) listFollowersHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := cfg.lookupActiveUser(w, r)
+	if !ok {
+		return
+	}
+	page, err := parsePageParams(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows, err := cfg.dbQueries.ListFollowers(r.Context(), database.ListFollowersParams{
+		UserID:         user.ID,
+		AfterCreatedAt: page.afterCreatedAt,
+		AfterID:        page.afterID,
+		MaxResults:     page.limit,
+	})
+	if err != nil {
+		log.Printf("Error listing followers: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list followers")
+		return
+	}
+
+	total, err := cfg.dbQueries.CountFollowers(r.Context(), user.ID)
+	if err != nil {
+		log.Printf("Error counting followers: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list followers")
+		return
+	}
+
+	resp := followPage{Users: make([]followEntry, 0, len(rows))}
+	for _, row := range rows {
+		resp.Users = append(resp.Users, followEntry{
+			UserSummary: cfg.userSummaryFromDB(row.User),
+			FollowedAt:  row.FollowedAt,
+		})
+	}
+	if n := len(rows); n > 0 {
+		resp.NextCursor = page.nextCursor(n, rows[n-1].FollowedAt, rows[n-1].User.ID)
+	}
+	setCursorPageHeaders(w, r, resp.NextCursor, total)
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// listFollowingHandler lists who {userID} follows, most recent follows
+// first, paged with ?limit= and ?after=.
+func (cfg *apiConfig) listFollowingHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := cfg.lookupActiveUser(w, r)
+	if !ok {
+		return
+	}
+	page, err := parsePageParams(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows, err := cfg.dbQueries.ListFollowing(r.Context(), database.ListFollowingParams{
+		UserID:         user.ID,
+		AfterCreatedAt: page.afterCreatedAt,
+		AfterID:        page.afterID,
+		MaxResults:     page.limit,
+	})
+	if err != nil {
+		log.Printf("Error listing followed users: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list followed users")
+		return
+	}
+
+	total, err := cfg.dbQueries.CountFollowing(r.Context(), user.ID)
+	if err != nil {
+		log.Printf("Error counting followed users: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list followed users")
+		return
+	}
+
+	resp := followPage{Users: make([]followEntry, 0, len(rows))}
+	for _, row := range rows {
+		resp.Users = append(resp.Users, followEntry{
+			UserSummary: cfg.userSummaryFromDB(row.User),
+			FollowedAt:  row.FollowedAt,
+		})
+	}
+	if n := len(rows); n > 0 {
+		resp.NextCursor = page.nextCursor(n, rows[n-1].FollowedAt, rows[n-1].User.ID)
+	}
+	setCursorPageHeaders(w, r, resp.NextCursor, total)
+	respondWithJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+// canViewChirpsOf reports whether the requester may see the author's chirps.
+// Private accounts only show their chirps to themselves and their followers.
+func (cfg *apiConfig) canViewChirpsOf(ctx context.Context, author database.User) (bool, error) {
+	viewerID := viewerFromContext(ctx)
+	if !author.IsPrivate || (viewerID.Valid && viewerID.UUID == author.ID) {
+		return true, nil
+	}
+	if !viewerID.Valid {
+		return false, nil
+	}
+	return cfg.dbQueries.IsFollowing(ctx, database.IsFollowingParams{
+		FollowerID: viewerID.UUID,
+		FolloweeID: author.ID,
+	})
+}
+
+// listFollowRequestsHandler lists the users waiting for the authenticated
+// user to approve their follow, most recent first, paged with ?limit= and
+// ?after=.
+func (cfg *apiConfig) listFollowRequestsHandler(w http.ResponseWriter, r *http.Request) {
+	type requestingUser struct {
+		UserSummary
+		RequestedAt time.Time `json:"requested_at"`
+	}
+	type followRequestsPage struct {
+		Users      []requestingUser `json:"users"`
+		NextCursor string           `json:"next_cursor,omitempty"`
+	}
+
+	userID, _ := userIDFromContext(r.Context())
+
+	page, err := parsePageParams(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows, err := cfg.dbQueries.ListFollowRequests(r.Context(), database.ListFollowRequestsParams{
+		UserID:         userID,
+		AfterCreatedAt: page.afterCreatedAt,
+		AfterID:        page.afterID,
+		MaxResults:     page.limit,
+	})
+	if err != nil {
+		log.Printf("Error listing follow requests: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list follow requests")
+		return
+	}
+
+	total, err := cfg.dbQueries.CountFollowRequests(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error counting follow requests: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list follow requests")
+		return
+	}
+
+	resp := followRequestsPage{Users: make([]requestingUser, 0, len(rows))}
+	for _, row := range rows {
+		resp.Users = append(resp.Users, requestingUser{
+			UserSummary: cfg.userSummaryFromDB(row.User),
+			RequestedAt: row.RequestedAt,
+		})
+	}
+	if n := len(rows); n > 0 {
+		resp.NextCursor = page.nextCursor(n, rows[n-1].RequestedAt, rows[n-1].User.ID)
+	}
+	setCursorPageHeaders(w, r, resp.NextCursor, total)
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// approveFollowRequestHandler lets {userID} (an ID or handle) follow the
+// authenticated user, consuming their pending request.
+func (cfg *apiConfig) approveFollowRequestHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	requester, ok := cfg.lookupActiveUser(w, r)
+	if !ok {
+		return
+	}
+
+	err := cfg.approveFollowRequest(r.Context(), requester.ID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "Follow request not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error approving follow request: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't approve follow request")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// approveFollowRequest turns a pending request into a follow. It returns
+// sql.ErrNoRows if there is no such request.
+func (cfg *apiConfig) approveFollowRequest(ctx context.Context, requesterID, targetID uuid.UUID) error {
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	n, err := qtx.DeleteFollowRequest(ctx, database.DeleteFollowRequestParams{
+		RequesterID: requesterID,
+		TargetID:    targetID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	err = qtx.Follow(ctx, database.FollowParams{
+		FollowerID: requesterID,
+		FolloweeID: targetID,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// denyFollowRequestHandler discards {userID}'s pending follow request. The
+// requester isn't told; they can ask again.
+func (cfg *apiConfig) denyFollowRequestHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	requester, ok := cfg.lookupActiveUser(w, r)
+	if !ok {
+		return
+	}
+
+	n, err := cfg.dbQueries.DeleteFollowRequest(r.Context(), database.DeleteFollowRequestParams{
+		RequesterID: requester.ID,
+		TargetID:    userID,
+	})
+	if err != nil {
+		log.Printf("Error deleting follow request: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't deny follow request")
+		return
+	}
+	if n == 0 {
+		respondWithError(w, http.StatusNotFound, "Follow request not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
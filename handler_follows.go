@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+// followHandler makes the authenticated user follow {userID} (an ID or
+// handle) and responds with the followed user's profile. Following a private
+// account only requests to follow it, until the account approves. Following
+// someone twice is not an error.
+func (cfg *apiConfig) followHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	followee, ok := cfg.lookupActiveUser(w, r)
+	if !ok {
+		return
+	}
+	if followee.ID == userID {
+		respondWithError(w, http.StatusBadRequest, "You can't follow yourself")
+		return
+	}
+
+	blocked, err := cfg.dbQueries.IsBlocked(r.Context(), database.IsBlockedParams{
+		BlockerID: followee.ID,
+		BlockedID: userID,
+	})
+	if err != nil {
+		log.Printf("Error checking block: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't follow user")
+		return
+	}
+	if blocked {
+		respondWithError(w, http.StatusForbidden, "You can't follow this user")
+		return
+	}
+
+	if followee.IsPrivate {
+		err = cfg.requestFollow(r.Context(), userID, followee.ID)
+	} else {
+		err = cfg.dbQueries.Follow(r.Context(), database.FollowParams{
+			FollowerID: userID,
+			FolloweeID: followee.ID,
+		})
+	}
+	if err != nil {
+		log.Printf("Error following user: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't follow user")
+		return
+	}
+
+	cfg.respondWithProfile(w, r, followee.ID)
+}
+
+// requestFollow asks to follow a private account, unless the follower
+// already does.
+func (cfg *apiConfig) requestFollow(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	following, err := cfg.dbQueries.IsFollowing(ctx, database.IsFollowingParams{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+	})
+	if err != nil || following {
+		return err
+	}
+	return cfg.dbQueries.CreateFollowRequest(ctx, database.CreateFollowRequestParams{
+		RequesterID: followerID,
+		TargetID:    followeeID,
+	})
+}
+
+// unfollowHandler undoes followHandler, withdrawing a pending follow request
+// too. Unfollowing someone who isn't followed is not an error.
+func (cfg *apiConfig) unfollowHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	followee, ok := cfg.lookupActiveUser(w, r)
+	if !ok {
+		return
+	}
+
+	err := cfg.dbQueries.Unfollow(r.Context(), database.UnfollowParams{
+		FollowerID: userID,
+		FolloweeID: followee.ID,
+	})
+	if err != nil {
+		log.Printf("Error unfollowing user: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't unfollow user")
+		return
+	}
+	_, err = cfg.dbQueries.DeleteFollowRequest(r.Context(), database.DeleteFollowRequestParams{
+		RequesterID: userID,
+		TargetID:    followee.ID,
+	})
+	if err != nil {
+		log.Printf("Error withdrawing follow request: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't unfollow user")
+		return
+	}
+
+	cfg.respondWithProfile(w, r, followee.ID)
+}
+
+// lookupActiveUser loads the user named by the {userID} path value. Deleted,
+// deactivated and suspended users are reported as not found.
+func (cfg *apiConfig) lookupActiveUser(w http.ResponseWriter, r *http.Request) (database.User, bool) {
+	userID, ok := cfg.resolveUserRef(w, r, r.PathValue("userID"))
+	if !ok {
+		return database.User{}, false
+	}
+
+	user, err := cfg.dbQueries.GetUserByID(r.Context(), userID)
+	if err == nil && (user.DeletedAt.Valid || user.DeactivatedAt.Valid || user.SuspendedAt.Valid) {
+		err = sql.ErrNoRows
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "User not found")
+		return database.User{}, false
+	}
+	if err != nil {
+		log.Printf("Error getting user: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get user")
+		return database.User{}, false
+	}
+	return user, true
+}
+
+// loadFollowStats fills in, in place, whether the authenticated user, if any,
+// follows or has asked to follow each of the profiles.
+func (cfg *apiConfig) loadFollowStats(ctx context.Context, profiles []Profile) error {
+	if len(profiles) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(profiles))
+	for _, profile := range profiles {
+		ids = append(ids, profile.ID)
+	}
+	viewerID := viewerFromContext(ctx)
+
+	rows, err := cfg.dbQueries.GetFollowStats(ctx, database.GetFollowStatsParams{
+		ViewerID: viewerID,
+		UserIds:  ids,
+	})
+	if err != nil {
+		return err
+	}
+
+	stats := make(map[uuid.UUID]database.GetFollowStatsRow, len(rows))
+	for _, row := range rows {
+		stats[row.UserID] = row
+	}
+	for i := range profiles {
+		s := stats[profiles[i].ID]
+		profiles[i].FollowedByMe = s.FollowedByMe
+		profiles[i].FollowRequestedByMe = s.FollowRequestedByMe
+	}
+	return nil
+}
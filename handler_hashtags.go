@@ -0,0 +1,120 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+// hashtagChirpsHandler lists chirps tagged with {tag}, newest first, paged
+// with ?limit= and ?after=.
+func (cfg *apiConfig) hashtagChirpsHandler(w http.ResponseWriter, r *http.Request) {
+	tag := strings.ToLower(strings.TrimPrefix(r.PathValue("tag"), "#"))
+	if tag == "" {
+		respondWithError(w, http.StatusBadRequest, "Invalid hashtag")
+		return
+	}
+
+	page, err := parsePageParams(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows, err := cfg.dbQueries.ListChirpsByHashtag(r.Context(), database.ListChirpsByHashtagParams{
+		Tag:            tag,
+		ViewerID:       viewerFromContext(r.Context()),
+		AfterCreatedAt: page.afterCreatedAt,
+		AfterID:        page.afterID,
+		MaxResults:     page.limit,
+	})
+	if err != nil {
+		log.Printf("Error listing chirps by hashtag: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list chirps")
+		return
+	}
+
+	total, err := cfg.dbQueries.CountChirpsByHashtag(r.Context(), database.CountChirpsByHashtagParams{
+		Tag:      tag,
+		ViewerID: viewerFromContext(r.Context()),
+	})
+	if err != nil {
+		log.Printf("Error counting chirps by hashtag: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list chirps")
+		return
+	}
+
+	chirps, err := cfg.chirpsFromDB(r.Context(), rows)
+	if err != nil {
+		log.Printf("Error loading chirp details: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list chirps")
+		return
+	}
+
+	cfg.views.recordChirps(chirps)
+
+	resp := chirpsPage{Chirps: chirps}
+	if n := len(rows); n > 0 {
+		resp.NextCursor = page.nextCursor(n, rows[n-1].CreatedAt, rows[n-1].ID)
+	}
+	setCursorPageHeaders(w, r, resp.NextCursor, total)
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+const (
+	defaultTrendsWindow = 24 * time.Hour
+	maxTrendsWindow     = 7 * 24 * time.Hour
+	defaultTrendsLimit  = 10
+	maxTrendsLimit      = 50
+)
+
+// trendsHandler returns the most used hashtags over a rolling ?window=
+// (a duration such as "6h", default 24h), with how many chirps used each.
+func (cfg *apiConfig) trendsHandler(w http.ResponseWriter, r *http.Request) {
+	type trend struct {
+		Tag        string `json:"tag"`
+		ChirpCount int64  `json:"chirp_count"`
+	}
+
+	query := r.URL.Query()
+
+	window := defaultTrendsWindow
+	if v := query.Get("window"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 || d > maxTrendsWindow {
+			respondWithError(w, http.StatusBadRequest, "window must be a duration up to 168h")
+			return
+		}
+		window = d
+	}
+
+	limit := defaultTrendsLimit
+	if v := query.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxTrendsLimit {
+			respondWithError(w, http.StatusBadRequest, "Invalid limit")
+			return
+		}
+		limit = n
+	}
+
+	rows, err := cfg.dbQueries.ListTrendingHashtags(r.Context(), database.ListTrendingHashtagsParams{
+		CreatedAt: time.Now().UTC().Add(-window),
+		Limit:     int32(limit),
+	})
+	if err != nil {
+		log.Printf("Error listing trending hashtags: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list trends")
+		return
+	}
+
+	trends := make([]trend, 0, len(rows))
+	for _, row := range rows {
+		trends = append(trends, trend{Tag: row.Tag, ChirpCount: row.ChirpCount})
+	}
+	respondWithJSON(w, http.StatusOK, trends)
+}
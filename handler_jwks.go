@@ -0,0 +1,19 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/auth"
+)
+
+// jwksHandler publishes the public keys used to sign access tokens. With a
+// shared HS256 secret there is nothing to publish, so the set is empty.
+func (cfg *apiConfig) jwksHandler(w http.ResponseWriter, r *http.Request) {
+	keySet, ok := cfg.tokenSigner.(*auth.RSAKeySet)
+	if !ok {
+		respondWithJSON(w, http.StatusOK, auth.JWKS{Keys: []auth.JWK{}})
+		return
+	}
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	respondWithJSON(w, http.StatusOK, keySet.JWKS())
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+func (cfg *apiConfig) likeChirpHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	chirp, ok := cfg.lookupChirp(w, r)
+	if !ok {
+		return
+	}
+
+	err := cfg.dbQueries.LikeChirp(r.Context(), database.LikeChirpParams{
+		UserID:  userID,
+		ChirpID: chirp.ID,
+	})
+	if err != nil {
+		log.Printf("Error liking chirp: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't like chirp")
+		return
+	}
+
+	cfg.respondWithChirp(w, r, http.StatusOK, chirp)
+}
+
+func (cfg *apiConfig) unlikeChirpHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	chirp, ok := cfg.lookupChirp(w, r)
+	if !ok {
+		return
+	}
+
+	err := cfg.dbQueries.UnlikeChirp(r.Context(), database.UnlikeChirpParams{
+		UserID:  userID,
+		ChirpID: chirp.ID,
+	})
+	if err != nil {
+		log.Printf("Error unliking chirp: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't unlike chirp")
+		return
+	}
+
+	cfg.respondWithChirp(w, r, http.StatusOK, chirp)
+}
+
+// loadChirpStats fills in the like and rechirp counts of chirps in place,
+// and whether the authenticated user, if any, has liked or rechirped each of
+// them.
+func (cfg *apiConfig) loadChirpStats(ctx context.Context, chirps []Chirp) error {
+	if len(chirps) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(chirps))
+	for _, chirp := range chirps {
+		ids = append(ids, chirp.ID)
+	}
+	viewerID := viewerFromContext(ctx)
+
+	likeRows, err := cfg.dbQueries.GetChirpLikeStats(ctx, database.GetChirpLikeStatsParams{
+		ViewerID: viewerID,
+		ChirpIds: ids,
+	})
+	if err != nil {
+		return err
+	}
+	rechirpRows, err := cfg.dbQueries.GetChirpRechirpStats(ctx, database.GetChirpRechirpStatsParams{
+		ViewerID: viewerID,
+		ChirpIds: ids,
+	})
+	if err != nil {
+		return err
+	}
+
+	likes := make(map[uuid.UUID]database.GetChirpLikeStatsRow, len(likeRows))
+	for _, row := range likeRows {
+		likes[row.ChirpID] = row
+	}
+	rechirps := make(map[uuid.UUID]database.GetChirpRechirpStatsRow, len(rechirpRows))
+	for _, row := range rechirpRows {
+		rechirps[row.ChirpID] = row
+	}
+	for i := range chirps {
+		chirps[i].LikeCount = likes[chirps[i].ID].LikeCount
+		chirps[i].LikedByMe = likes[chirps[i].ID].LikedByMe
+		chirps[i].RechirpCount = rechirps[chirps[i].ID].RechirpCount
+		chirps[i].RechirpedByMe = rechirps[chirps[i].ID].RechirpedByMe
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/auth"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+const (
+	defaultAccessTokenTTL = time.Hour
+	refreshTokenTTL       = 60 * 24 * time.Hour
+	rememberMeTokenTTL    = 365 * 24 * time.Hour
+)
+
+const (
+	refreshTokenKindStandard   = "standard"
+	refreshTokenKindRememberMe = "remember_me"
+)
+
+func (cfg *apiConfig) loginHandler(w http.ResponseWriter, r *http.Request) {
+	type loginParameters struct {
+		Email            string `json:"email"`
+		Handle           string `json:"handle"`
+		Password         string `json:"password"`
+		ExpiresInSeconds int    `json:"expires_in_seconds"`
+		RememberMe       bool   `json:"remember_me"`
+	}
+	type loginResponse struct {
+		User
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	params := loginParameters{}
+	if !decodeParameters(w, r, &params) {
+		return
+	}
+
+	// Users log in with either their email or their handle.
+	login, lookup := params.Email, cfg.dbQueries.GetUserByEmail
+	if login == "" {
+		login, lookup = params.Handle, cfg.dbQueries.GetUserByHandle
+	}
+
+	user, err := lookup(r.Context(), login)
+	if err != nil {
+		cfg.recordAuthEvent(r, uuid.Nil, authEventLoginFailed, login)
+		respondWithError(w, http.StatusUnauthorized, "Incorrect email or password")
+		return
+	}
+
+	if user.LockedUntil.Valid && user.LockedUntil.Time.After(time.Now().UTC()) {
+		cfg.recordAuthEvent(r, user.ID, authEventLoginFailed, "account locked")
+		respondLocked(w, user.LockedUntil.Time)
+		return
+	}
+
+	ip := clientIP(r)
+	err = auth.CheckPasswordHash(params.Password, user.HashedPassword)
+	if err != nil {
+		cfg.recordFailedLogin(r.Context(), user.ID, ip)
+		cfg.recordAuthEvent(r, user.ID, authEventLoginFailed, "incorrect password")
+		respondWithError(w, http.StatusUnauthorized, "Incorrect email or password")
+		return
+	}
+
+	if user.SuspendedAt.Valid {
+		cfg.recordAuthEvent(r, user.ID, authEventLoginFailed, "account suspended")
+		respondWithError(w, http.StatusForbidden, "Account suspended")
+		return
+	}
+
+	// Logging in reactivates a deactivated account, until it is purged.
+	if user.DeactivatedAt.Valid {
+		if time.Now().UTC().Sub(user.DeactivatedAt.Time) > cfg.deactivationRetention {
+			cfg.recordAuthEvent(r, user.ID, authEventLoginFailed, "account deactivated")
+			respondWithError(w, http.StatusGone, "Account was deactivated too long ago to reactivate")
+			return
+		}
+		err = cfg.dbQueries.ReactivateUser(r.Context(), user.ID)
+		if err != nil {
+			log.Printf("Error reactivating user: %s", err)
+			respondWithError(w, http.StatusInternalServerError, "Couldn't log in")
+			return
+		}
+		user.DeactivatedAt = sql.NullTime{}
+		cfg.recordAuthEvent(r, user.ID, authEventAccountReactivated, "")
+	}
+
+	err = cfg.recordSuccessfulLogin(r.Context(), user, params.Password, ip)
+	if err != nil {
+		log.Printf("Error recording login: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't log in")
+		return
+	}
+
+	// Clients may ask for a shorter-lived token, but never a longer one.
+	expiresIn := cfg.accessTokenTTL
+	if params.ExpiresInSeconds > 0 && time.Duration(params.ExpiresInSeconds)*time.Second < expiresIn {
+		expiresIn = time.Duration(params.ExpiresInSeconds) * time.Second
+	}
+
+	token, err := cfg.tokenSigner.MakeJWT(user.ID, expiresIn)
+	if err != nil {
+		log.Printf("Error creating access token: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create access token")
+		return
+	}
+
+	refreshToken, err := auth.MakeRefreshToken()
+	if err != nil {
+		log.Printf("Error creating refresh token: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create refresh token")
+		return
+	}
+
+	kind, ttl := refreshTokenKindStandard, refreshTokenTTL
+	if params.RememberMe {
+		kind, ttl = refreshTokenKindRememberMe, cfg.rememberMeTTL
+	}
+
+	_, err = cfg.dbQueries.CreateRefreshToken(r.Context(), database.CreateRefreshTokenParams{
+		Token:     refreshToken,
+		UserID:    user.ID,
+		ExpiresAt: time.Now().UTC().Add(ttl),
+		UserAgent: r.UserAgent(),
+		IpAddress: ip,
+		Kind:      kind,
+	})
+	if err != nil {
+		log.Printf("Error saving refresh token: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't save refresh token")
+		return
+	}
+
+	cfg.recordAuthEvent(r, user.ID, authEventLoginSucceeded, "")
+	cfg.checkNewDevice(r.Context(), user, ip, r.UserAgent())
+	respondWithJSON(w, http.StatusOK, loginResponse{
+		User:         cfg.userFromDB(user),
+		Token:        token,
+		RefreshToken: refreshToken,
+	})
+}
+
+// recordFailedLogin stores a failed attempt and locks the account once the
+// number of recent failures reaches the configured limit. Errors are only
+// logged: the caller is already rejecting the login.
+func (cfg *apiConfig) recordFailedLogin(ctx context.Context, userID uuid.UUID, ip string) {
+	err := cfg.dbQueries.RecordLoginAttempt(ctx, database.RecordLoginAttemptParams{
+		UserID:    userID,
+		IpAddress: ip,
+		Succeeded: false,
+	})
+	if err != nil {
+		log.Printf("Error recording failed login: %s", err)
+		return
+	}
+
+	now := time.Now().UTC()
+	failures, err := cfg.dbQueries.CountRecentFailedLoginAttempts(ctx, database.CountRecentFailedLoginAttemptsParams{
+		UserID:    userID,
+		CreatedAt: now.Add(-cfg.lockout.window),
+	})
+	if err != nil {
+		log.Printf("Error counting failed logins: %s", err)
+		return
+	}
+	if failures < int64(cfg.lockout.maxFailures) {
+		return
+	}
+
+	err = cfg.dbQueries.LockUser(ctx, database.LockUserParams{
+		ID:          userID,
+		LockedUntil: sql.NullTime{Time: now.Add(cfg.lockout.duration), Valid: true},
+	})
+	if err != nil {
+		log.Printf("Error locking user: %s", err)
+	}
+}
+
+// recordSuccessfulLogin stores the attempt and resets the failure count. If
+// the user's password hash predates the current hashing policy, it is
+// replaced in the same transaction using the just-verified password.
+func (cfg *apiConfig) recordSuccessfulLogin(ctx context.Context, user database.User, password, ip string) error {
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	err = qtx.RecordLoginAttempt(ctx, database.RecordLoginAttemptParams{
+		UserID:    user.ID,
+		IpAddress: ip,
+		Succeeded: true,
+	})
+	if err != nil {
+		return err
+	}
+	err = qtx.ClearFailedLoginAttempts(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	if cfg.passwordHasher.NeedsRehash(user.HashedPassword) {
+		newHash, err := cfg.passwordHasher.Hash(password)
+		if err != nil {
+			return err
+		}
+		// Matching on the old hash keeps a concurrent password change from
+		// being overwritten.
+		err = qtx.RehashUserPassword(ctx, database.RehashUserPasswordParams{
+			ID:                user.ID,
+			OldHashedPassword: user.HashedPassword,
+			NewHashedPassword: newHash,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func respondLocked(w http.ResponseWriter, lockedUntil time.Time) {
+	retryAfter := int(math.Ceil(time.Until(lockedUntil).Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	respondWithError(w, http.StatusLocked, "Account temporarily locked after too many failed logins")
+}
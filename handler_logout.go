@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// logoutHandler signs the user out of every device by revoking all of their
+// refresh tokens. Outstanding access tokens stay valid until they expire.
+func (cfg *apiConfig) logoutHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	err := cfg.revokeAllSessions(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error revoking refresh tokens: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't log out")
+		return
+	}
+	cfg.recordAuthEvent(r, userID, authEventLoggedOutEverywhere, "")
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cfg *apiConfig) revokeAllSessions(ctx context.Context, userID uuid.UUID) error {
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = cfg.dbQueries.WithTx(tx).RevokeAllRefreshTokensForUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+const (
+	defaultMediaMaxBytes = 5 << 20
+	maxMediaPerChirp     = 4
+)
+
+// mediaExtensions maps the image types chirps may carry to the file
+// extension they are stored under.
+var mediaExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+var errMediaTooLarge = errors.New("File is too large")
+
+// MediaAttachment is an image attached to a chirp.
+type MediaAttachment struct {
+	ID          uuid.UUID `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	URL         string    `json:"url"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+}
+
+func (cfg *apiConfig) mediaAttachmentFromDB(media database.MediaAttachment) MediaAttachment {
+	return MediaAttachment{
+		ID:          media.ID,
+		CreatedAt:   media.CreatedAt,
+		URL:         cfg.mediaStore.URL(media.StorageKey),
+		ContentType: media.ContentType,
+		SizeBytes:   media.SizeBytes,
+	}
+}
+
+// uploadChirpMediaHandler attaches the image in the multipart "file" field to
+// one of the authenticated user's chirps. The type is sniffed from the
+// content rather than trusted from the client.
+func (cfg *apiConfig) uploadChirpMediaHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	chirp, ok := cfg.getOwnedChirp(w, r)
+	if !ok {
+		return
+	}
+
+	count, err := cfg.dbQueries.CountMediaAttachments(r.Context(), chirp.ID)
+	if err != nil {
+		log.Printf("Error counting media: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't upload media")
+		return
+	}
+	if count >= maxMediaPerChirp {
+		respondWithError(w, http.StatusBadRequest, "Chirp already has the maximum number of attachments")
+		return
+	}
+
+	data, contentType, err := cfg.readUploadedImage(w, r)
+	if errors.Is(err, errMediaTooLarge) {
+		respondWithError(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	}
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	key, err := cfg.putMedia(r.Context(), "chirps/", data, contentType)
+	if err != nil {
+		log.Printf("Error storing media: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't upload media")
+		return
+	}
+
+	media, err := cfg.dbQueries.CreateMediaAttachment(r.Context(), database.CreateMediaAttachmentParams{
+		ChirpID:     chirp.ID,
+		UserID:      userID,
+		StorageKey:  key,
+		ContentType: contentType,
+		SizeBytes:   int64(len(data)),
+	})
+	if err != nil {
+		log.Printf("Error creating media attachment: %s", err)
+		cfg.deleteMedia(r.Context(), key)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't upload media")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, cfg.mediaAttachmentFromDB(media))
+}
+
+// readUploadedImage reads the multipart "file" field, enforcing the
+// configured size limit and the allowed image types. The body itself is
+// already capped at the upload limit by bodyLimits.
+func (cfg *apiConfig) readUploadedImage(w http.ResponseWriter, r *http.Request) ([]byte, string, error) {
+	file, _, err := r.FormFile("file")
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return nil, "", errMediaTooLarge
+	}
+	if err != nil {
+		return nil, "", errors.New("Couldn't read file")
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, cfg.mediaMaxBytes+1))
+	if err != nil {
+		return nil, "", errors.New("Couldn't read file")
+	}
+	if int64(len(data)) > cfg.mediaMaxBytes {
+		return nil, "", errMediaTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	if _, ok := mediaExtensions[contentType]; !ok {
+		return nil, "", errors.New("Unsupported media type")
+	}
+	return data, contentType, nil
+}
+
+// putMedia writes data to the media store under a fresh random key with the
+// given prefix and returns the key.
+func (cfg *apiConfig) putMedia(ctx context.Context, prefix string, data []byte, contentType string) (string, error) {
+	name := make([]byte, 16)
+	_, err := rand.Read(name)
+	if err != nil {
+		return "", err
+	}
+	key := prefix + hex.EncodeToString(name) + mediaExtensions[contentType]
+
+	return key, cfg.mediaStore.Put(ctx, key, bytes.NewReader(data))
+}
+
+// deleteMedia removes a stored file that is no longer referenced, logging
+// rather than failing since the caller is already handling another error.
+func (cfg *apiConfig) deleteMedia(ctx context.Context, key string) {
+	err := cfg.mediaStore.Delete(ctx, key)
+	if err != nil {
+		log.Printf("Error removing media %s: %s", key, err)
+	}
+}
+
+// loadChirpMedia fills in the media attachments of chirps in place.
+func (cfg *apiConfig) loadChirpMedia(ctx context.Context, chirps []Chirp) error {
+	if len(chirps) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(chirps))
+	for _, chirp := range chirps {
+		ids = append(ids, chirp.ID)
+	}
+
+	rows, err := cfg.dbQueries.ListMediaAttachments(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	media := make(map[uuid.UUID][]MediaAttachment)
+	for _, row := range rows {
+		media[row.ChirpID] = append(media[row.ChirpID], cfg.mediaAttachmentFromDB(row))
+	}
+	for i := range chirps {
+		if attachments, ok := media[chirps[i].ID]; ok {
+			chirps[i].Media = attachments
+		}
+	}
+	return nil
+}
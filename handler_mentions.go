@@ -0,0 +1,55 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+// myMentionsHandler lists chirps that mention the authenticated user, newest
+// first, paged with ?limit= and ?after=.
+func (cfg *apiConfig) myMentionsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	page, err := parsePageParams(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows, err := cfg.dbQueries.ListMentioningChirps(r.Context(), database.ListMentioningChirpsParams{
+		UserID:         userID,
+		AfterCreatedAt: page.afterCreatedAt,
+		AfterID:        page.afterID,
+		MaxResults:     page.limit,
+	})
+	if err != nil {
+		log.Printf("Error listing mentions: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list mentions")
+		return
+	}
+
+	total, err := cfg.dbQueries.CountMentioningChirps(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error counting mentions: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list mentions")
+		return
+	}
+
+	chirps, err := cfg.chirpsFromDB(r.Context(), rows)
+	if err != nil {
+		log.Printf("Error loading chirp details: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list mentions")
+		return
+	}
+
+	cfg.views.recordChirps(chirps)
+
+	resp := chirpsPage{Chirps: chirps}
+	if n := len(rows); n > 0 {
+		resp.NextCursor = page.nextCursor(n, rows[n-1].CreatedAt, rows[n-1].ID)
+	}
+	setCursorPageHeaders(w, r, resp.NextCursor, total)
+	respondWithJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+// muteHandler mutes {userID} (an ID or handle) for the authenticated user:
+// their chirps and mentions are left out of the muter's listings. Unlike a
+// block, nothing changes for the muted user, who isn't told. Muting someone
+// twice is not an error.
+func (cfg *apiConfig) muteHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	muted, ok := cfg.lookupActiveUser(w, r)
+	if !ok {
+		return
+	}
+	if muted.ID == userID {
+		respondWithError(w, http.StatusBadRequest, "You can't mute yourself")
+		return
+	}
+
+	err := cfg.dbQueries.Mute(r.Context(), database.MuteParams{
+		MuterID: userID,
+		MutedID: muted.ID,
+	})
+	if err != nil {
+		log.Printf("Error muting user: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't mute user")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// unmuteHandler undoes muteHandler. Unmuting someone who isn't muted is not
+// an error.
+func (cfg *apiConfig) unmuteHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	muted, ok := cfg.lookupActiveUser(w, r)
+	if !ok {
+		return
+	}
+
+	err := cfg.dbQueries.Unmute(r.Context(), database.UnmuteParams{
+		MuterID: userID,
+		MutedID: muted.ID,
+	})
+	if err != nil {
+		log.Printf("Error unmuting user: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't unmute user")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listMutesHandler lists the users the authenticated user has muted, most
+// recent first, paged with ?limit= and ?after=.
+func (cfg *apiConfig) listMutesHandler(w http.ResponseWriter, r *http.Request) {
+	type mutedUser struct {
+		UserSummary
+		MutedAt time.Time `json:"muted_at"`
+	}
+	type mutesPage struct {
+		Users      []mutedUser `json:"users"`
+		NextCursor string      `json:"next_cursor,omitempty"`
+	}
+
+	userID, _ := userIDFromContext(r.Context())
+
+	page, err := parsePageParams(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows, err := cfg.dbQueries.ListMutes(r.Context(), database.ListMutesParams{
+		UserID:         userID,
+		AfterCreatedAt: page.afterCreatedAt,
+		AfterID:        page.afterID,
+		MaxResults:     page.limit,
+	})
+	if err != nil {
+		log.Printf("Error listing mutes: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list muted users")
+		return
+	}
+
+	total, err := cfg.dbQueries.CountMutes(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error counting mutes: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list muted users")
+		return
+	}
+
+	resp := mutesPage{Users: make([]mutedUser, 0, len(rows))}
+	for _, row := range rows {
+		resp.Users = append(resp.Users, mutedUser{
+			UserSummary: cfg.userSummaryFromDB(row.User),
+			MutedAt:     row.MutedAt,
+		})
+	}
+	if n := len(rows); n > 0 {
+		resp.NextCursor = page.nextCursor(n, rows[n-1].MutedAt, rows[n-1].User.ID)
+	}
+	setCursorPageHeaders(w, r, resp.NextCursor, total)
+	respondWithJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+const notificationsLimit = 50
+
+type Notification struct {
+	ID        uuid.UUID  `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	Kind      string     `json:"kind"`
+	Body      string     `json:"body"`
+	ReadAt    *time.Time `json:"read_at"`
+}
+
+func notificationFromDB(notification database.Notification) Notification {
+	n := Notification{
+		ID:        notification.ID,
+		CreatedAt: notification.CreatedAt,
+		Kind:      notification.Kind,
+		Body:      notification.Body,
+	}
+	if notification.ReadAt.Valid {
+		n.ReadAt = &notification.ReadAt.Time
+	}
+	return n
+}
+
+func (cfg *apiConfig) listNotificationsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	rows, err := cfg.dbQueries.ListNotificationsForUser(r.Context(), database.ListNotificationsForUserParams{
+		UserID: userID,
+		Limit:  notificationsLimit,
+	})
+	if err != nil {
+		log.Printf("Error listing notifications: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list notifications")
+		return
+	}
+
+	notifications := make([]Notification, 0, len(rows))
+	for _, row := range rows {
+		notifications = append(notifications, notificationFromDB(row))
+	}
+	respondWithJSON(w, http.StatusOK, notifications)
+}
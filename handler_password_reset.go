@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/auth"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+const resetTokenTTL = time.Hour
+
+// requestPasswordResetHandler emails a one-time reset token. The lookup and
+// email happen in a background task and the response is always 202, so
+// neither the status nor the timing tells callers whether the email belongs
+// to an account.
+func (cfg *apiConfig) requestPasswordResetHandler(w http.ResponseWriter, r *http.Request) {
+	type passwordResetParameters struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+
+	params := passwordResetParameters{}
+	if !decodeParameters(w, r, &params) {
+		return
+	}
+
+	queued := cfg.tasks.enqueue(func(ctx context.Context) {
+		err := cfg.sendPasswordReset(ctx, params.Email)
+		if err != nil {
+			log.Printf("Error sending password reset: %s", err)
+		}
+	})
+	if !queued {
+		log.Printf("Background task queue full, not sending password reset")
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// sendPasswordReset emails a reset token to the account with the email, if
+// there is one.
+func (cfg *apiConfig) sendPasswordReset(ctx context.Context, email string) error {
+	user, err := cfg.dbQueries.GetUserByEmail(ctx, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	token, err := auth.MakeRefreshToken()
+	if err != nil {
+		return err
+	}
+	_, err = cfg.dbQueries.CreateResetToken(ctx, database.CreateResetTokenParams{
+		TokenHash: auth.HashToken(token),
+		UserID:    user.ID,
+		ExpiresAt: time.Now().UTC().Add(resetTokenTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Use this token to reset your Chirpy password within the next hour:\n\n%s\n\nIf you didn't ask for this, you can ignore this email.", token)
+	return cfg.mailer.Send(ctx, user.Email, "Reset your Chirpy password", body)
+}
+
+func (cfg *apiConfig) confirmPasswordResetHandler(w http.ResponseWriter, r *http.Request) {
+	type confirmPasswordResetParameters struct {
+		Token    string `json:"token" validate:"required"`
+		Password string `json:"password" validate:"required"`
+	}
+
+	params := confirmPasswordResetParameters{}
+	if !decodeParameters(w, r, &params) {
+		return
+	}
+	if !cfg.checkPasswordPolicy(w, params.Password) {
+		return
+	}
+
+	hashedPassword, err := cfg.passwordHasher.Hash(params.Password)
+	if err != nil {
+		log.Printf("Error hashing password: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't hash password")
+		return
+	}
+
+	userID, err := cfg.resetPassword(r.Context(), auth.HashToken(params.Token), hashedPassword)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusUnauthorized, "Invalid or expired reset token")
+		return
+	}
+	if err != nil {
+		log.Printf("Error resetting password: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't reset password")
+		return
+	}
+
+	cfg.recordAuthEvent(r, userID, authEventPasswordReset, "")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// resetPassword consumes the reset token, sets the new password and signs
+// the user out of every device in one transaction, so a token can never be
+// spent without the password changing, and whoever knew the old password
+// loses access with it.
+func (cfg *apiConfig) resetPassword(ctx context.Context, tokenHash, hashedPassword string) (uuid.UUID, error) {
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer tx.Rollback()
+
+	qtx := cfg.dbQueries.WithTx(tx)
+	userID, err := qtx.ConsumeResetToken(ctx, tokenHash)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	err = qtx.UpdateUserPassword(ctx, database.UpdateUserPasswordParams{
+		ID:             userID,
+		HashedPassword: hashedPassword,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	_, err = qtx.RevokeAllRefreshTokensForUser(ctx, userID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	err = qtx.RevokeAllPersonalAccessTokensForUser(ctx, userID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return userID, tx.Commit()
+}
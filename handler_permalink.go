@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	_ "embed"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+//go:embed templates/chirp.html
+var chirpPageHTML string
+
+var chirpPageTemplate = template.Must(template.New("chirp").Parse(chirpPageHTML))
+
+// chirpPageHandler serves a minimal HTML page for a chirp at
+// /chirps/{chirpID}, the URL clients share. Its OpenGraph and Twitter card
+// tags let chat apps and social sites render a preview of the chirp.
+func (cfg *apiConfig) chirpPageHandler(w http.ResponseWriter, r *http.Request) {
+	type chirpPage struct {
+		Title     string
+		URL       string
+		AuthorURL string
+		Handle    string
+		ImageURL  string
+		Chirp     Chirp
+	}
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpID"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	dbChirp, err := cfg.dbQueries.GetChirp(r.Context(), chirpID)
+	if err == nil {
+		err = cfg.checkChirpVisible(r.Context(), dbChirp)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Printf("Error getting chirp: %s", err)
+		http.Error(w, "Couldn't get chirp", http.StatusInternalServerError)
+		return
+	}
+
+	chirps, err := cfg.chirpsFromDB(r.Context(), []database.Chirp{dbChirp})
+	if err != nil {
+		log.Printf("Error loading chirp details: %s", err)
+		http.Error(w, "Couldn't get chirp", http.StatusInternalServerError)
+		return
+	}
+	chirp := chirps[0]
+	cfg.views.record(chirp.ID)
+
+	author, err := cfg.dbQueries.GetUserByID(r.Context(), chirp.UserID)
+	if err != nil {
+		log.Printf("Error getting chirp author: %s", err)
+		http.Error(w, "Couldn't get chirp", http.StatusInternalServerError)
+		return
+	}
+
+	base := cfg.baseURL(r)
+	page := chirpPage{
+		Title:     "@" + author.Handle + " on Chirpy",
+		URL:       base + "/chirps/" + chirp.ID.String(),
+		AuthorURL: base + "/api/v1/users/" + author.Handle,
+		Handle:    author.Handle,
+		Chirp:     chirp,
+	}
+	switch {
+	case len(chirp.Media) > 0:
+		page.ImageURL = absoluteURL(base, chirp.Media[0].URL)
+	case chirp.LinkPreview != nil && chirp.LinkPreview.ImageURL != "":
+		page.ImageURL = chirp.LinkPreview.ImageURL
+	}
+	for i := range page.Chirp.Media {
+		page.Chirp.Media[i].URL = absoluteURL(base, page.Chirp.Media[i].URL)
+	}
+
+	var buf bytes.Buffer
+	err = chirpPageTemplate.Execute(&buf, page)
+	if err != nil {
+		log.Printf("Error rendering chirp page: %s", err)
+		http.Error(w, "Couldn't render chirp", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// baseURL is the public origin of the server: PUBLIC_BASE_URL when
+// configured, otherwise derived from the request.
+func (cfg *apiConfig) baseURL(r *http.Request) string {
+	if cfg.publicBaseURL != "" {
+		return cfg.publicBaseURL
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// absoluteURL resolves a root-relative URL such as a media URL from the disk
+// store against base.
+func absoluteURL(base, url string) string {
+	if strings.HasPrefix(url, "/") {
+		return base + url
+	}
+	return url
+}
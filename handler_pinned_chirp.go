@@ -0,0 +1,57 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+// pinChirpHandler pins one of the authenticated user's own chirps to their
+// profile, replacing any previously pinned chirp. A null chirp_id unpins.
+func (cfg *apiConfig) pinChirpHandler(w http.ResponseWriter, r *http.Request) {
+	type pinParameters struct {
+		ChirpID *uuid.UUID `json:"chirp_id"`
+	}
+
+	userID, _ := userIDFromContext(r.Context())
+
+	params := pinParameters{}
+	if !decodeParameters(w, r, &params) {
+		return
+	}
+
+	var pinned uuid.NullUUID
+	if params.ChirpID != nil {
+		chirp, err := cfg.dbQueries.GetChirp(r.Context(), *params.ChirpID)
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Chirp not found")
+			return
+		}
+		if err != nil {
+			log.Printf("Error getting chirp: %s", err)
+			respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp")
+			return
+		}
+		if chirp.UserID != userID {
+			respondWithError(w, http.StatusForbidden, "You can only pin your own chirps")
+			return
+		}
+		pinned = uuid.NullUUID{UUID: chirp.ID, Valid: true}
+	}
+
+	user, err := cfg.dbQueries.SetPinnedChirp(r.Context(), database.SetPinnedChirpParams{
+		ID:            userID,
+		PinnedChirpID: pinned,
+	})
+	if err != nil {
+		log.Printf("Error pinning chirp: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't pin chirp")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, cfg.userFromDB(user))
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"crypto/subtle"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/auth"
+)
+
+const polkaEventUserUpgraded = "user.upgraded"
+
+// polkaWebhookHandler receives payment events from Polka, which
+// authenticates with the POLKA_KEY API key. Events we don't act on are
+// acknowledged with 204 so Polka stops resending them; an upgrade for an
+// unknown user gets 404 so that Polka retries it.
+func (cfg *apiConfig) polkaWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Event string `json:"event"`
+		Data  struct {
+			UserID uuid.UUID `json:"user_id"`
+		} `json:"data"`
+	}
+
+	key, err := auth.GetAPIKey(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find API key")
+		return
+	}
+	if cfg.polkaKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(cfg.polkaKey)) != 1 {
+		respondWithError(w, http.StatusUnauthorized, "Invalid API key")
+		return
+	}
+
+	params := parameters{}
+	if !decodeParameters(w, r, &params) {
+		return
+	}
+
+	if params.Event != polkaEventUserUpgraded {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	n, err := cfg.dbQueries.UpgradeUserToChirpyRed(r.Context(), params.Data.UserID)
+	if err != nil {
+		log.Printf("Error upgrading user: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't upgrade user")
+		return
+	}
+	if n == 0 {
+		respondWithError(w, http.StatusNotFound, "Couldn't find user")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
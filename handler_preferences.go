@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+// Preferences are a user's settings. Notifications maps each kind of
+// notification to the channels it is delivered over.
+type Preferences struct {
+	Notifications map[string]NotificationChannels `json:"notifications"`
+}
+
+// NotificationChannels says where one kind of notification is delivered.
+type NotificationChannels struct {
+	InApp bool `json:"in_app"`
+	Email bool `json:"email"`
+}
+
+// defaultNotificationChannels applies to kinds the user hasn't configured.
+var defaultNotificationChannels = NotificationChannels{InApp: true, Email: true}
+
+func notificationChannelsFromDB(pref database.NotificationPreference) NotificationChannels {
+	return NotificationChannels{InApp: pref.InApp, Email: pref.Email}
+}
+
+func (cfg *apiConfig) getPreferencesHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	prefs, err := cfg.loadPreferences(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error loading preferences: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get preferences")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, prefs)
+}
+
+// updatePreferencesHandler changes the authenticated user's preferences.
+// Notification kinds and channels left out of the request are unchanged.
+func (cfg *apiConfig) updatePreferencesHandler(w http.ResponseWriter, r *http.Request) {
+	type channelParameters struct {
+		InApp *bool `json:"in_app"`
+		Email *bool `json:"email"`
+	}
+	type preferencesParameters struct {
+		Notifications map[string]channelParameters `json:"notifications"`
+	}
+
+	userID, _ := userIDFromContext(r.Context())
+
+	params := preferencesParameters{}
+	if !decodeParameters(w, r, &params) {
+		return
+	}
+	for kind := range params.Notifications {
+		if !slices.Contains(notificationKinds, kind) {
+			respondWithError(w, http.StatusBadRequest, "Unknown notification kind: "+kind)
+			return
+		}
+	}
+
+	prefs, err := cfg.loadPreferences(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error loading preferences: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't update preferences")
+		return
+	}
+	for kind, p := range params.Notifications {
+		channels := prefs.Notifications[kind]
+		if p.InApp != nil {
+			channels.InApp = *p.InApp
+		}
+		if p.Email != nil {
+			channels.Email = *p.Email
+		}
+		prefs.Notifications[kind] = channels
+	}
+
+	err = cfg.saveNotificationPreferences(r.Context(), userID, prefs.Notifications)
+	if err != nil {
+		log.Printf("Error saving preferences: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't update preferences")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, prefs)
+}
+
+// loadPreferences returns the user's preferences with defaults filled in
+// for everything they haven't set.
+func (cfg *apiConfig) loadPreferences(ctx context.Context, userID uuid.UUID) (Preferences, error) {
+	rows, err := cfg.dbQueries.ListNotificationPreferences(ctx, userID)
+	if err != nil {
+		return Preferences{}, err
+	}
+
+	prefs := Preferences{Notifications: make(map[string]NotificationChannels, len(notificationKinds))}
+	for _, kind := range notificationKinds {
+		prefs.Notifications[kind] = defaultNotificationChannels
+	}
+	for _, row := range rows {
+		if _, ok := prefs.Notifications[row.Kind]; ok {
+			prefs.Notifications[row.Kind] = notificationChannelsFromDB(row)
+		}
+	}
+	return prefs, nil
+}
+
+func (cfg *apiConfig) saveNotificationPreferences(ctx context.Context, userID uuid.UUID, notifications map[string]NotificationChannels) error {
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	for kind, channels := range notifications {
+		err = qtx.UpsertNotificationPreference(ctx, database.UpsertNotificationPreferenceParams{
+			UserID: userID,
+			Kind:   kind,
+			InApp:  channels.InApp,
+			Email:  channels.Email,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+// TimelineChirp is an entry in a user's timeline: either a chirp they wrote
+// or one they rechirped, in which case RechirpedBy and RechirpedAt are set.
+type TimelineChirp struct {
+	Chirp
+	RechirpedBy *uuid.UUID `json:"rechirped_by,omitempty"`
+	RechirpedAt *time.Time `json:"rechirped_at,omitempty"`
+}
+
+func (cfg *apiConfig) rechirpHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	chirp, ok := cfg.lookupChirp(w, r)
+	if !ok {
+		return
+	}
+
+	err := cfg.dbQueries.Rechirp(r.Context(), database.RechirpParams{
+		UserID:  userID,
+		ChirpID: chirp.ID,
+	})
+	if err != nil {
+		log.Printf("Error rechirping chirp: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't rechirp chirp")
+		return
+	}
+
+	cfg.respondWithChirp(w, r, http.StatusOK, chirp)
+}
+
+func (cfg *apiConfig) undoRechirpHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	chirp, ok := cfg.lookupChirp(w, r)
+	if !ok {
+		return
+	}
+
+	err := cfg.dbQueries.UndoRechirp(r.Context(), database.UndoRechirpParams{
+		UserID:  userID,
+		ChirpID: chirp.ID,
+	})
+	if err != nil {
+		log.Printf("Error undoing rechirp: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't undo rechirp")
+		return
+	}
+
+	cfg.respondWithChirp(w, r, http.StatusOK, chirp)
+}
+
+// userTimelineHandler lists the chirps {userID} (an ID or handle) wrote or
+// rechirped, newest activity first, paged with ?limit= and ?after=. Private
+// accounts' timelines are forbidden to anyone but their followers.
+func (cfg *apiConfig) userTimelineHandler(w http.ResponseWriter, r *http.Request) {
+	type timelinePage struct {
+		Chirps     []TimelineChirp `json:"chirps"`
+		NextCursor string          `json:"next_cursor,omitempty"`
+	}
+
+	userID, ok := cfg.resolveUserRef(w, r, r.PathValue("userID"))
+	if !ok {
+		return
+	}
+
+	page, err := parsePageParams(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := cfg.dbQueries.GetUserByID(r.Context(), userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error getting user: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get user")
+		return
+	}
+	visible, err := cfg.canViewChirpsOf(r.Context(), user)
+	if err != nil {
+		log.Printf("Error checking follow: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list timeline")
+		return
+	}
+	if !visible {
+		respondWithError(w, http.StatusForbidden, "This account is private")
+		return
+	}
+
+	// Rechirps can bring in chirps of other accounts, which the viewer may
+	// not be allowed to see.
+	viewer := viewerFromContext(r.Context())
+	rows, err := cfg.dbQueries.ListUserTimeline(r.Context(), database.ListUserTimelineParams{
+		UserID:         userID,
+		ViewerID:       viewer,
+		AfterCreatedAt: page.afterCreatedAt,
+		AfterID:        page.afterID,
+		MaxResults:     page.limit,
+	})
+	if err != nil {
+		log.Printf("Error listing timeline: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list timeline")
+		return
+	}
+
+	total, err := cfg.dbQueries.CountUserTimeline(r.Context(), database.CountUserTimelineParams{
+		UserID:   userID,
+		ViewerID: viewer,
+	})
+	if err != nil {
+		log.Printf("Error counting timeline: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list timeline")
+		return
+	}
+
+	chirps := make([]Chirp, 0, len(rows))
+	for _, row := range rows {
+		chirps = append(chirps, chirpFromDB(row.Chirp))
+	}
+	err = cfg.loadChirpDetails(r.Context(), chirps)
+	if err != nil {
+		log.Printf("Error loading chirp details: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list timeline")
+		return
+	}
+
+	cfg.views.recordChirps(chirps)
+
+	resp := timelinePage{Chirps: make([]TimelineChirp, 0, len(rows))}
+	for i, row := range rows {
+		entry := TimelineChirp{Chirp: chirps[i]}
+		if row.Rechirped {
+			entry.RechirpedBy = &userID
+			entry.RechirpedAt = &row.ActivityAt
+		}
+		resp.Chirps = append(resp.Chirps, entry)
+	}
+	if n := len(rows); n > 0 {
+		resp.NextCursor = page.nextCursor(n, rows[n-1].ActivityAt, rows[n-1].Chirp.ID)
+	}
+	setCursorPageHeaders(w, r, resp.NextCursor, total)
+	respondWithJSON(w, http.StatusOK, resp)
+}
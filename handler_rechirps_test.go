@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+// openTestDB connects to the Postgres database in TEST_DB_URL and applies
+// the embedded migrations to a schema of its own, dropped when the test
+// ends. Tests that need it are skipped when TEST_DB_URL isn't set.
+func openTestDB(t *testing.T) *database.Queries {
+	t.Helper()
+	dbURL := os.Getenv("TEST_DB_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DB_URL is not set")
+	}
+
+	db, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		t.Fatalf("couldn't open database: %s", err)
+	}
+	// search_path is per connection, so every query must use this one.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	ctx := context.Background()
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	if _, err := db.ExecContext(ctx, fmt.Sprintf("CREATE SCHEMA %s; SET search_path TO %s, public", schema, schema)); err != nil {
+		t.Fatalf("couldn't create schema: %s", err)
+	}
+	t.Cleanup(func() { db.ExecContext(ctx, "DROP SCHEMA "+schema+" CASCADE") })
+
+	names, err := fs.Glob(schemaMigrations, "sql/schema/*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		dat, err := schemaMigrations.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		up, _, _ := strings.Cut(string(dat), "-- +goose Down")
+		if _, err := db.ExecContext(ctx, up); err != nil {
+			t.Fatalf("couldn't apply %s: %s", name, err)
+		}
+	}
+	return database.New(db)
+}
+
+func TestListUserTimelinePrivacy(t *testing.T) {
+	q := openTestDB(t)
+	ctx := context.Background()
+
+	createUser := func(handle string) uuid.UUID {
+		user, err := q.CreateUser(ctx, database.CreateUserParams{
+			Email:          handle + "@example.com",
+			HashedPassword: "unused",
+			Handle:         handle,
+		})
+		if err != nil {
+			t.Fatalf("couldn't create %s: %s", handle, err)
+		}
+		return user.ID
+	}
+	createChirp := func(userID uuid.UUID, body string) uuid.UUID {
+		chirp, err := q.CreateChirp(ctx, database.CreateChirpParams{
+			Body:        body,
+			UserID:      userID,
+			ReplyPolicy: "everyone",
+			Language:    "en",
+		})
+		if err != nil {
+			t.Fatalf("couldn't create chirp: %s", err)
+		}
+		return chirp.ID
+	}
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	owner := createUser("owner")
+	private := createUser("private")
+	muted := createUser("muted")
+	follower := createUser("follower")
+	stranger := createUser("stranger")
+
+	_, err := q.UpdateUserProfile(ctx, database.UpdateUserProfileParams{ID: private, IsPrivate: true})
+	must(err)
+	ownChirp := createChirp(owner, "mine")
+	privateChirp := createChirp(private, "followers only")
+	mutedChirp := createChirp(muted, "loud")
+	must(q.Rechirp(ctx, database.RechirpParams{UserID: owner, ChirpID: privateChirp}))
+	must(q.Rechirp(ctx, database.RechirpParams{UserID: owner, ChirpID: mutedChirp}))
+	must(q.Follow(ctx, database.FollowParams{FollowerID: follower, FolloweeID: private}))
+	must(q.Mute(ctx, database.MuteParams{MuterID: stranger, MutedID: muted}))
+
+	tests := []struct {
+		name   string
+		viewer uuid.NullUUID
+		want   []uuid.UUID
+	}{
+		{"anonymous", uuid.NullUUID{}, []uuid.UUID{mutedChirp, ownChirp}},
+		{"follower of private account", uuid.NullUUID{UUID: follower, Valid: true}, []uuid.UUID{mutedChirp, privateChirp, ownChirp}},
+		{"private account itself", uuid.NullUUID{UUID: private, Valid: true}, []uuid.UUID{mutedChirp, privateChirp, ownChirp}},
+		{"muter", uuid.NullUUID{UUID: stranger, Valid: true}, []uuid.UUID{ownChirp}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := q.ListUserTimeline(ctx, database.ListUserTimelineParams{
+				UserID:     owner,
+				ViewerID:   tt.viewer,
+				MaxResults: 10,
+			})
+			if err != nil {
+				t.Fatalf("ListUserTimeline() error = %s", err)
+			}
+			got := make([]uuid.UUID, 0, len(rows))
+			for _, row := range rows {
+				got = append(got, row.Chirp.ID)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("ListUserTimeline() = %v, want %v", got, tt.want)
+			}
+
+			total, err := q.CountUserTimeline(ctx, database.CountUserTimelineParams{
+				UserID:   owner,
+				ViewerID: tt.viewer,
+			})
+			if err != nil {
+				t.Fatalf("CountUserTimeline() error = %s", err)
+			}
+			if total != int64(len(tt.want)) {
+				t.Errorf("CountUserTimeline() = %d, want %d", total, len(tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/auth"
+)
+
+func (cfg *apiConfig) refreshHandler(w http.ResponseWriter, r *http.Request) {
+	type refreshResponse struct {
+		Token string `json:"token"`
+	}
+
+	refreshToken, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find refresh token")
+		return
+	}
+
+	user, err := cfg.dbQueries.GetUserFromRefreshToken(r.Context(), refreshToken)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Invalid refresh token")
+		return
+	}
+
+	token, err := cfg.tokenSigner.MakeJWT(user.ID, cfg.accessTokenTTL)
+	if err != nil {
+		log.Printf("Error creating access token: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create access token")
+		return
+	}
+
+	cfg.recordAuthEvent(r, user.ID, authEventTokenRefreshed, "")
+	respondWithJSON(w, http.StatusOK, refreshResponse{
+		Token: token,
+	})
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+const (
+	reportStatusOpen      = "open"
+	reportStatusDismissed = "dismissed"
+	reportStatusResolved  = "resolved"
+)
+
+// reportReasons are the categories a chirp can be reported under.
+var reportReasons = []string{
+	"spam",
+	"harassment",
+	"hate",
+	"violence",
+	"self_harm",
+	"misinformation",
+	"other",
+}
+
+// ChirpReport is a user's report of a chirp for moderators to review.
+type ChirpReport struct {
+	ID         uuid.UUID  `json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	ChirpID    uuid.UUID  `json:"chirp_id"`
+	ReporterID uuid.UUID  `json:"reporter_id"`
+	Reason     string     `json:"reason"`
+	Details    string     `json:"details"`
+	Status     string     `json:"status"`
+	Action     string     `json:"action,omitempty"`
+	ResolvedBy *uuid.UUID `json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+func chirpReportFromDB(report database.ChirpReport) ChirpReport {
+	r := ChirpReport{
+		ID:         report.ID,
+		CreatedAt:  report.CreatedAt,
+		UpdatedAt:  report.UpdatedAt,
+		ChirpID:    report.ChirpID,
+		ReporterID: report.ReporterID,
+		Reason:     report.Reason,
+		Details:    report.Details,
+		Status:     report.Status,
+		Action:     report.Action.String,
+	}
+	if report.ResolvedBy.Valid {
+		r.ResolvedBy = &report.ResolvedBy.UUID
+	}
+	if report.ResolvedAt.Valid {
+		r.ResolvedAt = &report.ResolvedAt.Time
+	}
+	return r
+}
+
+// reportChirpHandler files a report against a chirp. Each user can report a
+// given chirp once.
+func (cfg *apiConfig) reportChirpHandler(w http.ResponseWriter, r *http.Request) {
+	type reportParameters struct {
+		Reason  string `json:"reason" validate:"required"`
+		Details string `json:"details" validate:"max=1000"`
+	}
+
+	userID, _ := userIDFromContext(r.Context())
+
+	chirp, ok := cfg.lookupChirp(w, r)
+	if !ok {
+		return
+	}
+
+	params := reportParameters{}
+	if !decodeParameters(w, r, &params) {
+		return
+	}
+
+	if !slices.Contains(reportReasons, params.Reason) {
+		respondWithError(w, http.StatusBadRequest, "Invalid reason")
+		return
+	}
+
+	report, err := cfg.dbQueries.CreateChirpReport(r.Context(), database.CreateChirpReportParams{
+		ChirpID:    chirp.ID,
+		ReporterID: userID,
+		Reason:     params.Reason,
+		Details:    params.Details,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusConflict, "You have already reported this chirp")
+		return
+	}
+	if err != nil {
+		log.Printf("Error creating report: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't report chirp")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, chirpReportFromDB(report))
+}
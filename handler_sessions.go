@@ -0,0 +1,99 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+// Session is a device's login, backed by its refresh token. The token itself
+// is never exposed.
+type Session struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	UserAgent string    `json:"user_agent"`
+	IPAddress string    `json:"ip_address"`
+	Kind      string    `json:"kind"`
+}
+
+func sessionFromDB(token database.RefreshToken) Session {
+	return Session{
+		ID:        token.ID,
+		CreatedAt: token.CreatedAt,
+		ExpiresAt: token.ExpiresAt,
+		UserAgent: token.UserAgent,
+		IPAddress: token.IpAddress,
+		Kind:      token.Kind,
+	}
+}
+
+func (cfg *apiConfig) listSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	tokens, err := cfg.dbQueries.ListActiveRefreshTokensForUser(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing sessions: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list sessions")
+		return
+	}
+
+	sessions := make([]Session, 0, len(tokens))
+	for _, token := range tokens {
+		sessions = append(sessions, sessionFromDB(token))
+	}
+	respondWithJSON(w, http.StatusOK, sessions)
+}
+
+func (cfg *apiConfig) deleteSessionHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	sessionID, err := uuid.Parse(r.PathValue("sessionID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid session ID")
+		return
+	}
+
+	rows, err := cfg.dbQueries.RevokeRefreshTokenByID(r.Context(), database.RevokeRefreshTokenByIDParams{
+		ID:     sessionID,
+		UserID: userID,
+	})
+	if err != nil {
+		log.Printf("Error revoking session: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't revoke session")
+		return
+	}
+	if rows == 0 {
+		respondWithError(w, http.StatusNotFound, "Session not found")
+		return
+	}
+	cfg.recordAuthEvent(r, userID, authEventSessionRevoked, sessionID.String())
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// expireRefreshTokensHandler lets admins revoke every active refresh token of
+// one kind, e.g. ?kind=remember_me to force long-lived sessions to log in again.
+func (cfg *apiConfig) expireRefreshTokensHandler(w http.ResponseWriter, r *http.Request) {
+	type expireResponse struct {
+		Revoked int64 `json:"revoked"`
+	}
+
+	kind := r.URL.Query().Get("kind")
+	if kind != refreshTokenKindStandard && kind != refreshTokenKindRememberMe {
+		respondWithError(w, http.StatusBadRequest, "kind must be standard or remember_me")
+		return
+	}
+
+	revoked, err := cfg.dbQueries.RevokeRefreshTokensByKind(r.Context(), kind)
+	if err != nil {
+		log.Printf("Error revoking refresh tokens: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't revoke refresh tokens")
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, expireResponse{Revoked: revoked})
+}
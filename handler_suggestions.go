@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+const (
+	suggestionsRefreshInterval = time.Hour
+	// suggestionsActivityWindow is how far back chirps count as recent
+	// activity when ranking suggestions.
+	suggestionsActivityWindow = 7 * 24 * time.Hour
+	// mutualFollowWeight is how many recent chirps one mutual follow is
+	// worth when ranking suggestions.
+	mutualFollowWeight = 10
+	suggestionsPerUser = 50
+	generalSuggestions = 100
+)
+
+// suggestionsHandler lists accounts the authenticated user might want to
+// follow, best first, up to ?limit=. Accounts followed by the people they
+// follow come first, ranked by mutual follows and recent activity, followed
+// by the most active accounts overall, so new users get suggestions too.
+func (cfg *apiConfig) suggestionsHandler(w http.ResponseWriter, r *http.Request) {
+	type suggestion struct {
+		UserSummary
+		MutualFollowCount int32 `json:"mutual_follow_count"`
+	}
+
+	userID, _ := userIDFromContext(r.Context())
+
+	page, err := parsePageParams(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows, err := cfg.dbQueries.ListSuggestions(r.Context(), database.ListSuggestionsParams{
+		UserID:     userID,
+		MaxResults: page.limit,
+	})
+	if err != nil {
+		log.Printf("Error listing suggestions: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list suggestions")
+		return
+	}
+
+	suggestions := make([]suggestion, 0, len(rows))
+	for _, row := range rows {
+		suggestions = append(suggestions, suggestion{
+			UserSummary:       cfg.userSummaryFromDB(row.User),
+			MutualFollowCount: row.MutualCount,
+		})
+	}
+	respondWithJSON(w, http.StatusOK, suggestions)
+}
+
+// runSuggestionsRefresher recomputes the suggestions now and then every
+// interval until ctx is cancelled.
+func (cfg *apiConfig) runSuggestionsRefresher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := cfg.refreshSuggestions(ctx)
+		if err != nil {
+			log.Printf("Error refreshing suggestions: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshSuggestions replaces every suggestion list in one transaction, so
+// readers never see a half-computed set.
+func (cfg *apiConfig) refreshSuggestions(ctx context.Context) error {
+	activeSince := time.Now().UTC().Add(-suggestionsActivityWindow)
+
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	err = qtx.DeleteSuggestions(ctx)
+	if err != nil {
+		return err
+	}
+	err = qtx.InsertPersonalSuggestions(ctx, database.InsertPersonalSuggestionsParams{
+		MutualWeight: mutualFollowWeight,
+		ActiveSince:  activeSince,
+		PerUser:      suggestionsPerUser,
+	})
+	if err != nil {
+		return err
+	}
+	err = qtx.InsertGeneralSuggestions(ctx, database.InsertGeneralSuggestionsParams{
+		ActiveSince: activeSince,
+		MaxResults:  generalSuggestions,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
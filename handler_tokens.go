@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/auth"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+const (
+	scopeReadChirps  = "read:chirps"
+	scopeWriteChirps = "write:chirps"
+)
+
+var knownScopes = []string{scopeReadChirps, scopeWriteChirps}
+
+// PersonalAccessToken is a long-lived, scoped token a user has minted for a
+// third-party app. The token value is only included when it is created.
+type PersonalAccessToken struct {
+	ID         uuid.UUID  `json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	Token      string     `json:"token,omitempty"`
+}
+
+func personalAccessTokenFromDB(pat database.PersonalAccessToken) PersonalAccessToken {
+	token := PersonalAccessToken{
+		ID:        pat.ID,
+		CreatedAt: pat.CreatedAt,
+		Name:      pat.Name,
+		Scopes:    pat.Scopes,
+	}
+	if pat.LastUsedAt.Valid {
+		token.LastUsedAt = &pat.LastUsedAt.Time
+	}
+	return token
+}
+
+func (cfg *apiConfig) createTokenHandler(w http.ResponseWriter, r *http.Request) {
+	type tokenParameters struct {
+		Name   string   `json:"name" validate:"required"`
+		Scopes []string `json:"scopes" validate:"required"`
+	}
+
+	userID, _ := userIDFromContext(r.Context())
+
+	params := tokenParameters{}
+	if !decodeParameters(w, r, &params) {
+		return
+	}
+	for _, scope := range params.Scopes {
+		if !slices.Contains(knownScopes, scope) {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Unknown scope %q", scope))
+			return
+		}
+	}
+
+	token, err := auth.MakePersonalAccessToken()
+	if err != nil {
+		log.Printf("Error generating access token: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create access token")
+		return
+	}
+
+	pat, err := cfg.dbQueries.CreatePersonalAccessToken(r.Context(), database.CreatePersonalAccessTokenParams{
+		UserID:    userID,
+		Name:      params.Name,
+		TokenHash: auth.HashToken(token),
+		Scopes:    params.Scopes,
+	})
+	if err != nil {
+		log.Printf("Error saving access token: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create access token")
+		return
+	}
+
+	resp := personalAccessTokenFromDB(pat)
+	resp.Token = token
+	respondWithJSON(w, http.StatusCreated, resp)
+}
+
+func (cfg *apiConfig) listTokensHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	pats, err := cfg.dbQueries.ListPersonalAccessTokensForUser(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error listing access tokens: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list access tokens")
+		return
+	}
+
+	tokens := make([]PersonalAccessToken, 0, len(pats))
+	for _, pat := range pats {
+		tokens = append(tokens, personalAccessTokenFromDB(pat))
+	}
+	respondWithJSON(w, http.StatusOK, tokens)
+}
+
+func (cfg *apiConfig) deleteTokenHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	tokenID, err := uuid.Parse(r.PathValue("tokenID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid token ID")
+		return
+	}
+
+	rows, err := cfg.dbQueries.RevokePersonalAccessToken(r.Context(), database.RevokePersonalAccessTokenParams{
+		ID:     tokenID,
+		UserID: userID,
+	})
+	if err != nil {
+		log.Printf("Error revoking access token: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't revoke access token")
+		return
+	}
+	if rows == 0 {
+		respondWithError(w, http.StatusNotFound, "Access token not found")
+		return
+	}
+	cfg.recordAuthEvent(r, userID, authEventAccessTokenRevoked, tokenID.String())
+
+	w.WriteHeader(http.StatusNoContent)
+}
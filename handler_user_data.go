@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserData is everything stored about a user, in one machine-readable
+// document. Unlike the zip export it is built on request and leaves out media
+// files, which are linked from the chirps instead.
+type UserData struct {
+	GeneratedAt     time.Time             `json:"generated_at"`
+	Account         User                  `json:"account"`
+	Profile         Profile               `json:"profile"`
+	Preferences     Preferences           `json:"preferences"`
+	Chirps          []Chirp               `json:"chirps"`
+	Drafts          []Draft               `json:"drafts"`
+	ScheduledChirps []ScheduledChirp      `json:"scheduled_chirps"`
+	Likes           []userDataChirpRef    `json:"likes"`
+	Bookmarks       []userDataChirpRef    `json:"bookmarks"`
+	Following       []userDataUserRef     `json:"following"`
+	FollowRequests  []userDataUserRef     `json:"follow_requests"`
+	Blocks          []userDataUserRef     `json:"blocks"`
+	Mutes           []userDataUserRef     `json:"mutes"`
+	Reports         []ChirpReport         `json:"reports"`
+	Notifications   []Notification        `json:"notifications"`
+	Sessions        []Session             `json:"sessions"`
+	AccessTokens    []PersonalAccessToken `json:"access_tokens"`
+	KnownDevices    []KnownDevice         `json:"known_devices"`
+	AuthEvents      []AuthEvent           `json:"auth_events"`
+}
+
+type userDataChirpRef struct {
+	ChirpID   uuid.UUID `json:"chirp_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type userDataUserRef struct {
+	UserID    uuid.UUID `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (cfg *apiConfig) userDataHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	data, err := cfg.collectUserData(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error collecting user data: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get user data")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, data)
+}
+
+// collectUserData gathers a user's data from every table that holds some.
+// Chirps include deleted ones that are still stored; secrets such as password
+// hashes and token values are never included.
+func (cfg *apiConfig) collectUserData(ctx context.Context, userID uuid.UUID) (UserData, error) {
+	q := cfg.dbQueries
+	data := UserData{GeneratedAt: time.Now().UTC()}
+
+	user, err := q.GetUserByID(ctx, userID)
+	if err != nil {
+		return UserData{}, err
+	}
+	data.Account = cfg.userFromDB(user)
+	data.Profile = cfg.profileFromDB(user)
+	data.Profile.Email = user.Email
+	data.Profile.Presence = presenceOf(user, true)
+
+	data.Preferences, err = cfg.loadPreferences(ctx, userID)
+	if err != nil {
+		return UserData{}, err
+	}
+
+	chirps, err := q.ListAllChirpsByUser(ctx, userID)
+	if err != nil {
+		return UserData{}, err
+	}
+	data.Chirps, err = cfg.chirpsFromDB(ctx, chirps)
+	if err != nil {
+		return UserData{}, err
+	}
+
+	drafts, err := q.ListDrafts(ctx, userID)
+	if err != nil {
+		return UserData{}, err
+	}
+	data.Drafts = make([]Draft, 0, len(drafts))
+	for _, draft := range drafts {
+		data.Drafts = append(data.Drafts, draftFromDB(draft))
+	}
+
+	scheduled, err := q.ListScheduledChirpsByUser(ctx, userID)
+	if err != nil {
+		return UserData{}, err
+	}
+	data.ScheduledChirps = make([]ScheduledChirp, 0, len(scheduled))
+	for _, chirp := range scheduled {
+		data.ScheduledChirps = append(data.ScheduledChirps, scheduledChirpFromDB(chirp))
+	}
+
+	likes, err := q.ListLikesByUser(ctx, userID)
+	if err != nil {
+		return UserData{}, err
+	}
+	data.Likes = make([]userDataChirpRef, 0, len(likes))
+	for _, like := range likes {
+		data.Likes = append(data.Likes, userDataChirpRef{ChirpID: like.ChirpID, CreatedAt: like.CreatedAt})
+	}
+
+	bookmarks, err := q.ListBookmarksByUser(ctx, userID)
+	if err != nil {
+		return UserData{}, err
+	}
+	data.Bookmarks = make([]userDataChirpRef, 0, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		data.Bookmarks = append(data.Bookmarks, userDataChirpRef{ChirpID: bookmark.ChirpID, CreatedAt: bookmark.CreatedAt})
+	}
+
+	follows, err := q.ListFollowsByUser(ctx, userID)
+	if err != nil {
+		return UserData{}, err
+	}
+	data.Following = make([]userDataUserRef, 0, len(follows))
+	for _, follow := range follows {
+		data.Following = append(data.Following, userDataUserRef{UserID: follow.FolloweeID, CreatedAt: follow.CreatedAt})
+	}
+
+	requests, err := q.ListFollowRequestsByUser(ctx, userID)
+	if err != nil {
+		return UserData{}, err
+	}
+	data.FollowRequests = make([]userDataUserRef, 0, len(requests))
+	for _, request := range requests {
+		data.FollowRequests = append(data.FollowRequests, userDataUserRef{UserID: request.TargetID, CreatedAt: request.CreatedAt})
+	}
+
+	blocks, err := q.ListBlocksByUser(ctx, userID)
+	if err != nil {
+		return UserData{}, err
+	}
+	data.Blocks = make([]userDataUserRef, 0, len(blocks))
+	for _, block := range blocks {
+		data.Blocks = append(data.Blocks, userDataUserRef{UserID: block.BlockedID, CreatedAt: block.CreatedAt})
+	}
+
+	mutes, err := q.ListMutesByUser(ctx, userID)
+	if err != nil {
+		return UserData{}, err
+	}
+	data.Mutes = make([]userDataUserRef, 0, len(mutes))
+	for _, mute := range mutes {
+		data.Mutes = append(data.Mutes, userDataUserRef{UserID: mute.MutedID, CreatedAt: mute.CreatedAt})
+	}
+
+	reports, err := q.ListChirpReportsByReporter(ctx, userID)
+	if err != nil {
+		return UserData{}, err
+	}
+	data.Reports = make([]ChirpReport, 0, len(reports))
+	for _, report := range reports {
+		data.Reports = append(data.Reports, chirpReportFromDB(report))
+	}
+
+	notifications, err := q.ListAllNotificationsForUser(ctx, userID)
+	if err != nil {
+		return UserData{}, err
+	}
+	data.Notifications = make([]Notification, 0, len(notifications))
+	for _, notification := range notifications {
+		data.Notifications = append(data.Notifications, notificationFromDB(notification))
+	}
+
+	tokens, err := q.ListActiveRefreshTokensForUser(ctx, userID)
+	if err != nil {
+		return UserData{}, err
+	}
+	data.Sessions = make([]Session, 0, len(tokens))
+	for _, token := range tokens {
+		data.Sessions = append(data.Sessions, sessionFromDB(token))
+	}
+
+	pats, err := q.ListPersonalAccessTokensForUser(ctx, userID)
+	if err != nil {
+		return UserData{}, err
+	}
+	data.AccessTokens = make([]PersonalAccessToken, 0, len(pats))
+	for _, pat := range pats {
+		data.AccessTokens = append(data.AccessTokens, personalAccessTokenFromDB(pat))
+	}
+
+	devices, err := q.ListKnownDevicesForUser(ctx, userID)
+	if err != nil {
+		return UserData{}, err
+	}
+	data.KnownDevices = make([]KnownDevice, 0, len(devices))
+	for _, device := range devices {
+		data.KnownDevices = append(data.KnownDevices, knownDeviceFromDB(device))
+	}
+
+	events, err := q.ListAuthEventsForUser(ctx, uuid.NullUUID{UUID: userID, Valid: true})
+	if err != nil {
+		return UserData{}, err
+	}
+	data.AuthEvents = make([]AuthEvent, 0, len(events))
+	for _, event := range events {
+		data.AuthEvents = append(data.AuthEvents, authEventFromDB(event))
+	}
+
+	return data, nil
+}
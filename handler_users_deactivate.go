@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+const (
+	// defaultDeactivationRetention is how long a deactivated account can be
+	// reactivated by logging in, unless USER_DEACTIVATION_RETENTION
+	// overrides it. After that it is deleted.
+	defaultDeactivationRetention = 30 * 24 * time.Hour
+	deactivationPurgeInterval    = time.Hour
+	deactivationPurgeBatchSize   = 100
+)
+
+// deactivateMeHandler deactivates the authenticated user's account: their
+// profile and chirps are hidden and they are signed out everywhere, but
+// nothing is deleted. Logging in again within the retention window
+// reactivates the account.
+func (cfg *apiConfig) deactivateMeHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	n, err := cfg.dbQueries.DeactivateUser(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error deactivating user: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't deactivate account")
+		return
+	}
+	if n == 0 {
+		respondWithError(w, http.StatusConflict, "Account is already deactivated")
+		return
+	}
+
+	err = cfg.revokeAllSessions(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error revoking refresh tokens: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't deactivate account")
+		return
+	}
+	cfg.recordAuthEvent(r, userID, authEventAccountDeactivated, "")
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// runDeactivatedAccountPurger deletes accounts that have stayed deactivated
+// past the retention window, every interval until ctx is cancelled.
+func (cfg *apiConfig) runDeactivatedAccountPurger(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for {
+			purged, err := cfg.purgeDeactivatedAccounts(ctx)
+			if err != nil {
+				log.Printf("Error purging deactivated accounts: %s", err)
+				break
+			}
+			if purged < deactivationPurgeBatchSize {
+				break
+			}
+		}
+	}
+}
+
+// purgeDeactivatedAccounts deletes one batch of expired deactivated
+// accounts, the same way users delete their own.
+func (cfg *apiConfig) purgeDeactivatedAccounts(ctx context.Context) (int, error) {
+	cutoff := time.Now().UTC().Add(-cfg.deactivationRetention)
+	userIDs, err := cfg.dbQueries.ListExpiredDeactivatedUsers(ctx, database.ListExpiredDeactivatedUsersParams{
+		Cutoff:     cutoff,
+		MaxResults: deactivationPurgeBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, userID := range userIDs {
+		files, err := cfg.deleteUser(ctx, userID)
+		if err != nil {
+			return 0, err
+		}
+		cfg.deleteUserFiles(ctx, files)
+	}
+	return len(userIDs), nil
+}
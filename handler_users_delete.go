@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+var errUserDeleted = errors.New("user already deleted")
+
+// deleteMeHandler deletes the authenticated user's account. The user row is
+// kept so that chirp and report history stays consistent, but it is
+// anonymized: email, handle and password are replaced and profile fields
+// cleared, so the account can't be logged into or found again. Chirps are
+// soft-deleted, and likes, rechirps, bookmarks, follows, blocks and mutes in
+// both directions, drafts, scheduled chirps, exports and the avatar are removed.
+func (cfg *apiConfig) deleteMeHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := userIDFromContext(r.Context())
+
+	files, err := cfg.deleteUser(r.Context(), userID)
+	if errors.Is(err, errUserDeleted) {
+		respondWithError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error deleting user: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't delete user")
+		return
+	}
+
+	cfg.deleteUserFiles(r.Context(), files)
+	cfg.recordAuthEvent(r, userID, authEventAccountDeleted, "")
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// deleteUser anonymizes the user and removes their content in one
+// transaction. It returns the store keys of their avatar and exports, to be
+// removed once the transaction has committed.
+func (cfg *apiConfig) deleteUser(ctx context.Context, userID uuid.UUID) (userFiles, error) {
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return userFiles{}, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	user, err := qtx.GetUserByID(ctx, userID)
+	if err != nil {
+		return userFiles{}, err
+	}
+
+	n, err := qtx.AnonymizeUser(ctx, userID)
+	if err != nil {
+		return userFiles{}, err
+	}
+	if n == 0 {
+		return userFiles{}, errUserDeleted
+	}
+
+	_, err = qtx.RevokeAllRefreshTokensForUser(ctx, userID)
+	if err != nil {
+		return userFiles{}, err
+	}
+	err = qtx.RevokeAllPersonalAccessTokensForUser(ctx, userID)
+	if err != nil {
+		return userFiles{}, err
+	}
+
+	for _, del := range []func(context.Context, uuid.UUID) error{
+		qtx.SoftDeleteChirpsByUser,
+		qtx.DeleteLikesByUser,
+		qtx.DeleteRechirpsByUser,
+		qtx.DeleteBookmarksByUser,
+		qtx.DeleteFollowsByUser,
+		qtx.DeleteFollowRequestsByUser,
+		qtx.DeleteBlocksByUser,
+		qtx.DeleteMutesByUser,
+		qtx.DeleteDraftsByUser,
+		qtx.DeleteScheduledChirpsByUser,
+	} {
+		err = del(ctx, userID)
+		if err != nil {
+			return userFiles{}, err
+		}
+	}
+
+	exports, err := qtx.DeleteExportsByUser(ctx, userID)
+	if err != nil {
+		return userFiles{}, err
+	}
+
+	var files userFiles
+	if user.AvatarKey.Valid {
+		files.media = append(files.media, user.AvatarKey.String)
+	}
+	for _, export := range exports {
+		if export.StorageKey.Valid {
+			files.exports = append(files.exports, export.StorageKey.String)
+		}
+	}
+	return files, tx.Commit()
+}
+
+// userFiles are the keys of a deleted user's files in the media and export
+// stores.
+type userFiles struct {
+	media   []string
+	exports []string
+}
+
+func (cfg *apiConfig) deleteUserFiles(ctx context.Context, files userFiles) {
+	for _, key := range files.media {
+		cfg.deleteMedia(ctx, key)
+	}
+	for _, key := range files.exports {
+		err := cfg.exportStore.Delete(ctx, key)
+		if err != nil {
+			log.Printf("Error removing export %s: %s", key, err)
+		}
+	}
+}
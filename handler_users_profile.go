@@ -0,0 +1,190 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+// Profile is the public view of a user. Email is only included when users
+// look at their own profile. Presence is "online", "recently_active" or
+// "offline", and is left out for users who hide it, except from themselves.
+type Profile struct {
+	ID            uuid.UUID  `json:"id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	Email         string     `json:"email,omitempty"`
+	Handle        string     `json:"handle"`
+	DisplayName   *string    `json:"display_name"`
+	Bio           *string    `json:"bio"`
+	Location      *string    `json:"location"`
+	Website       *string    `json:"website"`
+	PinnedChirpID *uuid.UUID `json:"pinned_chirp_id"`
+	AvatarURL     *string    `json:"avatar_url"`
+	IsPrivate     bool       `json:"is_private"`
+	IsVerified    bool       `json:"is_verified"`
+	Presence      string     `json:"presence,omitempty"`
+
+	ChirpCount          int64 `json:"chirp_count"`
+	FollowerCount       int64 `json:"follower_count"`
+	FollowingCount      int64 `json:"following_count"`
+	FollowedByMe        bool  `json:"followed_by_me"`
+	FollowRequestedByMe bool  `json:"follow_requested_by_me"`
+}
+
+func (cfg *apiConfig) profileFromDB(user database.User) Profile {
+	p := Profile{
+		ID:             user.ID,
+		CreatedAt:      user.CreatedAt,
+		UpdatedAt:      user.UpdatedAt,
+		Handle:         user.Handle,
+		IsPrivate:      user.IsPrivate,
+		IsVerified:     user.IsVerified,
+		Presence:       presenceOf(user, false),
+		ChirpCount:     user.ChirpCount,
+		FollowerCount:  user.FollowerCount,
+		FollowingCount: user.FollowingCount,
+	}
+	if user.DisplayName.Valid {
+		p.DisplayName = &user.DisplayName.String
+	}
+	if user.Bio.Valid {
+		p.Bio = &user.Bio.String
+	}
+	if user.Location.Valid {
+		p.Location = &user.Location.String
+	}
+	if user.Website.Valid {
+		p.Website = &user.Website.String
+	}
+	if user.PinnedChirpID.Valid {
+		p.PinnedChirpID = &user.PinnedChirpID.UUID
+	}
+	p.AvatarURL = cfg.avatarURL(user)
+	return p
+}
+
+// getProfileHandler returns a user's public profile. The path names the user
+// by ID or by handle, so profile URLs can use the handle. Suspended accounts
+// are reported as not found, except to their owners, and deleted or
+// deactivated accounts always are.
+func (cfg *apiConfig) getProfileHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := cfg.resolveUserRef(w, r, r.PathValue("userID"))
+	if !ok {
+		return
+	}
+	cfg.respondWithProfile(w, r, userID)
+}
+
+// respondWithProfile writes the profile of userID as seen by the requester.
+func (cfg *apiConfig) respondWithProfile(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	viewerID, _ := userIDFromContext(r.Context())
+	self := viewerID == userID
+
+	user, err := cfg.dbQueries.GetUserByID(r.Context(), userID)
+	if err == nil && (user.DeletedAt.Valid || user.DeactivatedAt.Valid || (user.SuspendedAt.Valid && !self)) {
+		err = sql.ErrNoRows
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Error getting user profile: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get user")
+		return
+	}
+
+	profiles := []Profile{cfg.profileFromDB(user)}
+	err = cfg.loadFollowStats(r.Context(), profiles)
+	if err != nil {
+		log.Printf("Error loading follow stats: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get user")
+		return
+	}
+	if self {
+		profiles[0].Email = user.Email
+		profiles[0].Presence = presenceOf(user, true)
+	}
+	respondWithJSON(w, http.StatusOK, profiles[0])
+}
+
+// resolveUserRef returns the ID of the user named by ref, either a user ID or
+// a handle. On failure it writes a 400, 404 or 500 response and returns false.
+func (cfg *apiConfig) resolveUserRef(w http.ResponseWriter, r *http.Request, ref string) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(ref)
+	if err == nil {
+		return userID, true
+	}
+	if validateHandle(ref) != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid user ID or handle")
+		return uuid.Nil, false
+	}
+
+	user, err := cfg.dbQueries.GetUserByHandle(r.Context(), ref)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusNotFound, "User not found")
+		return uuid.Nil, false
+	}
+	if err != nil {
+		log.Printf("Error getting user by handle: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get user")
+		return uuid.Nil, false
+	}
+	return user.ID, true
+}
+
+// listProfilesHandler returns the profiles of the users named by ?handle=,
+// which may be repeated or comma-separated, for clients resolving
+// @mentions. Unknown handles are left out, so the result may be shorter than
+// the request.
+func (cfg *apiConfig) listProfilesHandler(w http.ResponseWriter, r *http.Request) {
+	var handles []string
+	for _, v := range r.URL.Query()["handle"] {
+		for _, handle := range strings.Split(v, ",") {
+			if validateHandle(handle) != nil {
+				respondWithError(w, http.StatusBadRequest, "Invalid handle")
+				return
+			}
+			handles = append(handles, strings.ToLower(handle))
+		}
+	}
+	if len(handles) == 0 {
+		respondWithError(w, http.StatusBadRequest, "handle is required")
+		return
+	}
+	if len(handles) > maxPageSize {
+		respondWithError(w, http.StatusBadRequest, "Too many handles")
+		return
+	}
+
+	rows, err := cfg.dbQueries.ListUserProfilesByHandles(r.Context(), handles)
+	if err != nil {
+		log.Printf("Error listing user profiles: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get users")
+		return
+	}
+
+	viewerID, _ := userIDFromContext(r.Context())
+	profiles := make([]Profile, 0, len(rows))
+	for _, user := range rows {
+		profile := cfg.profileFromDB(user)
+		if user.ID == viewerID {
+			profile.Email = user.Email
+		}
+		profiles = append(profiles, profile)
+	}
+	err = cfg.loadFollowStats(r.Context(), profiles)
+	if err != nil {
+		log.Printf("Error loading follow stats: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get users")
+		return
+	}
+	respondWithJSON(w, http.StatusOK, profiles)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+// updateProfileHandler changes the authenticated user's profile fields.
+// Fields left out of the request are unchanged; an empty string clears one.
+// is_private makes the account's chirps visible to approved followers only,
+// and show_presence=false hides whether the user is online from others.
+func (cfg *apiConfig) updateProfileHandler(w http.ResponseWriter, r *http.Request) {
+	type profileParameters struct {
+		DisplayName  *string `json:"display_name" validate:"max=50"`
+		Bio          *string `json:"bio" validate:"max=160"`
+		Location     *string `json:"location" validate:"max=30"`
+		Website      *string `json:"website" validate:"max=100,url"`
+		IsPrivate    *bool   `json:"is_private"`
+		ShowPresence *bool   `json:"show_presence"`
+	}
+
+	userID, _ := userIDFromContext(r.Context())
+
+	params := profileParameters{}
+	if !decodeParameters(w, r, &params) {
+		return
+	}
+
+	user, err := cfg.dbQueries.GetUserByID(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error getting user: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't update profile")
+		return
+	}
+
+	update := database.UpdateUserProfileParams{
+		ID:           userID,
+		DisplayName:  user.DisplayName,
+		Bio:          user.Bio,
+		Location:     user.Location,
+		Website:      user.Website,
+		IsPrivate:    user.IsPrivate,
+		ShowPresence: user.ShowPresence,
+	}
+	if params.IsPrivate != nil {
+		update.IsPrivate = *params.IsPrivate
+	}
+	if params.ShowPresence != nil {
+		update.ShowPresence = *params.ShowPresence
+	}
+	fields := []struct {
+		value *string
+		dst   *sql.NullString
+	}{
+		{params.DisplayName, &update.DisplayName},
+		{params.Bio, &update.Bio},
+		{params.Location, &update.Location},
+		{params.Website, &update.Website},
+	}
+	for _, f := range fields {
+		if f.value == nil {
+			continue
+		}
+		v := strings.TrimSpace(*f.value)
+		*f.dst = sql.NullString{String: v, Valid: v != ""}
+	}
+
+	_, err = cfg.dbQueries.UpdateUserProfile(r.Context(), update)
+	if err != nil {
+		log.Printf("Error updating profile: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't update profile")
+		return
+	}
+
+	cfg.respondWithProfile(w, r, userID)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+// likePatternEscaper escapes LIKE wildcards so user input matches literally.
+var likePatternEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// searchUsersHandler finds users whose handle or display name contains ?q=
+// (a leading '@' is ignored), ranking an exact handle match first, then
+// handle and display name prefix matches. Results are paged with ?limit= and
+// ?offset=, like chirp search.
+func (cfg *apiConfig) searchUsersHandler(w http.ResponseWriter, r *http.Request) {
+	type searchResponse struct {
+		Users      []Profile `json:"users"`
+		NextOffset *int      `json:"next_offset"`
+	}
+
+	query := r.URL.Query()
+	q := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(query.Get("q")), "@"))
+	if q == "" {
+		respondWithError(w, http.StatusBadRequest, "q is required")
+		return
+	}
+
+	page, err := parsePageParams(r)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	offset := 0
+	if v := query.Get("offset"); v != "" {
+		offset, err = strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			respondWithError(w, http.StatusBadRequest, "Invalid offset")
+			return
+		}
+	}
+
+	escaped := likePatternEscaper.Replace(q)
+	rows, err := cfg.dbQueries.SearchUsers(r.Context(), database.SearchUsersParams{
+		Pattern:    "%" + escaped + "%",
+		Prefix:     escaped + "%",
+		Query:      q,
+		MaxResults: page.limit,
+		Skip:       int32(offset),
+	})
+	if err != nil {
+		log.Printf("Error searching users: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't search users")
+		return
+	}
+
+	total, err := cfg.dbQueries.CountSearchUsers(r.Context(), "%"+escaped+"%")
+	if err != nil {
+		log.Printf("Error counting user search results: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't search users")
+		return
+	}
+
+	resp := searchResponse{Users: make([]Profile, 0, len(rows))}
+	for _, user := range rows {
+		resp.Users = append(resp.Users, cfg.profileFromDB(user))
+	}
+	err = cfg.loadFollowStats(r.Context(), resp.Users)
+	if err != nil {
+		log.Printf("Error loading follow stats: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't search users")
+		return
+	}
+	if len(rows) == int(page.limit) {
+		next := offset + len(rows)
+		resp.NextOffset = &next
+	}
+	setOffsetPageHeaders(w, r, offset, int(page.limit), total)
+	respondWithJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/auth"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+const emailChangeTokenTTL = 24 * time.Hour
+
+var errEmailTaken = errors.New("email is already taken")
+
+// updateUserHandler changes the authenticated user's password. A new email
+// is only stored as pending and a confirmation token is sent to it; the
+// email changes once the token is confirmed. Either change needs the current
+// password, and a new password signs out every device, so a stolen session
+// alone can't take over the account.
+func (cfg *apiConfig) updateUserHandler(w http.ResponseWriter, r *http.Request) {
+	type updateUserParameters struct {
+		Email           string `json:"email" validate:"required,email"`
+		Password        string `json:"password" validate:"required"`
+		CurrentPassword string `json:"current_password" validate:"required"`
+	}
+
+	userID, ok := userIDFromContext(r.Context())
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT")
+		return
+	}
+
+	params := updateUserParameters{}
+	if !decodeParameters(w, r, &params) {
+		return
+	}
+
+	if !cfg.checkPasswordPolicy(w, params.Password) {
+		return
+	}
+
+	user, err := cfg.dbQueries.GetUserByID(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error getting user: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't update user")
+		return
+	}
+
+	if user.LockedUntil.Valid && user.LockedUntil.Time.After(time.Now().UTC()) {
+		respondLocked(w, user.LockedUntil.Time)
+		return
+	}
+	err = auth.CheckPasswordHash(params.CurrentPassword, user.HashedPassword)
+	if err != nil {
+		cfg.recordFailedLogin(r.Context(), user.ID, clientIP(r))
+		respondWithError(w, http.StatusUnauthorized, "Incorrect password")
+		return
+	}
+	passwordChanged := auth.CheckPasswordHash(params.Password, user.HashedPassword) != nil
+
+	pendingEmail := user.PendingEmail
+	emailChanged := params.Email != user.Email
+	if emailChanged {
+		_, err = cfg.dbQueries.GetUserByEmail(r.Context(), params.Email)
+		if err == nil {
+			respondWithError(w, http.StatusConflict, "Email is already taken")
+			return
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Error looking up user: %s", err)
+			respondWithError(w, http.StatusInternalServerError, "Couldn't update user")
+			return
+		}
+		pendingEmail = sql.NullString{String: params.Email, Valid: true}
+	}
+
+	hashedPassword, err := cfg.passwordHasher.Hash(params.Password)
+	if err != nil {
+		log.Printf("Error hashing password: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't hash password")
+		return
+	}
+
+	user, err = cfg.dbQueries.UpdateUser(r.Context(), database.UpdateUserParams{
+		ID:             userID,
+		HashedPassword: hashedPassword,
+		PendingEmail:   pendingEmail,
+	})
+	if err != nil {
+		log.Printf("Error updating user: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't update user")
+		return
+	}
+	if passwordChanged {
+		err = cfg.revokeAllSessions(r.Context(), user.ID)
+		if err != nil {
+			log.Printf("Error revoking refresh tokens: %s", err)
+			respondWithError(w, http.StatusInternalServerError, "Couldn't update user")
+			return
+		}
+		cfg.recordAuthEvent(r, user.ID, authEventPasswordChanged, "")
+	}
+
+	if emailChanged {
+		err = cfg.sendEmailChangeConfirmation(r.Context(), user.ID, params.Email)
+		if err != nil {
+			log.Printf("Error sending email change confirmation: %s", err)
+			respondWithError(w, http.StatusInternalServerError, "Couldn't send confirmation email")
+			return
+		}
+		cfg.recordAuthEvent(r, user.ID, authEventEmailChangeRequested, params.Email)
+	}
+
+	respondWithJSON(w, http.StatusOK, cfg.userFromDB(user))
+}
+
+// sendEmailChangeConfirmation emails a one-time token to the new address.
+func (cfg *apiConfig) sendEmailChangeConfirmation(ctx context.Context, userID uuid.UUID, email string) error {
+	token, err := auth.MakeRefreshToken()
+	if err != nil {
+		return err
+	}
+
+	_, err = cfg.dbQueries.CreateEmailChangeToken(ctx, database.CreateEmailChangeTokenParams{
+		TokenHash: auth.HashToken(token),
+		UserID:    userID,
+		Email:     email,
+		ExpiresAt: time.Now().UTC().Add(emailChangeTokenTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Use this token to confirm %s as your Chirpy email address within the next day:\n\n%s\n\nIf you didn't ask for this, you can ignore this email.", email, token)
+	return cfg.mailer.Send(ctx, email, "Confirm your new Chirpy email", body)
+}
+
+// confirmEmailChangeHandler swaps in the pending email of the token's
+// account. Only the token for the most recently requested email works.
+func (cfg *apiConfig) confirmEmailChangeHandler(w http.ResponseWriter, r *http.Request) {
+	type confirmEmailParameters struct {
+		Token string `json:"token" validate:"required"`
+	}
+
+	params := confirmEmailParameters{}
+	if !decodeParameters(w, r, &params) {
+		return
+	}
+
+	user, oldEmail, err := cfg.confirmEmailChange(r.Context(), auth.HashToken(params.Token))
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusUnauthorized, "Invalid or expired confirmation token")
+		return
+	}
+	if errors.Is(err, errEmailTaken) {
+		respondWithError(w, http.StatusConflict, "Email is already taken")
+		return
+	}
+	if err != nil {
+		log.Printf("Error confirming email change: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't change email")
+		return
+	}
+
+	cfg.recordAuthEvent(r, user.ID, authEventEmailChanged, user.Email)
+	body := fmt.Sprintf("The email address of your Chirpy account was changed to %s. If you didn't do this, reset your password and contact support.", user.Email)
+	err = cfg.mailer.Send(r.Context(), oldEmail, "Your Chirpy email was changed", body)
+	if err != nil {
+		log.Printf("Error sending email change notice: %s", err)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// confirmEmailChange consumes the token and swaps the email in one
+// transaction. It returns the updated user and their previous email.
+func (cfg *apiConfig) confirmEmailChange(ctx context.Context, tokenHash string) (database.User, string, error) {
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return database.User{}, "", err
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	token, err := qtx.ConsumeEmailChangeToken(ctx, tokenHash)
+	if err != nil {
+		return database.User{}, "", err
+	}
+	_, err = qtx.GetUserByEmail(ctx, token.Email)
+	if err == nil {
+		return database.User{}, "", errEmailTaken
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return database.User{}, "", err
+	}
+	old, err := qtx.GetUserByID(ctx, token.UserID)
+	if err != nil {
+		return database.User{}, "", err
+	}
+	user, err := qtx.ConfirmPendingEmail(ctx, database.ConfirmPendingEmailParams{
+		ID:    token.UserID,
+		Email: token.Email,
+	})
+	if err != nil {
+		return database.User{}, "", err
+	}
+	return user, old.Email, tx.Commit()
+}
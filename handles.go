@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+const (
+	minHandleLength = 3
+	maxHandleLength = 15
+)
+
+// handlePattern matches the same characters mentionPattern picks up after
+// an '@', so every handle can be mentioned.
+var handlePattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// reservedHandles can't be chosen because profile URLs using them would
+// collide with other /api/users/... routes. Some, like "me", are also shorter
+// than minHandleLength, but are listed so lowering it stays safe.
+var reservedHandles = map[string]bool{
+	"by_handle": true,
+	"chirps":    true,
+	"followers": true,
+	"following": true,
+	"me":        true,
+	"search":    true,
+}
+
+var (
+	errInvalidHandle  = errors.New("Handle must be 3 to 15 letters, digits or underscores")
+	errReservedHandle = errors.New("Handle is reserved")
+)
+
+// validateHandle checks a handle chosen at signup. Handles keep the case
+// they were chosen in but are unique and matched case-insensitively.
+func validateHandle(handle string) error {
+	if len(handle) < minHandleLength || len(handle) > maxHandleLength || !handlePattern.MatchString(handle) {
+		return errInvalidHandle
+	}
+	if reservedHandles[strings.ToLower(handle)] {
+		return errReservedHandle
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	healthStatusOK   = "ok"
+	healthStatusDown = "down"
+
+	// healthCheckTimeout bounds each dependency check, so a hung database
+	// makes the check fail rather than hang the prober.
+	healthCheckTimeout = 2 * time.Second
+)
+
+type componentHealth struct {
+	Status string `json:"status"`
+	// LatencyMS is how long the check took, in milliseconds.
+	LatencyMS int64 `json:"latency_ms"`
+}
+
+type healthResponse struct {
+	Status     string                     `json:"status"`
+	Components map[string]componentHealth `json:"components,omitempty"`
+}
+
+// schemaMigrations are the goose migrations the code expects to have been
+// applied.
+//
+//go:embed sql/schema/*.sql
+var schemaMigrations embed.FS
+
+// livenessHandler reports that the process is up and serving requests. It
+// checks nothing else, so orchestrators only restart the server when it is
+// wedged, not when the database is.
+func livenessHandler(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, healthResponse{Status: healthStatusOK})
+}
+
+// readinessHandler checks that the server can serve traffic: the database
+// is reachable and has every migration applied, and maintenance mode is off
+// unless MAINTENANCE_FAILS_READINESS is false. It answers 503 otherwise, so
+// orchestrators route traffic elsewhere until it recovers. Failures are
+// logged rather than returned, since the endpoint is public.
+func (cfg *apiConfig) readinessHandler(w http.ResponseWriter, r *http.Request) {
+	resp := healthResponse{
+		Status: healthStatusOK,
+		Components: map[string]componentHealth{
+			"database":    checkComponent(r.Context(), "database", cfg.checkDatabase),
+			"migrations":  checkComponent(r.Context(), "migrations", cfg.checkMigrations),
+			"maintenance": checkComponent(r.Context(), "maintenance", cfg.maintenance.check),
+		},
+	}
+
+	status := http.StatusOK
+	for _, c := range resp.Components {
+		if c.Status != healthStatusOK {
+			resp.Status = healthStatusDown
+			status = http.StatusServiceUnavailable
+		}
+	}
+	respondWithJSON(w, status, resp)
+}
+
+// checkDatabase pings Postgres and runs a trivial query, which also catches
+// a database that accepts connections but can't serve them.
+func (cfg *apiConfig) checkDatabase(ctx context.Context) error {
+	err := cfg.db.PingContext(ctx)
+	if err != nil {
+		return err
+	}
+	var one int
+	return cfg.db.QueryRowContext(ctx, "SELECT 1").Scan(&one)
+}
+
+// checkMigrations compares the database's goose version with the newest
+// migration in sql/schema.
+func (cfg *apiConfig) checkMigrations(ctx context.Context) error {
+	want, err := latestMigrationVersion()
+	if err != nil {
+		return err
+	}
+	var have int64
+	err = cfg.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version_id), 0) FROM goose_db_version WHERE is_applied").Scan(&have)
+	if err != nil {
+		return err
+	}
+	if have < want {
+		return fmt.Errorf("database is at migration %d, want %d", have, want)
+	}
+	return nil
+}
+
+// latestMigrationVersion is the version of the newest embedded migration,
+// from its file name such as 055_create_idempotency_keys_table.sql.
+var latestMigrationVersion = sync.OnceValues(func() (int64, error) {
+	names, err := fs.Glob(schemaMigrations, "sql/schema/*.sql")
+	if err != nil {
+		return 0, err
+	}
+	var latest int64
+	for _, name := range names {
+		prefix, _, _ := strings.Cut(strings.TrimPrefix(name, "sql/schema/"), "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("migration %s: %w", name, err)
+		}
+		latest = max(latest, version)
+	}
+	return latest, nil
+})
+
+func checkComponent(ctx context.Context, name string, check func(context.Context) error) componentHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	c := componentHealth{Status: healthStatusOK, LatencyMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		log.Printf("Health check of %s failed: %s", name, err)
+		c.Status = healthStatusDown
+	}
+	return c
+}
@@ -0,0 +1,39 @@
+package main
+
+import "net/http"
+
+const defaultHTTP2MaxConcurrentStreams = 250
+
+// http2Config says how the server speaks HTTP/2. It always does over TLS;
+// cleartext HTTP/2 (h2c) is for load balancers that terminate TLS and talk
+// HTTP/2 to the server, and is off by default since browsers don't use it.
+type http2Config struct {
+	cleartext bool
+	// maxConcurrentStreams caps the requests a client may multiplex over one
+	// connection.
+	maxConcurrentStreams int
+}
+
+// loadHTTP2Config reads HTTP2_CLEARTEXT and HTTP2_MAX_CONCURRENT_STREAMS.
+func loadHTTP2Config() (http2Config, error) {
+	cleartext, err := envBool("HTTP2_CLEARTEXT", false)
+	if err != nil {
+		return http2Config{}, err
+	}
+	maxStreams, err := envInt("HTTP2_MAX_CONCURRENT_STREAMS", defaultHTTP2MaxConcurrentStreams)
+	if err != nil {
+		return http2Config{}, err
+	}
+	return http2Config{cleartext: cleartext, maxConcurrentStreams: maxStreams}, nil
+}
+
+// apply sets the protocols the server accepts. h2c clients must use prior
+// knowledge: the server doesn't upgrade HTTP/1.1 connections to HTTP/2.
+func (c http2Config) apply(server *http.Server) {
+	var protocols http.Protocols
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(c.cleartext)
+	server.Protocols = &protocols
+	server.HTTP2 = &http.HTTP2Config{MaxConcurrentStreams: c.maxConcurrentStreams}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+const (
+	// idempotencyKeyTTL is how long a response is kept for replay.
+	idempotencyKeyTTL           = 24 * time.Hour
+	idempotencyKeyPurgeInterval = time.Hour
+	maxIdempotencyKeyLength     = 255
+)
+
+// middlewareIdempotency lets clients retry a POST safely by sending the same
+// Idempotency-Key header: the first response for a key is stored, per user
+// and route, and replayed to later requests with it. Server errors and rate
+// limiting aren't stored, so those can be retried for real. Requests without
+// the header are unaffected.
+func (cfg *apiConfig) middlewareIdempotency(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if key == "" {
+			next(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			respondWithError(w, http.StatusBadRequest, "Idempotency-Key is too long")
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondWithError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+			return
+		}
+		if err != nil {
+			log.Printf("Error reading request body: %s", err)
+			respondWithError(w, http.StatusInternalServerError, "Couldn't read request body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		requestHash := sha256.Sum256(body)
+
+		id := database.GetIdempotencyKeyParams{
+			UserID: viewerFromContext(r.Context()),
+			Route:  canonicalAPIPattern(r.Pattern),
+			Key:    key,
+		}
+
+		reserved, err := cfg.dbQueries.ReserveIdempotencyKey(r.Context(), database.ReserveIdempotencyKeyParams{
+			UserID:      id.UserID,
+			Route:       id.Route,
+			Key:         id.Key,
+			RequestHash: requestHash[:],
+		})
+		if err != nil {
+			log.Printf("Error reserving idempotency key: %s", err)
+			respondWithError(w, http.StatusInternalServerError, "Couldn't process request")
+			return
+		}
+		if reserved == 0 {
+			cfg.replayIdempotentResponse(w, r, id, requestHash[:])
+			return
+		}
+
+		// Release the key unless the response gets stored, including when
+		// the handler panics, so the client can retry.
+		stored := false
+		defer func() {
+			if stored {
+				return
+			}
+			err := cfg.dbQueries.DeleteIdempotencyKey(context.WithoutCancel(r.Context()), database.DeleteIdempotencyKeyParams(id))
+			if err != nil {
+				log.Printf("Error releasing idempotency key: %s", err)
+			}
+		}()
+
+		buf := &bufferedResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		next(buf, r)
+
+		if buf.status < http.StatusInternalServerError && buf.status != http.StatusTooManyRequests {
+			err = cfg.dbQueries.CompleteIdempotencyKey(r.Context(), database.CompleteIdempotencyKeyParams{
+				UserID:       id.UserID,
+				Route:        id.Route,
+				Key:          id.Key,
+				StatusCode:   sql.NullInt32{Int32: int32(buf.status), Valid: true},
+				ContentType:  sql.NullString{String: w.Header().Get("Content-Type"), Valid: true},
+				ResponseBody: buf.body.Bytes(),
+			})
+			if err != nil {
+				log.Printf("Error saving idempotency key: %s", err)
+			} else {
+				stored = true
+			}
+		}
+
+		w.WriteHeader(buf.status)
+		w.Write(buf.body.Bytes())
+	}
+}
+
+// replayIdempotentResponse answers a request whose key was already used. A
+// different body under the same key is a client bug, and a key whose first
+// request is still running can't be replayed yet.
+func (cfg *apiConfig) replayIdempotentResponse(w http.ResponseWriter, r *http.Request, id database.GetIdempotencyKeyParams, requestHash []byte) {
+	stored, err := cfg.dbQueries.GetIdempotencyKey(r.Context(), id)
+	if err != nil {
+		log.Printf("Error getting idempotency key: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't process request")
+		return
+	}
+	if !bytes.Equal(stored.RequestHash, requestHash) {
+		respondWithError(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used with a different request")
+		return
+	}
+	if !stored.StatusCode.Valid {
+		respondWithError(w, http.StatusConflict, "A request with this Idempotency-Key is still in progress")
+		return
+	}
+
+	if stored.ContentType.String != "" {
+		w.Header().Set("Content-Type", stored.ContentType.String)
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(int(stored.StatusCode.Int32))
+	w.Write(stored.ResponseBody)
+}
+
+// runIdempotencyKeyPurger deletes keys older than idempotencyKeyTTL every
+// interval until ctx is cancelled.
+func (cfg *apiConfig) runIdempotencyKeyPurger(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		_, err := cfg.dbQueries.DeleteExpiredIdempotencyKeys(ctx, time.Now().UTC().Add(-idempotencyKeyTTL))
+		if err != nil {
+			log.Printf("Error purging idempotency keys: %s", err)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// GetAPIKey extracts the key from an "Authorization: ApiKey <key>" header.
+func GetAPIKey(headers http.Header) (string, error) {
+	authHeader := headers.Get("Authorization")
+	if authHeader == "" {
+		return "", ErrNoAuthHeaderIncluded
+	}
+	splitAuth := strings.Fields(authHeader)
+	if len(splitAuth) != 2 || splitAuth[0] != "ApiKey" {
+		return "", errors.New("malformed authorization header")
+	}
+	return splitAuth[1], nil
+}
+
+// MakeAPIKey returns a new random 256-bit API key encoded as hex.
+func MakeAPIKey() (string, error) {
+	return randomHex(32)
+}
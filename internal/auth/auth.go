@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrNoAuthHeaderIncluded is returned when a request has no Authorization header.
+var ErrNoAuthHeaderIncluded = errors.New("no authorization header included")
+
+// GetBearerToken extracts the token from an "Authorization: Bearer <token>" header.
+func GetBearerToken(headers http.Header) (string, error) {
+	authHeader := headers.Get("Authorization")
+	if authHeader == "" {
+		return "", ErrNoAuthHeaderIncluded
+	}
+	splitAuth := strings.Fields(authHeader)
+	if len(splitAuth) != 2 || !strings.EqualFold(splitAuth[0], "Bearer") {
+		return "", errors.New("malformed authorization header")
+	}
+	return splitAuth[1], nil
+}
+
+// MakeRefreshToken returns a random 256-bit opaque token encoded as hex.
+func MakeRefreshToken() (string, error) {
+	return randomHex(32)
+}
+
+// HashToken returns the hex SHA-256 digest under which an opaque token (API
+// key, reset token) is stored. Such tokens are high-entropy random values, so
+// a fast hash is sufficient.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	key := make([]byte, n)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(key), nil
+}
@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const jwtIssuer = "chirpy"
+
+// TokenSigner issues and verifies access tokens with a particular algorithm
+// and key material.
+type TokenSigner interface {
+	MakeJWT(userID uuid.UUID, expiresIn time.Duration) (string, error)
+	// MakeImpersonationJWT issues a token acting as userID on behalf of
+	// impersonatorID, recorded in the RFC 8693 "act" claim.
+	MakeImpersonationJWT(userID, impersonatorID uuid.UUID, expiresIn time.Duration) (string, error)
+	ValidateJWT(tokenString string) (uuid.UUID, error)
+	ParseJWT(tokenString string) (TokenClaims, error)
+}
+
+// TokenClaims are the verified contents of an access token.
+type TokenClaims struct {
+	UserID uuid.UUID
+	// ImpersonatorID is uuid.Nil unless the token was issued to an admin
+	// acting as UserID.
+	ImpersonatorID uuid.UUID
+}
+
+type claims struct {
+	jwt.RegisteredClaims
+	Actor *actorClaim `json:"act,omitempty"`
+}
+
+type actorClaim struct {
+	Subject string `json:"sub"`
+}
+
+// HMACSigner is a TokenSigner using HS256 with a shared secret.
+type HMACSigner struct {
+	Secret string
+}
+
+func (s HMACSigner) MakeJWT(userID uuid.UUID, expiresIn time.Duration) (string, error) {
+	return MakeJWT(userID, s.Secret, expiresIn)
+}
+
+func (s HMACSigner) MakeImpersonationJWT(userID, impersonatorID uuid.UUID, expiresIn time.Duration) (string, error) {
+	c := newClaims(userID, expiresIn)
+	c.Actor = &actorClaim{Subject: impersonatorID.String()}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString([]byte(s.Secret))
+}
+
+func (s HMACSigner) ValidateJWT(tokenString string) (uuid.UUID, error) {
+	return ValidateJWT(tokenString, s.Secret)
+}
+
+func (s HMACSigner) ParseJWT(tokenString string) (TokenClaims, error) {
+	return parseJWT(tokenString, jwt.SigningMethodHS256.Alg(), s.keyFunc)
+}
+
+func (s HMACSigner) keyFunc(token *jwt.Token) (interface{}, error) {
+	return []byte(s.Secret), nil
+}
+
+// MakeJWT signs an HS256 access token whose subject is the given user ID.
+func MakeJWT(userID uuid.UUID, tokenSecret string, expiresIn time.Duration) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, newClaims(userID, expiresIn))
+	return token.SignedString([]byte(tokenSecret))
+}
+
+// ValidateJWT verifies the signature and expiry of an access token and
+// returns the user ID stored in its subject.
+func ValidateJWT(tokenString, tokenSecret string) (uuid.UUID, error) {
+	c, err := HMACSigner{Secret: tokenSecret}.ParseJWT(tokenString)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return c.UserID, nil
+}
+
+func newClaims(userID uuid.UUID, expiresIn time.Duration) claims {
+	now := time.Now().UTC()
+	return claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    jwtIssuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiresIn)),
+			Subject:   userID.String(),
+		},
+	}
+}
+
+func parseJWT(tokenString, alg string, keyFunc jwt.Keyfunc) (TokenClaims, error) {
+	c := claims{}
+	token, err := jwt.ParseWithClaims(
+		tokenString,
+		&c,
+		keyFunc,
+		jwt.WithValidMethods([]string{alg}),
+	)
+	if err != nil {
+		return TokenClaims{}, err
+	}
+	if !token.Valid {
+		return TokenClaims{}, errors.New("invalid token")
+	}
+
+	if c.Issuer != jwtIssuer {
+		return TokenClaims{}, errors.New("invalid issuer")
+	}
+
+	userID, err := uuid.Parse(c.Subject)
+	if err != nil {
+		return TokenClaims{}, fmt.Errorf("invalid user ID: %w", err)
+	}
+	result := TokenClaims{UserID: userID}
+
+	if c.Actor != nil {
+		result.ImpersonatorID, err = uuid.Parse(c.Actor.Subject)
+		if err != nil {
+			return TokenClaims{}, fmt.Errorf("invalid impersonator ID: %w", err)
+		}
+	}
+	return result, nil
+}
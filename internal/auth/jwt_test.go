@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func TestMakeAndValidateJWT(t *testing.T) {
+	userID := uuid.New()
+	token, err := MakeJWT(userID, "secret", time.Hour)
+	if err != nil {
+		t.Fatalf("MakeJWT() error = %v", err)
+	}
+
+	got, err := ValidateJWT(token, "secret")
+	if err != nil {
+		t.Fatalf("ValidateJWT() error = %v", err)
+	}
+	if got != userID {
+		t.Errorf("ValidateJWT() = %v, want %v", got, userID)
+	}
+}
+
+func TestValidateJWT(t *testing.T) {
+	userID := uuid.New()
+	validToken, _ := MakeJWT(userID, "secret", time.Hour)
+	expiredToken, _ := MakeJWT(userID, "secret", -time.Minute)
+
+	otherIssuer := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Issuer:    "someone-else",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		Subject:   userID.String(),
+	})
+	otherIssuerToken, _ := otherIssuer.SignedString([]byte("secret"))
+
+	badSubject := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Issuer:    jwtIssuer,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		Subject:   "not-a-uuid",
+	})
+	badSubjectToken, _ := badSubject.SignedString([]byte("secret"))
+
+	noneToken, _ := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.RegisteredClaims{
+		Issuer:  jwtIssuer,
+		Subject: userID.String(),
+	}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+
+	tests := []struct {
+		name        string
+		tokenString string
+		tokenSecret string
+		wantUserID  uuid.UUID
+		wantErr     bool
+	}{
+		{"valid token", validToken, "secret", userID, false},
+		{"wrong secret", validToken, "wrong", uuid.Nil, true},
+		{"expired token", expiredToken, "secret", uuid.Nil, true},
+		{"malformed token", "not.a.jwt", "secret", uuid.Nil, true},
+		{"empty token", "", "secret", uuid.Nil, true},
+		{"wrong issuer", otherIssuerToken, "secret", uuid.Nil, true},
+		{"non-uuid subject", badSubjectToken, "secret", uuid.Nil, true},
+		{"unsigned token", noneToken, "secret", uuid.Nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateJWT(tt.tokenString, tt.tokenSecret)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateJWT() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.wantUserID {
+				t.Errorf("ValidateJWT() = %v, want %v", got, tt.wantUserID)
+			}
+		})
+	}
+}
+
+func TestImpersonationJWT(t *testing.T) {
+	userID, adminID := uuid.New(), uuid.New()
+	signer := HMACSigner{Secret: "secret"}
+
+	token, err := signer.MakeImpersonationJWT(userID, adminID, time.Minute)
+	if err != nil {
+		t.Fatalf("MakeImpersonationJWT() error = %v", err)
+	}
+	claims, err := signer.ParseJWT(token)
+	if err != nil {
+		t.Fatalf("ParseJWT() error = %v", err)
+	}
+	if claims.UserID != userID || claims.ImpersonatorID != adminID {
+		t.Errorf("ParseJWT() = %+v, want user %v impersonated by %v", claims, userID, adminID)
+	}
+
+	plain, _ := signer.MakeJWT(userID, time.Minute)
+	claims, err = signer.ParseJWT(plain)
+	if err != nil {
+		t.Fatalf("ParseJWT() error = %v", err)
+	}
+	if claims.ImpersonatorID != uuid.Nil {
+		t.Errorf("ParseJWT() impersonator = %v, want none", claims.ImpersonatorID)
+	}
+}
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPasswordHashers(t *testing.T) {
+	hashers := map[string]PasswordHasher{
+		"bcrypt":   BcryptHasher{Cost: 4},
+		"argon2id": Argon2idHasher{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+	}
+
+	for name, hasher := range hashers {
+		t.Run(name, func(t *testing.T) {
+			hash, err := hasher.Hash("correct horse")
+			if err != nil {
+				t.Fatalf("Hash() error = %v", err)
+			}
+			if err := CheckPasswordHash("correct horse", hash); err != nil {
+				t.Errorf("CheckPasswordHash() with the right password error = %v", err)
+			}
+			if err := CheckPasswordHash("battery staple", hash); !errors.Is(err, ErrPasswordMismatch) {
+				t.Errorf("CheckPasswordHash() with the wrong password error = %v, want ErrPasswordMismatch", err)
+			}
+		})
+	}
+}
+
+func TestArgon2idHashFormat(t *testing.T) {
+	hash, err := Argon2idHasher{Memory: 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32}.Hash("pw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=2,p=1$") {
+		t.Errorf("Hash() = %q, want PHC-formatted argon2id hash", hash)
+	}
+	if err := CheckPasswordHash("pw", "$argon2id$v=19$garbage"); err == nil {
+		t.Error("CheckPasswordHash() accepted a malformed hash")
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	weakBcrypt, _ := BcryptHasher{Cost: 4}.Hash("pw")
+	strongBcrypt, _ := BcryptHasher{Cost: 6}.Hash("pw")
+	weakArgon, _ := Argon2idHasher{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}.Hash("pw")
+	strongArgon, _ := Argon2idHasher{Memory: 2048, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32}.Hash("pw")
+
+	bcryptPolicy := BcryptHasher{Cost: 6}
+	argonPolicy := Argon2idHasher{Memory: 2048, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+	tests := []struct {
+		name   string
+		hasher PasswordHasher
+		hash   string
+		want   bool
+	}{
+		{"bcrypt at current cost", bcryptPolicy, strongBcrypt, false},
+		{"bcrypt below current cost", bcryptPolicy, weakBcrypt, true},
+		{"argon2id hash under bcrypt policy", bcryptPolicy, strongArgon, true},
+		{"argon2id at current parameters", argonPolicy, strongArgon, false},
+		{"argon2id with weaker parameters", argonPolicy, weakArgon, true},
+		{"bcrypt hash under argon2id policy", argonPolicy, strongBcrypt, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hasher.NeedsRehash(tt.hash); got != tt.want {
+				t.Errorf("NeedsRehash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
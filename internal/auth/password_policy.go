@@ -0,0 +1,75 @@
+package auth
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordsFile string
+
+var commonPasswords = func() map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, line := range strings.Split(commonPasswordsFile, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			set[line] = struct{}{}
+		}
+	}
+	return set
+}()
+
+// PasswordPolicy describes the rules a new password must satisfy.
+type PasswordPolicy struct {
+	MinLength      int
+	RequireUpper   bool
+	RequireLower   bool
+	RequireDigit   bool
+	RequireSymbol  bool
+	DenyCommonList bool
+}
+
+// Validate returns a description of every rule the password breaks, or nil
+// if it satisfies the policy.
+func (p PasswordPolicy) Validate(password string) []string {
+	var failed []string
+
+	if len([]rune(password)) < p.MinLength {
+		failed = append(failed, fmt.Sprintf("must be at least %d characters", p.MinLength))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+	if p.RequireUpper && !hasUpper {
+		failed = append(failed, "must contain an uppercase letter")
+	}
+	if p.RequireLower && !hasLower {
+		failed = append(failed, "must contain a lowercase letter")
+	}
+	if p.RequireDigit && !hasDigit {
+		failed = append(failed, "must contain a digit")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		failed = append(failed, "must contain a symbol")
+	}
+
+	if p.DenyCommonList {
+		if _, ok := commonPasswords[strings.ToLower(password)]; ok {
+			failed = append(failed, "must not be a commonly used password")
+		}
+	}
+
+	return failed
+}
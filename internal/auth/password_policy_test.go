@@ -0,0 +1,35 @@
+package auth
+
+import "testing"
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	policy := PasswordPolicy{
+		MinLength:      10,
+		RequireUpper:   true,
+		RequireLower:   true,
+		RequireDigit:   true,
+		RequireSymbol:  true,
+		DenyCommonList: true,
+	}
+
+	tests := []struct {
+		name       string
+		password   string
+		wantFailed int
+	}{
+		{"strong password", "Correct-Horse-9", 0},
+		{"too short", "Ab1!", 1},
+		{"missing classes", "alllowercaseletters", 3},
+		{"common password", "password123", 3},
+		{"empty", "", 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policy.Validate(tt.password)
+			if len(got) != tt.wantFailed {
+				t.Errorf("Validate(%q) = %v, want %d failed rules", tt.password, got, tt.wantFailed)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package auth
+
+import "strings"
+
+// PersonalAccessTokenPrefix marks bearer tokens that are personal access
+// tokens rather than JWTs, so they can be told apart without a lookup.
+const PersonalAccessTokenPrefix = "chirpy_pat_"
+
+// MakePersonalAccessToken returns a new random, prefixed personal access token.
+func MakePersonalAccessToken() (string, error) {
+	key, err := randomHex(32)
+	if err != nil {
+		return "", err
+	}
+	return PersonalAccessTokenPrefix + key, nil
+}
+
+// IsPersonalAccessToken reports whether a bearer token is a personal access token.
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// RSAKeySet is a TokenSigner using RS256. Tokens are signed with the first
+// loaded key; every loaded key is accepted for verification and published in
+// the JWKS, so a new key can be rolled in ahead of retiring the old one.
+type RSAKeySet struct {
+	signingKID string
+	signingKey *rsa.PrivateKey
+	publicKeys map[string]*rsa.PublicKey
+	kids       []string
+}
+
+// LoadRSAKeySet reads PEM-encoded keys from the given files. The first file
+// must hold a private key and becomes the signing key; later files may hold
+// private or public keys of retired signers.
+func LoadRSAKeySet(paths ...string) (*RSAKeySet, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("no RSA key files given")
+	}
+
+	ks := &RSAKeySet{publicKeys: make(map[string]*rsa.PublicKey)}
+	for i, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		privateKey, publicKey, err := parseRSAPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		kid, err := keyID(publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if i == 0 {
+			if privateKey == nil {
+				return nil, fmt.Errorf("%s: signing key must be a private key", path)
+			}
+			ks.signingKID = kid
+			ks.signingKey = privateKey
+		}
+		if _, ok := ks.publicKeys[kid]; !ok {
+			ks.kids = append(ks.kids, kid)
+		}
+		ks.publicKeys[kid] = publicKey
+	}
+	return ks, nil
+}
+
+func (ks *RSAKeySet) MakeJWT(userID uuid.UUID, expiresIn time.Duration) (string, error) {
+	return ks.sign(newClaims(userID, expiresIn))
+}
+
+func (ks *RSAKeySet) MakeImpersonationJWT(userID, impersonatorID uuid.UUID, expiresIn time.Duration) (string, error) {
+	c := newClaims(userID, expiresIn)
+	c.Actor = &actorClaim{Subject: impersonatorID.String()}
+	return ks.sign(c)
+}
+
+func (ks *RSAKeySet) sign(c claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, c)
+	token.Header["kid"] = ks.signingKID
+	return token.SignedString(ks.signingKey)
+}
+
+func (ks *RSAKeySet) ValidateJWT(tokenString string) (uuid.UUID, error) {
+	c, err := ks.ParseJWT(tokenString)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return c.UserID, nil
+}
+
+func (ks *RSAKeySet) ParseJWT(tokenString string) (TokenClaims, error) {
+	return parseJWT(tokenString, jwt.SigningMethodRS256.Alg(), ks.keyFunc)
+}
+
+func (ks *RSAKeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	publicKey, ok := ks.publicKeys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key ID %q", kid)
+	}
+	return publicKey, nil
+}
+
+// JWK is a single RSA public key in JSON Web Key format (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// JWKS is a JSON Web Key Set document.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public half of every key in the set.
+func (ks *RSAKeySet) JWKS() JWKS {
+	set := JWKS{Keys: make([]JWK, 0, len(ks.kids))}
+	for _, kid := range ks.kids {
+		publicKey := ks.publicKeys[kid]
+		set.Keys = append(set.Keys, JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: jwt.SigningMethodRS256.Alg(),
+			Kid: kid,
+			N:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		})
+	}
+	return set
+}
+
+func parseRSAPEM(data []byte) (*rsa.PrivateKey, *rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		return key, &key.PublicKey, nil
+	case "PRIVATE KEY":
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		key, ok := parsed.(*rsa.PrivateKey)
+		if !ok {
+			return nil, nil, errors.New("not an RSA private key")
+		}
+		return key, &key.PublicKey, nil
+	case "PUBLIC KEY":
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		key, ok := parsed.(*rsa.PublicKey)
+		if !ok {
+			return nil, nil, errors.New("not an RSA public key")
+		}
+		return nil, key, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+}
+
+// keyID derives a stable key ID from the SHA-256 of the DER public key.
+func keyID(publicKey *rsa.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return base64.RawURLEncoding.EncodeToString(sum[:16]), nil
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func writeRSAKey(t *testing.T, dir, name string, publicOnly bool) string {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+	if publicOnly {
+		der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block = &pem.Block{Type: "PUBLIC KEY", Bytes: der}
+	}
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRSAKeySetRotation(t *testing.T) {
+	dir := t.TempDir()
+	oldKey := writeRSAKey(t, dir, "old.pem", false)
+	newKey := writeRSAKey(t, dir, "new.pem", false)
+	userID := uuid.New()
+
+	oldSet, err := LoadRSAKeySet(oldKey)
+	if err != nil {
+		t.Fatalf("LoadRSAKeySet() error = %v", err)
+	}
+	oldToken, err := oldSet.MakeJWT(userID, time.Hour)
+	if err != nil {
+		t.Fatalf("MakeJWT() error = %v", err)
+	}
+
+	rotated, err := LoadRSAKeySet(newKey, oldKey)
+	if err != nil {
+		t.Fatalf("LoadRSAKeySet() error = %v", err)
+	}
+	newToken, err := rotated.MakeJWT(userID, time.Hour)
+	if err != nil {
+		t.Fatalf("MakeJWT() error = %v", err)
+	}
+
+	for name, token := range map[string]string{"old key": oldToken, "new key": newToken} {
+		got, err := rotated.ValidateJWT(token)
+		if err != nil {
+			t.Errorf("%s: ValidateJWT() error = %v", name, err)
+		}
+		if got != userID {
+			t.Errorf("%s: ValidateJWT() = %v, want %v", name, got, userID)
+		}
+	}
+
+	if _, err := oldSet.ValidateJWT(newToken); err == nil {
+		t.Error("ValidateJWT() accepted a token signed by an unknown key")
+	}
+	if n := len(rotated.JWKS().Keys); n != 2 {
+		t.Errorf("JWKS() has %d keys, want 2", n)
+	}
+}
+
+func TestLoadRSAKeySetRequiresPrivateSigningKey(t *testing.T) {
+	dir := t.TempDir()
+	publicKey := writeRSAKey(t, dir, "public.pem", true)
+
+	if _, err := LoadRSAKeySet(publicKey); err == nil {
+		t.Error("LoadRSAKeySet() accepted a public key as the signing key")
+	}
+}
+
+func TestRSAKeySetRejectsHS256(t *testing.T) {
+	dir := t.TempDir()
+	ks, err := LoadRSAKeySet(writeRSAKey(t, dir, "key.pem", false))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, _ := MakeJWT(uuid.New(), "secret", time.Hour)
+	if _, err := ks.ValidateJWT(token); err == nil {
+		t.Error("ValidateJWT() accepted an HS256 token")
+	}
+}
@@ -0,0 +1,110 @@
+// Package avatar turns uploaded profile pictures into square images of a
+// standard size.
+package avatar
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+
+	// Register the formats Process accepts.
+	_ "image/gif"
+	_ "image/jpeg"
+)
+
+// Size is the width and height of processed avatars, in pixels.
+const Size = 256
+
+// MaxSourcePixels bounds the decoded size of an upload, so a small file that
+// declares huge dimensions can't exhaust memory.
+const MaxSourcePixels = 40_000_000
+
+var (
+	ErrUnsupportedFormat = errors.New("avatar: unsupported image format")
+	ErrTooLarge          = errors.New("avatar: image dimensions too large")
+)
+
+// Process decodes a JPEG, PNG or GIF image, crops it to a centered square,
+// scales it to Size×Size and returns it encoded as PNG.
+func Process(data []byte) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+	if cfg.Width*cfg.Height > MaxSourcePixels {
+		return nil, ErrTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+
+	var buf bytes.Buffer
+	err = png.Encode(&buf, Resize(CropSquare(src), Size))
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// CropSquare returns the largest square centered in img.
+func CropSquare(img image.Image) image.Image {
+	b := img.Bounds()
+	side := min(b.Dx(), b.Dy())
+	x := b.Min.X + (b.Dx()-side)/2
+	y := b.Min.Y + (b.Dy()-side)/2
+	return subImage(img, image.Rect(x, y, x+side, y+side))
+}
+
+func subImage(img image.Image, r image.Rectangle) image.Image {
+	if s, ok := img.(interface {
+		SubImage(image.Rectangle) image.Image
+	}); ok {
+		return s.SubImage(r)
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	for y := 0; y < r.Dy(); y++ {
+		for x := 0; x < r.Dx(); x++ {
+			dst.Set(x, y, img.At(r.Min.X+x, r.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// Resize scales img to size×size. Each output pixel averages the source
+// pixels it covers, which keeps downscaled photos smooth; upscaling
+// repeats pixels.
+func Resize(img image.Image, size int) *image.NRGBA {
+	b := img.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		y0 := b.Min.Y + y*b.Dy()/size
+		y1 := max(b.Min.Y+(y+1)*b.Dy()/size, y0+1)
+		for x := 0; x < size; x++ {
+			x0 := b.Min.X + x*b.Dx()/size
+			x1 := max(b.Min.X+(x+1)*b.Dx()/size, x0+1)
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := color.NRGBA64Model.Convert(img.At(sx, sy)).(color.NRGBA64)
+					r += uint64(c.R)
+					g += uint64(c.G)
+					bl += uint64(c.B)
+					a += uint64(c.A)
+					n++
+				}
+			}
+			dst.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(bl / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return dst
+}
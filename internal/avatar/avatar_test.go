@@ -0,0 +1,73 @@
+package avatar
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestProcessCropsAndResizes(t *testing.T) {
+	// A 600x300 image: red on the outer thirds, blue in the middle third
+	// where the square crop lands.
+	src := image.NewRGBA(image.Rect(0, 0, 600, 300))
+	for y := 0; y < 300; y++ {
+		for x := 0; x < 600; x++ {
+			c := color.RGBA{R: 255, A: 255}
+			if x >= 150 && x < 450 {
+				c = color.RGBA{B: 255, A: 255}
+			}
+			src.Set(x, y, c)
+		}
+	}
+
+	out, err := Process(encodePNG(t, src))
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(out))
+	if err != nil {
+		t.Fatalf("decoding result: %v", err)
+	}
+	if got := img.Bounds(); got.Dx() != Size || got.Dy() != Size {
+		t.Fatalf("size = %v, want %dx%d", got, Size, Size)
+	}
+	for _, p := range []image.Point{{0, 0}, {Size - 1, Size - 1}, {Size / 2, Size / 2}} {
+		r, _, b, _ := img.At(p.X, p.Y).RGBA()
+		if r != 0 || b != 0xffff {
+			t.Errorf("pixel %v = r%d b%d, want pure blue", p, r, b)
+		}
+	}
+}
+
+func TestProcessUpscalesSmallImages(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	out, err := Process(encodePNG(t, src))
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	cfg, err := png.DecodeConfig(bytes.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Width != Size || cfg.Height != Size {
+		t.Errorf("size = %dx%d, want %dx%d", cfg.Width, cfg.Height, Size, Size)
+	}
+}
+
+func TestProcessRejectsNonImages(t *testing.T) {
+	_, err := Process([]byte("not an image"))
+	if err != ErrUnsupportedFormat {
+		t.Errorf("err = %v, want ErrUnsupportedFormat", err)
+	}
+}
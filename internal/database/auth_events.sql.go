@@ -0,0 +1,130 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: auth_events.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const createAuthEvent = `-- name: CreateAuthEvent :exec
+INSERT INTO auth_events (id, created_at, user_id, event_type, ip_address, user_agent, detail)
+
+VALUES (gen_random_uuid(), NOW(), $1, $2, $3, $4, $5)
+`
+
+type CreateAuthEventParams struct {
+	UserID    uuid.NullUUID
+	EventType string
+	IpAddress string
+	UserAgent string
+	Detail    string
+}
+
+func (q *Queries) CreateAuthEvent(ctx context.Context, arg CreateAuthEventParams) error {
+	_, err := q.db.ExecContext(ctx, createAuthEvent,
+		arg.UserID,
+		arg.EventType,
+		arg.IpAddress,
+		arg.UserAgent,
+		arg.Detail,
+	)
+	return err
+}
+
+const listAuthEvents = `-- name: ListAuthEvents :many
+SELECT id, created_at, user_id, event_type, ip_address, user_agent, detail FROM auth_events
+WHERE ($1::uuid IS NULL OR user_id = $1)
+AND ($2::text IS NULL OR event_type = $2)
+AND ($3::timestamp IS NULL OR created_at >= $3)
+AND ($4::timestamp IS NULL OR created_at < $4)
+ORDER BY created_at DESC
+LIMIT $5
+`
+
+type ListAuthEventsParams struct {
+	UserID     uuid.NullUUID
+	EventType  sql.NullString
+	Since      sql.NullTime
+	Until      sql.NullTime
+	MaxResults int32
+}
+
+func (q *Queries) ListAuthEvents(ctx context.Context, arg ListAuthEventsParams) ([]AuthEvent, error) {
+	rows, err := q.db.QueryContext(ctx, listAuthEvents,
+		arg.UserID,
+		arg.EventType,
+		arg.Since,
+		arg.Until,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuthEvent
+	for rows.Next() {
+		var i AuthEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UserID,
+			&i.EventType,
+			&i.IpAddress,
+			&i.UserAgent,
+			&i.Detail,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthEventsForUser = `-- name: ListAuthEventsForUser :many
+SELECT id, created_at, user_id, event_type, ip_address, user_agent, detail FROM auth_events
+WHERE user_id = $1
+ORDER BY created_at ASC
+`
+
+func (q *Queries) ListAuthEventsForUser(ctx context.Context, userID uuid.NullUUID) ([]AuthEvent, error) {
+	rows, err := q.db.QueryContext(ctx, listAuthEventsForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuthEvent
+	for rows.Next() {
+		var i AuthEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UserID,
+			&i.EventType,
+			&i.IpAddress,
+			&i.UserAgent,
+			&i.Detail,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
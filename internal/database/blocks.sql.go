@@ -0,0 +1,103 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: blocks.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const block = `-- name: Block :exec
+INSERT INTO blocks (blocker_id, blocked_id, created_at)
+
+VALUES ($1, $2, NOW())
+
+ON CONFLICT (blocker_id, blocked_id) DO NOTHING
+`
+
+type BlockParams struct {
+	BlockerID uuid.UUID
+	BlockedID uuid.UUID
+}
+
+func (q *Queries) Block(ctx context.Context, arg BlockParams) error {
+	_, err := q.db.ExecContext(ctx, block, arg.BlockerID, arg.BlockedID)
+	return err
+}
+
+const deleteBlocksByUser = `-- name: DeleteBlocksByUser :exec
+DELETE FROM blocks
+WHERE blocker_id = $1 OR blocked_id = $1
+`
+
+func (q *Queries) DeleteBlocksByUser(ctx context.Context, blockerID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteBlocksByUser, blockerID)
+	return err
+}
+
+const isBlocked = `-- name: IsBlocked :one
+SELECT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocker_id = $1 AND blocked_id = $2
+)
+`
+
+type IsBlockedParams struct {
+	BlockerID uuid.UUID
+	BlockedID uuid.UUID
+}
+
+func (q *Queries) IsBlocked(ctx context.Context, arg IsBlockedParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isBlocked, arg.BlockerID, arg.BlockedID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listBlocksByUser = `-- name: ListBlocksByUser :many
+SELECT blocker_id, blocked_id, created_at FROM blocks
+WHERE blocker_id = $1
+ORDER BY created_at ASC
+`
+
+func (q *Queries) ListBlocksByUser(ctx context.Context, blockerID uuid.UUID) ([]Block, error) {
+	rows, err := q.db.QueryContext(ctx, listBlocksByUser, blockerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Block
+	for rows.Next() {
+		var i Block
+		if err := rows.Scan(&i.BlockerID, &i.BlockedID, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const unblock = `-- name: Unblock :exec
+DELETE FROM blocks
+WHERE blocker_id = $1 AND blocked_id = $2
+`
+
+type UnblockParams struct {
+	BlockerID uuid.UUID
+	BlockedID uuid.UUID
+}
+
+func (q *Queries) Unblock(ctx context.Context, arg UnblockParams) error {
+	_, err := q.db.ExecContext(ctx, unblock, arg.BlockerID, arg.BlockedID)
+	return err
+}
@@ -0,0 +1,170 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: bookmarks.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const addBookmark = `-- name: AddBookmark :exec
+INSERT INTO bookmarks (user_id, chirp_id, created_at)
+
+VALUES ($1, $2, NOW())
+
+ON CONFLICT DO NOTHING
+`
+
+type AddBookmarkParams struct {
+	UserID  uuid.UUID
+	ChirpID uuid.UUID
+}
+
+func (q *Queries) AddBookmark(ctx context.Context, arg AddBookmarkParams) error {
+	_, err := q.db.ExecContext(ctx, addBookmark, arg.UserID, arg.ChirpID)
+	return err
+}
+
+const countBookmarkedChirps = `-- name: CountBookmarkedChirps :one
+SELECT COUNT(*) FROM bookmarks
+JOIN chirps ON chirps.id = bookmarks.chirp_id
+WHERE bookmarks.user_id = $1
+AND chirps.deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+`
+
+func (q *Queries) CountBookmarkedChirps(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countBookmarkedChirps, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteBookmark = `-- name: DeleteBookmark :exec
+DELETE FROM bookmarks
+WHERE user_id = $1 AND chirp_id = $2
+`
+
+type DeleteBookmarkParams struct {
+	UserID  uuid.UUID
+	ChirpID uuid.UUID
+}
+
+func (q *Queries) DeleteBookmark(ctx context.Context, arg DeleteBookmarkParams) error {
+	_, err := q.db.ExecContext(ctx, deleteBookmark, arg.UserID, arg.ChirpID)
+	return err
+}
+
+const deleteBookmarksByUser = `-- name: DeleteBookmarksByUser :exec
+DELETE FROM bookmarks
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteBookmarksByUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteBookmarksByUser, userID)
+	return err
+}
+
+const listBookmarkedChirps = `-- name: ListBookmarkedChirps :many
+SELECT chirps.id, chirps.created_at, chirps.updated_at, chirps.body, chirps.user_id, chirps.search_vector, chirps.deleted_at, chirps.hidden_at, chirps.view_count, chirps.in_reply_to_id, chirps.reply_policy, chirps.language, bookmarks.created_at AS bookmarked_at
+FROM bookmarks
+JOIN chirps ON chirps.id = bookmarks.chirp_id
+WHERE bookmarks.user_id = $1
+AND chirps.deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND (
+    $2::timestamp IS NULL
+    OR (bookmarks.created_at, bookmarks.chirp_id) < ($2::timestamp, $3::uuid)
+)
+ORDER BY bookmarks.created_at DESC, bookmarks.chirp_id DESC
+LIMIT $4
+`
+
+type ListBookmarkedChirpsParams struct {
+	UserID         uuid.UUID
+	AfterCreatedAt sql.NullTime
+	AfterID        uuid.NullUUID
+	MaxResults     int32
+}
+
+type ListBookmarkedChirpsRow struct {
+	Chirp        Chirp
+	BookmarkedAt time.Time
+}
+
+func (q *Queries) ListBookmarkedChirps(ctx context.Context, arg ListBookmarkedChirpsParams) ([]ListBookmarkedChirpsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listBookmarkedChirps,
+		arg.UserID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBookmarkedChirpsRow
+	for rows.Next() {
+		var i ListBookmarkedChirpsRow
+		if err := rows.Scan(
+			&i.Chirp.ID,
+			&i.Chirp.CreatedAt,
+			&i.Chirp.UpdatedAt,
+			&i.Chirp.Body,
+			&i.Chirp.UserID,
+			&i.Chirp.SearchVector,
+			&i.Chirp.DeletedAt,
+			&i.Chirp.HiddenAt,
+			&i.Chirp.ViewCount,
+			&i.Chirp.InReplyToID,
+			&i.Chirp.ReplyPolicy,
+			&i.Chirp.Language,
+			&i.BookmarkedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBookmarksByUser = `-- name: ListBookmarksByUser :many
+SELECT user_id, chirp_id, created_at FROM bookmarks
+WHERE user_id = $1
+ORDER BY created_at ASC
+`
+
+func (q *Queries) ListBookmarksByUser(ctx context.Context, userID uuid.UUID) ([]Bookmark, error) {
+	rows, err := q.db.QueryContext(ctx, listBookmarksByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Bookmark
+	for rows.Next() {
+		var i Bookmark
+		if err := rows.Scan(&i.UserID, &i.ChirpID, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
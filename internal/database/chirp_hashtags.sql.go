@@ -0,0 +1,206 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: chirp_hashtags.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const addChirpHashtag = `-- name: AddChirpHashtag :exec
+INSERT INTO chirp_hashtags (chirp_id, tag, created_at)
+
+VALUES ($1, $2, NOW())
+
+ON CONFLICT DO NOTHING
+`
+
+type AddChirpHashtagParams struct {
+	ChirpID uuid.UUID
+	Tag     string
+}
+
+func (q *Queries) AddChirpHashtag(ctx context.Context, arg AddChirpHashtagParams) error {
+	_, err := q.db.ExecContext(ctx, addChirpHashtag, arg.ChirpID, arg.Tag)
+	return err
+}
+
+const countChirpsByHashtag = `-- name: CountChirpsByHashtag :one
+SELECT COUNT(*) FROM chirps
+JOIN chirp_hashtags ON chirp_hashtags.chirp_id = chirps.id
+WHERE chirp_hashtags.tag = $1
+AND chirps.deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = $2::uuid AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = $2::uuid AND mutes.muted_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id IS DISTINCT FROM $2::uuid
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = $2::uuid AND follows.followee_id = users.id
+    )
+)
+`
+
+type CountChirpsByHashtagParams struct {
+	Tag      string
+	ViewerID uuid.NullUUID
+}
+
+func (q *Queries) CountChirpsByHashtag(ctx context.Context, arg CountChirpsByHashtagParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countChirpsByHashtag, arg.Tag, arg.ViewerID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteChirpHashtags = `-- name: DeleteChirpHashtags :exec
+DELETE FROM chirp_hashtags
+WHERE chirp_id = $1
+`
+
+func (q *Queries) DeleteChirpHashtags(ctx context.Context, chirpID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteChirpHashtags, chirpID)
+	return err
+}
+
+const listChirpsByHashtag = `-- name: ListChirpsByHashtag :many
+SELECT chirps.id, chirps.created_at, chirps.updated_at, chirps.body, chirps.user_id, chirps.search_vector, chirps.deleted_at, chirps.hidden_at, chirps.view_count, chirps.in_reply_to_id, chirps.reply_policy, chirps.language FROM chirps
+JOIN chirp_hashtags ON chirp_hashtags.chirp_id = chirps.id
+WHERE chirp_hashtags.tag = $1
+AND chirps.deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = $2::uuid AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = $2::uuid AND mutes.muted_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id IS DISTINCT FROM $2::uuid
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = $2::uuid AND follows.followee_id = users.id
+    )
+)
+AND (
+    $3::timestamp IS NULL
+    OR (chirps.created_at, chirps.id) < ($3::timestamp, $4::uuid)
+)
+ORDER BY chirps.created_at DESC, chirps.id DESC
+LIMIT $5
+`
+
+type ListChirpsByHashtagParams struct {
+	Tag            string
+	ViewerID       uuid.NullUUID
+	AfterCreatedAt sql.NullTime
+	AfterID        uuid.NullUUID
+	MaxResults     int32
+}
+
+func (q *Queries) ListChirpsByHashtag(ctx context.Context, arg ListChirpsByHashtagParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listChirpsByHashtag,
+		arg.Tag,
+		arg.ViewerID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.HiddenAt,
+			&i.ViewCount,
+			&i.InReplyToID,
+			&i.ReplyPolicy,
+			&i.Language,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrendingHashtags = `-- name: ListTrendingHashtags :many
+SELECT chirp_hashtags.tag, COUNT(*) AS chirp_count FROM chirp_hashtags
+JOIN chirps ON chirps.id = chirp_hashtags.chirp_id
+WHERE chirp_hashtags.created_at > $1
+AND chirps.deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+GROUP BY chirp_hashtags.tag
+ORDER BY chirp_count DESC, chirp_hashtags.tag ASC
+LIMIT $2
+`
+
+type ListTrendingHashtagsParams struct {
+	CreatedAt time.Time
+	Limit     int32
+}
+
+type ListTrendingHashtagsRow struct {
+	Tag        string
+	ChirpCount int64
+}
+
+func (q *Queries) ListTrendingHashtags(ctx context.Context, arg ListTrendingHashtagsParams) ([]ListTrendingHashtagsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTrendingHashtags, arg.CreatedAt, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTrendingHashtagsRow
+	for rows.Next() {
+		var i ListTrendingHashtagsRow
+		if err := rows.Scan(&i.Tag, &i.ChirpCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,205 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: chirp_reports.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const createChirpReport = `-- name: CreateChirpReport :one
+INSERT INTO chirp_reports (id, created_at, updated_at, chirp_id, reporter_id, reason, details)
+
+VALUES (gen_random_uuid(), NOW(), NOW(), $1, $2, $3, $4)
+
+ON CONFLICT (chirp_id, reporter_id) DO NOTHING
+
+RETURNING id, created_at, updated_at, chirp_id, reporter_id, reason, details, status, action, resolved_by, resolved_at
+`
+
+type CreateChirpReportParams struct {
+	ChirpID    uuid.UUID
+	ReporterID uuid.UUID
+	Reason     string
+	Details    string
+}
+
+func (q *Queries) CreateChirpReport(ctx context.Context, arg CreateChirpReportParams) (ChirpReport, error) {
+	row := q.db.QueryRowContext(ctx, createChirpReport,
+		arg.ChirpID,
+		arg.ReporterID,
+		arg.Reason,
+		arg.Details,
+	)
+	var i ChirpReport
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ChirpID,
+		&i.ReporterID,
+		&i.Reason,
+		&i.Details,
+		&i.Status,
+		&i.Action,
+		&i.ResolvedBy,
+		&i.ResolvedAt,
+	)
+	return i, err
+}
+
+const getChirpReport = `-- name: GetChirpReport :one
+SELECT id, created_at, updated_at, chirp_id, reporter_id, reason, details, status, action, resolved_by, resolved_at FROM chirp_reports
+WHERE id = $1
+`
+
+func (q *Queries) GetChirpReport(ctx context.Context, id uuid.UUID) (ChirpReport, error) {
+	row := q.db.QueryRowContext(ctx, getChirpReport, id)
+	var i ChirpReport
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ChirpID,
+		&i.ReporterID,
+		&i.Reason,
+		&i.Details,
+		&i.Status,
+		&i.Action,
+		&i.ResolvedBy,
+		&i.ResolvedAt,
+	)
+	return i, err
+}
+
+const listChirpReports = `-- name: ListChirpReports :many
+SELECT id, created_at, updated_at, chirp_id, reporter_id, reason, details, status, action, resolved_by, resolved_at FROM chirp_reports
+WHERE $1::text IS NULL OR status = $1::text
+ORDER BY created_at ASC, id ASC
+LIMIT $2
+`
+
+type ListChirpReportsParams struct {
+	Status     sql.NullString
+	MaxResults int32
+}
+
+func (q *Queries) ListChirpReports(ctx context.Context, arg ListChirpReportsParams) ([]ChirpReport, error) {
+	rows, err := q.db.QueryContext(ctx, listChirpReports, arg.Status, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ChirpReport
+	for rows.Next() {
+		var i ChirpReport
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ChirpID,
+			&i.ReporterID,
+			&i.Reason,
+			&i.Details,
+			&i.Status,
+			&i.Action,
+			&i.ResolvedBy,
+			&i.ResolvedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChirpReportsByReporter = `-- name: ListChirpReportsByReporter :many
+SELECT id, created_at, updated_at, chirp_id, reporter_id, reason, details, status, action, resolved_by, resolved_at FROM chirp_reports
+WHERE reporter_id = $1
+ORDER BY created_at ASC, id ASC
+`
+
+func (q *Queries) ListChirpReportsByReporter(ctx context.Context, reporterID uuid.UUID) ([]ChirpReport, error) {
+	rows, err := q.db.QueryContext(ctx, listChirpReportsByReporter, reporterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ChirpReport
+	for rows.Next() {
+		var i ChirpReport
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ChirpID,
+			&i.ReporterID,
+			&i.Reason,
+			&i.Details,
+			&i.Status,
+			&i.Action,
+			&i.ResolvedBy,
+			&i.ResolvedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const resolveChirpReport = `-- name: ResolveChirpReport :one
+UPDATE chirp_reports
+SET status = $2, action = $3, resolved_by = $4, resolved_at = NOW(), updated_at = NOW()
+WHERE id = $1
+AND status = 'open'
+RETURNING id, created_at, updated_at, chirp_id, reporter_id, reason, details, status, action, resolved_by, resolved_at
+`
+
+type ResolveChirpReportParams struct {
+	ID         uuid.UUID
+	Status     string
+	Action     sql.NullString
+	ResolvedBy uuid.NullUUID
+}
+
+func (q *Queries) ResolveChirpReport(ctx context.Context, arg ResolveChirpReportParams) (ChirpReport, error) {
+	row := q.db.QueryRowContext(ctx, resolveChirpReport,
+		arg.ID,
+		arg.Status,
+		arg.Action,
+		arg.ResolvedBy,
+	)
+	var i ChirpReport
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ChirpID,
+		&i.ReporterID,
+		&i.Reason,
+		&i.Details,
+		&i.Status,
+		&i.Action,
+		&i.ResolvedBy,
+		&i.ResolvedAt,
+	)
+	return i, err
+}
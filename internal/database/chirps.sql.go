@@ -0,0 +1,904 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: chirps.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const addChirpViews = `-- name: AddChirpViews :exec
+UPDATE chirps
+SET view_count = chirps.view_count + views.n
+FROM (
+    SELECT UNNEST($1::uuid[]) AS id, UNNEST($2::bigint[]) AS n
+) AS views
+WHERE chirps.id = views.id
+`
+
+type AddChirpViewsParams struct {
+	ChirpIds []uuid.UUID
+	Counts   []int64
+}
+
+func (q *Queries) AddChirpViews(ctx context.Context, arg AddChirpViewsParams) error {
+	_, err := q.db.ExecContext(ctx, addChirpViews, pq.Array(arg.ChirpIds), pq.Array(arg.Counts))
+	return err
+}
+
+const countChirps = `-- name: CountChirps :one
+SELECT COUNT(*) FROM chirps
+WHERE deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND ($1::text IS NULL OR language = $1)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = $2::uuid AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = $2::uuid AND mutes.muted_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id IS DISTINCT FROM $2::uuid
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = $2::uuid AND follows.followee_id = users.id
+    )
+)
+`
+
+type CountChirpsParams struct {
+	Language sql.NullString
+	ViewerID uuid.NullUUID
+}
+
+func (q *Queries) CountChirps(ctx context.Context, arg CountChirpsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countChirps, arg.Language, arg.ViewerID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countChirpsByAuthor = `-- name: CountChirpsByAuthor :one
+SELECT COUNT(*) FROM chirps
+WHERE user_id = $1
+AND deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND ($2::text IS NULL OR language = $2)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = $3::uuid AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id IS DISTINCT FROM $3::uuid
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = $3::uuid AND follows.followee_id = users.id
+    )
+)
+`
+
+type CountChirpsByAuthorParams struct {
+	UserID   uuid.UUID
+	Language sql.NullString
+	ViewerID uuid.NullUUID
+}
+
+func (q *Queries) CountChirpsByAuthor(ctx context.Context, arg CountChirpsByAuthorParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countChirpsByAuthor, arg.UserID, arg.Language, arg.ViewerID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countFeed = `-- name: CountFeed :one
+SELECT COUNT(*) FROM chirps
+WHERE (
+    chirps.user_id = $1
+    OR chirps.user_id IN (SELECT followee_id FROM follows WHERE follower_id = $1)
+)
+AND chirps.deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = $1 AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = $1 AND mutes.muted_id = chirps.user_id
+)
+`
+
+func (q *Queries) CountFeed(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFeed, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchChirps = `-- name: CountSearchChirps :one
+SELECT COUNT(*) FROM chirps
+WHERE search_vector @@ websearch_to_tsquery('english', $1)
+AND deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND ($2::text IS NULL OR language = $2)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = $3::uuid AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = $3::uuid AND mutes.muted_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id IS DISTINCT FROM $3::uuid
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = $3::uuid AND follows.followee_id = users.id
+    )
+)
+`
+
+type CountSearchChirpsParams struct {
+	Query    string
+	Language sql.NullString
+	ViewerID uuid.NullUUID
+}
+
+func (q *Queries) CountSearchChirps(ctx context.Context, arg CountSearchChirpsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSearchChirps, arg.Query, arg.Language, arg.ViewerID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createChirp = `-- name: CreateChirp :one
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, in_reply_to_id, reply_policy, language)
+
+VALUES (gen_random_uuid(), NOW(), NOW(), $1, $2, $3, $4, $5)
+
+RETURNING id, created_at, updated_at, body, user_id, search_vector, deleted_at, hidden_at, view_count, in_reply_to_id, reply_policy, language
+`
+
+type CreateChirpParams struct {
+	Body        string
+	UserID      uuid.UUID
+	InReplyToID uuid.NullUUID
+	ReplyPolicy string
+	Language    string
+}
+
+func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, createChirp,
+		arg.Body,
+		arg.UserID,
+		arg.InReplyToID,
+		arg.ReplyPolicy,
+		arg.Language,
+	)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.SearchVector,
+		&i.DeletedAt,
+		&i.HiddenAt,
+		&i.ViewCount,
+		&i.InReplyToID,
+		&i.ReplyPolicy,
+		&i.Language,
+	)
+	return i, err
+}
+
+const getChirp = `-- name: GetChirp :one
+SELECT id, created_at, updated_at, body, user_id, search_vector, deleted_at, hidden_at, view_count, in_reply_to_id, reply_policy, language FROM chirps
+WHERE chirps.id = $1 AND chirps.deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+`
+
+func (q *Queries) GetChirp(ctx context.Context, id uuid.UUID) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, getChirp, id)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.SearchVector,
+		&i.DeletedAt,
+		&i.HiddenAt,
+		&i.ViewCount,
+		&i.InReplyToID,
+		&i.ReplyPolicy,
+		&i.Language,
+	)
+	return i, err
+}
+
+const getChirpIncludingDeleted = `-- name: GetChirpIncludingDeleted :one
+SELECT id, created_at, updated_at, body, user_id, search_vector, deleted_at, hidden_at, view_count, in_reply_to_id, reply_policy, language FROM chirps
+WHERE id = $1
+`
+
+func (q *Queries) GetChirpIncludingDeleted(ctx context.Context, id uuid.UUID) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, getChirpIncludingDeleted, id)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.SearchVector,
+		&i.DeletedAt,
+		&i.HiddenAt,
+		&i.ViewCount,
+		&i.InReplyToID,
+		&i.ReplyPolicy,
+		&i.Language,
+	)
+	return i, err
+}
+
+const hideChirp = `-- name: HideChirp :exec
+UPDATE chirps
+SET hidden_at = NOW(), deleted_at = COALESCE(deleted_at, NOW())
+WHERE id = $1
+`
+
+func (q *Queries) HideChirp(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, hideChirp, id)
+	return err
+}
+
+const listAllChirpsByUser = `-- name: ListAllChirpsByUser :many
+SELECT id, created_at, updated_at, body, user_id, search_vector, deleted_at, hidden_at, view_count, in_reply_to_id, reply_policy, language FROM chirps
+WHERE user_id = $1
+ORDER BY created_at ASC, id ASC
+`
+
+func (q *Queries) ListAllChirpsByUser(ctx context.Context, userID uuid.UUID) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listAllChirpsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.HiddenAt,
+			&i.ViewCount,
+			&i.InReplyToID,
+			&i.ReplyPolicy,
+			&i.Language,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChirps = `-- name: ListChirps :many
+SELECT id, created_at, updated_at, body, user_id, search_vector, deleted_at, hidden_at, view_count, in_reply_to_id, reply_policy, language FROM chirps
+WHERE deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND ($1::text IS NULL OR language = $1)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = $2::uuid AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = $2::uuid AND mutes.muted_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id IS DISTINCT FROM $2::uuid
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = $2::uuid AND follows.followee_id = users.id
+    )
+)
+AND (
+    $3::timestamp IS NULL
+    OR (created_at, id) > ($3::timestamp, $4::uuid)
+)
+ORDER BY created_at ASC, id ASC
+LIMIT $5
+`
+
+type ListChirpsParams struct {
+	Language       sql.NullString
+	ViewerID       uuid.NullUUID
+	AfterCreatedAt sql.NullTime
+	AfterID        uuid.NullUUID
+	MaxResults     sql.NullInt32
+}
+
+func (q *Queries) ListChirps(ctx context.Context, arg ListChirpsParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listChirps,
+		arg.Language,
+		arg.ViewerID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.HiddenAt,
+			&i.ViewCount,
+			&i.InReplyToID,
+			&i.ReplyPolicy,
+			&i.Language,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChirpsByAuthor = `-- name: ListChirpsByAuthor :many
+SELECT id, created_at, updated_at, body, user_id, search_vector, deleted_at, hidden_at, view_count, in_reply_to_id, reply_policy, language FROM chirps
+WHERE user_id = $1
+AND deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND ($2::text IS NULL OR language = $2)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = $3::uuid AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id IS DISTINCT FROM $3::uuid
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = $3::uuid AND follows.followee_id = users.id
+    )
+)
+AND (
+    $4::timestamp IS NULL
+    OR (created_at, id) > ($4::timestamp, $5::uuid)
+)
+ORDER BY created_at ASC, id ASC
+LIMIT $6
+`
+
+type ListChirpsByAuthorParams struct {
+	UserID         uuid.UUID
+	Language       sql.NullString
+	ViewerID       uuid.NullUUID
+	AfterCreatedAt sql.NullTime
+	AfterID        uuid.NullUUID
+	MaxResults     sql.NullInt32
+}
+
+func (q *Queries) ListChirpsByAuthor(ctx context.Context, arg ListChirpsByAuthorParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listChirpsByAuthor,
+		arg.UserID,
+		arg.Language,
+		arg.ViewerID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.HiddenAt,
+			&i.ViewCount,
+			&i.InReplyToID,
+			&i.ReplyPolicy,
+			&i.Language,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChirpsByAuthorDesc = `-- name: ListChirpsByAuthorDesc :many
+SELECT id, created_at, updated_at, body, user_id, search_vector, deleted_at, hidden_at, view_count, in_reply_to_id, reply_policy, language FROM chirps
+WHERE user_id = $1
+AND deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND ($2::text IS NULL OR language = $2)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = $3::uuid AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id IS DISTINCT FROM $3::uuid
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = $3::uuid AND follows.followee_id = users.id
+    )
+)
+AND (
+    $4::timestamp IS NULL
+    OR (created_at, id) < ($4::timestamp, $5::uuid)
+)
+ORDER BY created_at DESC, id DESC
+LIMIT $6
+`
+
+type ListChirpsByAuthorDescParams struct {
+	UserID         uuid.UUID
+	Language       sql.NullString
+	ViewerID       uuid.NullUUID
+	AfterCreatedAt sql.NullTime
+	AfterID        uuid.NullUUID
+	MaxResults     sql.NullInt32
+}
+
+func (q *Queries) ListChirpsByAuthorDesc(ctx context.Context, arg ListChirpsByAuthorDescParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listChirpsByAuthorDesc,
+		arg.UserID,
+		arg.Language,
+		arg.ViewerID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.HiddenAt,
+			&i.ViewCount,
+			&i.InReplyToID,
+			&i.ReplyPolicy,
+			&i.Language,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChirpsDesc = `-- name: ListChirpsDesc :many
+SELECT id, created_at, updated_at, body, user_id, search_vector, deleted_at, hidden_at, view_count, in_reply_to_id, reply_policy, language FROM chirps
+WHERE deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND ($1::text IS NULL OR language = $1)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = $2::uuid AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = $2::uuid AND mutes.muted_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id IS DISTINCT FROM $2::uuid
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = $2::uuid AND follows.followee_id = users.id
+    )
+)
+AND (
+    $3::timestamp IS NULL
+    OR (created_at, id) < ($3::timestamp, $4::uuid)
+)
+ORDER BY created_at DESC, id DESC
+LIMIT $5
+`
+
+type ListChirpsDescParams struct {
+	Language       sql.NullString
+	ViewerID       uuid.NullUUID
+	AfterCreatedAt sql.NullTime
+	AfterID        uuid.NullUUID
+	MaxResults     sql.NullInt32
+}
+
+func (q *Queries) ListChirpsDesc(ctx context.Context, arg ListChirpsDescParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listChirpsDesc,
+		arg.Language,
+		arg.ViewerID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.HiddenAt,
+			&i.ViewCount,
+			&i.InReplyToID,
+			&i.ReplyPolicy,
+			&i.Language,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDeletedChirps = `-- name: ListDeletedChirps :many
+SELECT id, created_at, updated_at, body, user_id, search_vector, deleted_at, hidden_at, view_count, in_reply_to_id, reply_policy, language FROM chirps
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, id DESC
+LIMIT $1
+`
+
+func (q *Queries) ListDeletedChirps(ctx context.Context, limit int32) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listDeletedChirps, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.HiddenAt,
+			&i.ViewCount,
+			&i.InReplyToID,
+			&i.ReplyPolicy,
+			&i.Language,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFeed = `-- name: ListFeed :many
+SELECT id, created_at, updated_at, body, user_id, search_vector, deleted_at, hidden_at, view_count, in_reply_to_id, reply_policy, language FROM chirps
+WHERE (
+    chirps.user_id = $1
+    OR chirps.user_id IN (SELECT followee_id FROM follows WHERE follower_id = $1)
+)
+AND chirps.deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = $1 AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = $1 AND mutes.muted_id = chirps.user_id
+)
+AND (
+    $2::timestamp IS NULL
+    OR (chirps.created_at, chirps.id) < ($2::timestamp, $3::uuid)
+)
+ORDER BY chirps.created_at DESC, chirps.id DESC
+LIMIT $4
+`
+
+type ListFeedParams struct {
+	UserID         uuid.UUID
+	AfterCreatedAt sql.NullTime
+	AfterID        uuid.NullUUID
+	MaxResults     int32
+}
+
+func (q *Queries) ListFeed(ctx context.Context, arg ListFeedParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listFeed,
+		arg.UserID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.HiddenAt,
+			&i.ViewCount,
+			&i.InReplyToID,
+			&i.ReplyPolicy,
+			&i.Language,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreChirp = `-- name: RestoreChirp :exec
+UPDATE chirps
+SET deleted_at = NULL
+WHERE id = $1
+`
+
+func (q *Queries) RestoreChirp(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, restoreChirp, id)
+	return err
+}
+
+const searchChirps = `-- name: SearchChirps :many
+SELECT chirps.id, chirps.created_at, chirps.updated_at, chirps.body, chirps.user_id, chirps.search_vector, chirps.deleted_at, chirps.hidden_at, chirps.view_count, chirps.in_reply_to_id, chirps.reply_policy, chirps.language, ts_rank(search_vector, websearch_to_tsquery('english', $1))::real AS rank
+FROM chirps
+WHERE search_vector @@ websearch_to_tsquery('english', $1)
+AND deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND ($2::text IS NULL OR language = $2)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = $3::uuid AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = $3::uuid AND mutes.muted_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id IS DISTINCT FROM $3::uuid
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = $3::uuid AND follows.followee_id = users.id
+    )
+)
+ORDER BY rank DESC, created_at DESC, id DESC
+LIMIT $5
+OFFSET $4
+`
+
+type SearchChirpsParams struct {
+	Query      string
+	Language   sql.NullString
+	ViewerID   uuid.NullUUID
+	Skip       int32
+	MaxResults int32
+}
+
+type SearchChirpsRow struct {
+	Chirp Chirp
+	Rank  float32
+}
+
+func (q *Queries) SearchChirps(ctx context.Context, arg SearchChirpsParams) ([]SearchChirpsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchChirps,
+		arg.Query,
+		arg.Language,
+		arg.ViewerID,
+		arg.Skip,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchChirpsRow
+	for rows.Next() {
+		var i SearchChirpsRow
+		if err := rows.Scan(
+			&i.Chirp.ID,
+			&i.Chirp.CreatedAt,
+			&i.Chirp.UpdatedAt,
+			&i.Chirp.Body,
+			&i.Chirp.UserID,
+			&i.Chirp.SearchVector,
+			&i.Chirp.DeletedAt,
+			&i.Chirp.HiddenAt,
+			&i.Chirp.ViewCount,
+			&i.Chirp.InReplyToID,
+			&i.Chirp.ReplyPolicy,
+			&i.Chirp.Language,
+			&i.Rank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const softDeleteChirp = `-- name: SoftDeleteChirp :exec
+UPDATE chirps
+SET deleted_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) SoftDeleteChirp(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, softDeleteChirp, id)
+	return err
+}
+
+const softDeleteChirpsByUser = `-- name: SoftDeleteChirpsByUser :exec
+UPDATE chirps
+SET deleted_at = NOW()
+WHERE user_id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) SoftDeleteChirpsByUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, softDeleteChirpsByUser, userID)
+	return err
+}
+
+const updateChirpBody = `-- name: UpdateChirpBody :one
+UPDATE chirps
+SET body = $2, language = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING id, created_at, updated_at, body, user_id, search_vector, deleted_at, hidden_at, view_count, in_reply_to_id, reply_policy, language
+`
+
+type UpdateChirpBodyParams struct {
+	ID       uuid.UUID
+	Body     string
+	Language string
+}
+
+func (q *Queries) UpdateChirpBody(ctx context.Context, arg UpdateChirpBodyParams) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, updateChirpBody, arg.ID, arg.Body, arg.Language)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.SearchVector,
+		&i.DeletedAt,
+		&i.HiddenAt,
+		&i.ViewCount,
+		&i.InReplyToID,
+		&i.ReplyPolicy,
+		&i.Language,
+	)
+	return i, err
+}
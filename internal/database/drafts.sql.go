@@ -0,0 +1,174 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: drafts.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const createDraft = `-- name: CreateDraft :one
+INSERT INTO drafts (id, created_at, updated_at, body, user_id)
+
+VALUES (gen_random_uuid(), NOW(), NOW(), $1, $2)
+
+RETURNING id, created_at, updated_at, body, user_id
+`
+
+type CreateDraftParams struct {
+	Body   string
+	UserID uuid.UUID
+}
+
+func (q *Queries) CreateDraft(ctx context.Context, arg CreateDraftParams) (Draft, error) {
+	row := q.db.QueryRowContext(ctx, createDraft, arg.Body, arg.UserID)
+	var i Draft
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+	)
+	return i, err
+}
+
+const deleteDraft = `-- name: DeleteDraft :execrows
+DELETE FROM drafts
+WHERE id = $1 AND user_id = $2
+`
+
+type DeleteDraftParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) DeleteDraft(ctx context.Context, arg DeleteDraftParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteDraft, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteDraftsByUser = `-- name: DeleteDraftsByUser :exec
+DELETE FROM drafts
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteDraftsByUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteDraftsByUser, userID)
+	return err
+}
+
+const getDraft = `-- name: GetDraft :one
+SELECT id, created_at, updated_at, body, user_id FROM drafts
+WHERE id = $1 AND user_id = $2
+`
+
+type GetDraftParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) GetDraft(ctx context.Context, arg GetDraftParams) (Draft, error) {
+	row := q.db.QueryRowContext(ctx, getDraft, arg.ID, arg.UserID)
+	var i Draft
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+	)
+	return i, err
+}
+
+const listDrafts = `-- name: ListDrafts :many
+SELECT id, created_at, updated_at, body, user_id FROM drafts
+WHERE user_id = $1
+ORDER BY updated_at DESC
+`
+
+func (q *Queries) ListDrafts(ctx context.Context, userID uuid.UUID) ([]Draft, error) {
+	rows, err := q.db.QueryContext(ctx, listDrafts, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Draft
+	for rows.Next() {
+		var i Draft
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const takeDraft = `-- name: TakeDraft :one
+DELETE FROM drafts
+WHERE id = $1 AND user_id = $2
+RETURNING id, created_at, updated_at, body, user_id
+`
+
+type TakeDraftParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) TakeDraft(ctx context.Context, arg TakeDraftParams) (Draft, error) {
+	row := q.db.QueryRowContext(ctx, takeDraft, arg.ID, arg.UserID)
+	var i Draft
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+	)
+	return i, err
+}
+
+const updateDraft = `-- name: UpdateDraft :one
+UPDATE drafts
+SET body = $3, updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+RETURNING id, created_at, updated_at, body, user_id
+`
+
+type UpdateDraftParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+	Body   string
+}
+
+func (q *Queries) UpdateDraft(ctx context.Context, arg UpdateDraftParams) (Draft, error) {
+	row := q.db.QueryRowContext(ctx, updateDraft, arg.ID, arg.UserID, arg.Body)
+	var i Draft
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+	)
+	return i, err
+}
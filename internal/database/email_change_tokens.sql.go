@@ -0,0 +1,68 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: email_change_tokens.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const consumeEmailChangeToken = `-- name: ConsumeEmailChangeToken :one
+UPDATE email_change_tokens
+SET used_at = NOW()
+WHERE token_hash = $1
+AND used_at IS NULL
+AND expires_at > NOW()
+RETURNING user_id, email
+`
+
+type ConsumeEmailChangeTokenRow struct {
+	UserID uuid.UUID
+	Email  string
+}
+
+func (q *Queries) ConsumeEmailChangeToken(ctx context.Context, tokenHash string) (ConsumeEmailChangeTokenRow, error) {
+	row := q.db.QueryRowContext(ctx, consumeEmailChangeToken, tokenHash)
+	var i ConsumeEmailChangeTokenRow
+	err := row.Scan(&i.UserID, &i.Email)
+	return i, err
+}
+
+const createEmailChangeToken = `-- name: CreateEmailChangeToken :one
+INSERT INTO email_change_tokens (token_hash, created_at, user_id, email, expires_at, used_at)
+
+VALUES ($1, NOW(), $2, $3, $4, NULL)
+
+RETURNING token_hash, created_at, user_id, email, expires_at, used_at
+`
+
+type CreateEmailChangeTokenParams struct {
+	TokenHash string
+	UserID    uuid.UUID
+	Email     string
+	ExpiresAt time.Time
+}
+
+func (q *Queries) CreateEmailChangeToken(ctx context.Context, arg CreateEmailChangeTokenParams) (EmailChangeToken, error) {
+	row := q.db.QueryRowContext(ctx, createEmailChangeToken,
+		arg.TokenHash,
+		arg.UserID,
+		arg.Email,
+		arg.ExpiresAt,
+	)
+	var i EmailChangeToken
+	err := row.Scan(
+		&i.TokenHash,
+		&i.CreatedAt,
+		&i.UserID,
+		&i.Email,
+		&i.ExpiresAt,
+		&i.UsedAt,
+	)
+	return i, err
+}
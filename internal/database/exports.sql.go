@@ -0,0 +1,130 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: exports.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const completeExport = `-- name: CompleteExport :exec
+UPDATE exports
+SET status = 'ready', storage_key = $2, updated_at = NOW()
+WHERE id = $1
+`
+
+type CompleteExportParams struct {
+	ID         uuid.UUID
+	StorageKey sql.NullString
+}
+
+func (q *Queries) CompleteExport(ctx context.Context, arg CompleteExportParams) error {
+	_, err := q.db.ExecContext(ctx, completeExport, arg.ID, arg.StorageKey)
+	return err
+}
+
+const createExport = `-- name: CreateExport :one
+INSERT INTO exports (id, created_at, updated_at, user_id)
+
+VALUES (gen_random_uuid(), NOW(), NOW(), $1)
+
+RETURNING id, created_at, updated_at, user_id, status, storage_key, error
+`
+
+func (q *Queries) CreateExport(ctx context.Context, userID uuid.UUID) (Export, error) {
+	row := q.db.QueryRowContext(ctx, createExport, userID)
+	var i Export
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.Status,
+		&i.StorageKey,
+		&i.Error,
+	)
+	return i, err
+}
+
+const deleteExportsByUser = `-- name: DeleteExportsByUser :many
+DELETE FROM exports
+WHERE user_id = $1
+RETURNING id, created_at, updated_at, user_id, status, storage_key, error
+`
+
+func (q *Queries) DeleteExportsByUser(ctx context.Context, userID uuid.UUID) ([]Export, error) {
+	rows, err := q.db.QueryContext(ctx, deleteExportsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Export
+	for rows.Next() {
+		var i Export
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.Status,
+			&i.StorageKey,
+			&i.Error,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const failExport = `-- name: FailExport :exec
+UPDATE exports
+SET status = 'failed', error = $2, updated_at = NOW()
+WHERE id = $1
+`
+
+type FailExportParams struct {
+	ID    uuid.UUID
+	Error sql.NullString
+}
+
+func (q *Queries) FailExport(ctx context.Context, arg FailExportParams) error {
+	_, err := q.db.ExecContext(ctx, failExport, arg.ID, arg.Error)
+	return err
+}
+
+const getExport = `-- name: GetExport :one
+SELECT id, created_at, updated_at, user_id, status, storage_key, error FROM exports
+WHERE id = $1 AND user_id = $2
+`
+
+type GetExportParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) GetExport(ctx context.Context, arg GetExportParams) (Export, error) {
+	row := q.db.QueryRowContext(ctx, getExport, arg.ID, arg.UserID)
+	var i Export
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.Status,
+		&i.StorageKey,
+		&i.Error,
+	)
+	return i, err
+}
@@ -0,0 +1,205 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: follow_requests.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const countFollowRequests = `-- name: CountFollowRequests :one
+SELECT COUNT(*) FROM follow_requests
+JOIN users ON users.id = follow_requests.requester_id
+WHERE follow_requests.target_id = $1
+AND users.deleted_at IS NULL
+AND users.deactivated_at IS NULL
+AND users.suspended_at IS NULL
+`
+
+func (q *Queries) CountFollowRequests(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFollowRequests, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createFollowRequest = `-- name: CreateFollowRequest :exec
+INSERT INTO follow_requests (requester_id, target_id, created_at)
+
+VALUES ($1, $2, NOW())
+
+ON CONFLICT (requester_id, target_id) DO NOTHING
+`
+
+type CreateFollowRequestParams struct {
+	RequesterID uuid.UUID
+	TargetID    uuid.UUID
+}
+
+func (q *Queries) CreateFollowRequest(ctx context.Context, arg CreateFollowRequestParams) error {
+	_, err := q.db.ExecContext(ctx, createFollowRequest, arg.RequesterID, arg.TargetID)
+	return err
+}
+
+const deleteFollowRequest = `-- name: DeleteFollowRequest :execrows
+DELETE FROM follow_requests
+WHERE requester_id = $1 AND target_id = $2
+`
+
+type DeleteFollowRequestParams struct {
+	RequesterID uuid.UUID
+	TargetID    uuid.UUID
+}
+
+func (q *Queries) DeleteFollowRequest(ctx context.Context, arg DeleteFollowRequestParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteFollowRequest, arg.RequesterID, arg.TargetID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteFollowRequestsBetween = `-- name: DeleteFollowRequestsBetween :exec
+DELETE FROM follow_requests
+WHERE (requester_id = $1 AND target_id = $2)
+OR (requester_id = $2 AND target_id = $1)
+`
+
+type DeleteFollowRequestsBetweenParams struct {
+	UserA uuid.UUID
+	UserB uuid.UUID
+}
+
+func (q *Queries) DeleteFollowRequestsBetween(ctx context.Context, arg DeleteFollowRequestsBetweenParams) error {
+	_, err := q.db.ExecContext(ctx, deleteFollowRequestsBetween, arg.UserA, arg.UserB)
+	return err
+}
+
+const deleteFollowRequestsByUser = `-- name: DeleteFollowRequestsByUser :exec
+DELETE FROM follow_requests
+WHERE requester_id = $1 OR target_id = $1
+`
+
+func (q *Queries) DeleteFollowRequestsByUser(ctx context.Context, requesterID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteFollowRequestsByUser, requesterID)
+	return err
+}
+
+const listFollowRequests = `-- name: ListFollowRequests :many
+SELECT users.id, users.created_at, users.updated_at, users.email, users.hashed_password, users.role, users.locked_until, users.handle, users.pinned_chirp_id, users.suspended_at, users.display_name, users.bio, users.deleted_at, users.avatar_key, users.location, users.website, users.deactivated_at, users.is_chirpy_red, users.is_private, users.is_verified, users.pending_email, users.last_seen_at, users.show_presence, users.follower_count, users.following_count, users.chirp_count, follow_requests.created_at AS requested_at
+FROM follow_requests
+JOIN users ON users.id = follow_requests.requester_id
+WHERE follow_requests.target_id = $1
+AND users.deleted_at IS NULL
+AND users.deactivated_at IS NULL
+AND users.suspended_at IS NULL
+AND (
+    $2::timestamp IS NULL
+    OR (follow_requests.created_at, users.id) < ($2::timestamp, $3::uuid)
+)
+ORDER BY follow_requests.created_at DESC, users.id DESC
+LIMIT $4
+`
+
+type ListFollowRequestsParams struct {
+	UserID         uuid.UUID
+	AfterCreatedAt sql.NullTime
+	AfterID        uuid.NullUUID
+	MaxResults     int32
+}
+
+type ListFollowRequestsRow struct {
+	User        User
+	RequestedAt time.Time
+}
+
+func (q *Queries) ListFollowRequests(ctx context.Context, arg ListFollowRequestsParams) ([]ListFollowRequestsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listFollowRequests,
+		arg.UserID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListFollowRequestsRow
+	for rows.Next() {
+		var i ListFollowRequestsRow
+		if err := rows.Scan(
+			&i.User.ID,
+			&i.User.CreatedAt,
+			&i.User.UpdatedAt,
+			&i.User.Email,
+			&i.User.HashedPassword,
+			&i.User.Role,
+			&i.User.LockedUntil,
+			&i.User.Handle,
+			&i.User.PinnedChirpID,
+			&i.User.SuspendedAt,
+			&i.User.DisplayName,
+			&i.User.Bio,
+			&i.User.DeletedAt,
+			&i.User.AvatarKey,
+			&i.User.Location,
+			&i.User.Website,
+			&i.User.DeactivatedAt,
+			&i.User.IsChirpyRed,
+			&i.User.IsPrivate,
+			&i.User.IsVerified,
+			&i.User.PendingEmail,
+			&i.User.LastSeenAt,
+			&i.User.ShowPresence,
+			&i.User.FollowerCount,
+			&i.User.FollowingCount,
+			&i.User.ChirpCount,
+			&i.RequestedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFollowRequestsByUser = `-- name: ListFollowRequestsByUser :many
+SELECT requester_id, target_id, created_at FROM follow_requests
+WHERE requester_id = $1
+ORDER BY created_at ASC
+`
+
+func (q *Queries) ListFollowRequestsByUser(ctx context.Context, requesterID uuid.UUID) ([]FollowRequest, error) {
+	rows, err := q.db.QueryContext(ctx, listFollowRequestsByUser, requesterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FollowRequest
+	for rows.Next() {
+		var i FollowRequest
+		if err := rows.Scan(&i.RequesterID, &i.TargetID, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,371 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: follows.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const countFollowers = `-- name: CountFollowers :one
+SELECT COUNT(*) FROM follows
+JOIN users ON users.id = follows.follower_id
+WHERE follows.followee_id = $1
+AND users.deleted_at IS NULL
+AND users.deactivated_at IS NULL
+AND users.suspended_at IS NULL
+`
+
+func (q *Queries) CountFollowers(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFollowers, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countFollowing = `-- name: CountFollowing :one
+SELECT COUNT(*) FROM follows
+JOIN users ON users.id = follows.followee_id
+WHERE follows.follower_id = $1
+AND users.deleted_at IS NULL
+AND users.deactivated_at IS NULL
+AND users.suspended_at IS NULL
+`
+
+func (q *Queries) CountFollowing(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFollowing, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteFollowsBetween = `-- name: DeleteFollowsBetween :exec
+DELETE FROM follows
+WHERE (follower_id = $1 AND followee_id = $2)
+OR (follower_id = $2 AND followee_id = $1)
+`
+
+type DeleteFollowsBetweenParams struct {
+	UserA uuid.UUID
+	UserB uuid.UUID
+}
+
+func (q *Queries) DeleteFollowsBetween(ctx context.Context, arg DeleteFollowsBetweenParams) error {
+	_, err := q.db.ExecContext(ctx, deleteFollowsBetween, arg.UserA, arg.UserB)
+	return err
+}
+
+const deleteFollowsByUser = `-- name: DeleteFollowsByUser :exec
+DELETE FROM follows
+WHERE follower_id = $1 OR followee_id = $1
+`
+
+func (q *Queries) DeleteFollowsByUser(ctx context.Context, followerID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteFollowsByUser, followerID)
+	return err
+}
+
+const follow = `-- name: Follow :exec
+INSERT INTO follows (follower_id, followee_id, created_at)
+
+VALUES ($1, $2, NOW())
+
+ON CONFLICT (follower_id, followee_id) DO NOTHING
+`
+
+type FollowParams struct {
+	FollowerID uuid.UUID
+	FolloweeID uuid.UUID
+}
+
+func (q *Queries) Follow(ctx context.Context, arg FollowParams) error {
+	_, err := q.db.ExecContext(ctx, follow, arg.FollowerID, arg.FolloweeID)
+	return err
+}
+
+const getFollowStats = `-- name: GetFollowStats :many
+SELECT
+    users.id AS user_id,
+    EXISTS (
+        SELECT 1 FROM follows
+        WHERE follower_id = $1::uuid AND followee_id = users.id
+    ) AS followed_by_me,
+    EXISTS (
+        SELECT 1 FROM follow_requests
+        WHERE requester_id = $1::uuid AND target_id = users.id
+    ) AS follow_requested_by_me
+FROM users
+WHERE users.id = ANY($2::uuid[])
+`
+
+type GetFollowStatsParams struct {
+	ViewerID uuid.NullUUID
+	UserIds  []uuid.UUID
+}
+
+type GetFollowStatsRow struct {
+	UserID              uuid.UUID
+	FollowedByMe        bool
+	FollowRequestedByMe bool
+}
+
+func (q *Queries) GetFollowStats(ctx context.Context, arg GetFollowStatsParams) ([]GetFollowStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFollowStats, arg.ViewerID, pq.Array(arg.UserIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFollowStatsRow
+	for rows.Next() {
+		var i GetFollowStatsRow
+		if err := rows.Scan(&i.UserID, &i.FollowedByMe, &i.FollowRequestedByMe); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const isFollowing = `-- name: IsFollowing :one
+SELECT EXISTS (
+    SELECT 1 FROM follows
+    WHERE follower_id = $1 AND followee_id = $2
+)
+`
+
+type IsFollowingParams struct {
+	FollowerID uuid.UUID
+	FolloweeID uuid.UUID
+}
+
+func (q *Queries) IsFollowing(ctx context.Context, arg IsFollowingParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isFollowing, arg.FollowerID, arg.FolloweeID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listFollowers = `-- name: ListFollowers :many
+SELECT users.id, users.created_at, users.updated_at, users.email, users.hashed_password, users.role, users.locked_until, users.handle, users.pinned_chirp_id, users.suspended_at, users.display_name, users.bio, users.deleted_at, users.avatar_key, users.location, users.website, users.deactivated_at, users.is_chirpy_red, users.is_private, users.is_verified, users.pending_email, users.last_seen_at, users.show_presence, users.follower_count, users.following_count, users.chirp_count, follows.created_at AS followed_at
+FROM follows
+JOIN users ON users.id = follows.follower_id
+WHERE follows.followee_id = $1
+AND users.deleted_at IS NULL
+AND users.deactivated_at IS NULL
+AND users.suspended_at IS NULL
+AND (
+    $2::timestamp IS NULL
+    OR (follows.created_at, users.id) < ($2::timestamp, $3::uuid)
+)
+ORDER BY follows.created_at DESC, users.id DESC
+LIMIT $4
+`
+
+type ListFollowersParams struct {
+	UserID         uuid.UUID
+	AfterCreatedAt sql.NullTime
+	AfterID        uuid.NullUUID
+	MaxResults     int32
+}
+
+type ListFollowersRow struct {
+	User       User
+	FollowedAt time.Time
+}
+
+func (q *Queries) ListFollowers(ctx context.Context, arg ListFollowersParams) ([]ListFollowersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listFollowers,
+		arg.UserID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListFollowersRow
+	for rows.Next() {
+		var i ListFollowersRow
+		if err := rows.Scan(
+			&i.User.ID,
+			&i.User.CreatedAt,
+			&i.User.UpdatedAt,
+			&i.User.Email,
+			&i.User.HashedPassword,
+			&i.User.Role,
+			&i.User.LockedUntil,
+			&i.User.Handle,
+			&i.User.PinnedChirpID,
+			&i.User.SuspendedAt,
+			&i.User.DisplayName,
+			&i.User.Bio,
+			&i.User.DeletedAt,
+			&i.User.AvatarKey,
+			&i.User.Location,
+			&i.User.Website,
+			&i.User.DeactivatedAt,
+			&i.User.IsChirpyRed,
+			&i.User.IsPrivate,
+			&i.User.IsVerified,
+			&i.User.PendingEmail,
+			&i.User.LastSeenAt,
+			&i.User.ShowPresence,
+			&i.User.FollowerCount,
+			&i.User.FollowingCount,
+			&i.User.ChirpCount,
+			&i.FollowedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFollowing = `-- name: ListFollowing :many
+SELECT users.id, users.created_at, users.updated_at, users.email, users.hashed_password, users.role, users.locked_until, users.handle, users.pinned_chirp_id, users.suspended_at, users.display_name, users.bio, users.deleted_at, users.avatar_key, users.location, users.website, users.deactivated_at, users.is_chirpy_red, users.is_private, users.is_verified, users.pending_email, users.last_seen_at, users.show_presence, users.follower_count, users.following_count, users.chirp_count, follows.created_at AS followed_at
+FROM follows
+JOIN users ON users.id = follows.followee_id
+WHERE follows.follower_id = $1
+AND users.deleted_at IS NULL
+AND users.deactivated_at IS NULL
+AND users.suspended_at IS NULL
+AND (
+    $2::timestamp IS NULL
+    OR (follows.created_at, users.id) < ($2::timestamp, $3::uuid)
+)
+ORDER BY follows.created_at DESC, users.id DESC
+LIMIT $4
+`
+
+type ListFollowingParams struct {
+	UserID         uuid.UUID
+	AfterCreatedAt sql.NullTime
+	AfterID        uuid.NullUUID
+	MaxResults     int32
+}
+
+type ListFollowingRow struct {
+	User       User
+	FollowedAt time.Time
+}
+
+func (q *Queries) ListFollowing(ctx context.Context, arg ListFollowingParams) ([]ListFollowingRow, error) {
+	rows, err := q.db.QueryContext(ctx, listFollowing,
+		arg.UserID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListFollowingRow
+	for rows.Next() {
+		var i ListFollowingRow
+		if err := rows.Scan(
+			&i.User.ID,
+			&i.User.CreatedAt,
+			&i.User.UpdatedAt,
+			&i.User.Email,
+			&i.User.HashedPassword,
+			&i.User.Role,
+			&i.User.LockedUntil,
+			&i.User.Handle,
+			&i.User.PinnedChirpID,
+			&i.User.SuspendedAt,
+			&i.User.DisplayName,
+			&i.User.Bio,
+			&i.User.DeletedAt,
+			&i.User.AvatarKey,
+			&i.User.Location,
+			&i.User.Website,
+			&i.User.DeactivatedAt,
+			&i.User.IsChirpyRed,
+			&i.User.IsPrivate,
+			&i.User.IsVerified,
+			&i.User.PendingEmail,
+			&i.User.LastSeenAt,
+			&i.User.ShowPresence,
+			&i.User.FollowerCount,
+			&i.User.FollowingCount,
+			&i.User.ChirpCount,
+			&i.FollowedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFollowsByUser = `-- name: ListFollowsByUser :many
+SELECT follower_id, followee_id, created_at FROM follows
+WHERE follower_id = $1
+ORDER BY created_at ASC
+`
+
+func (q *Queries) ListFollowsByUser(ctx context.Context, followerID uuid.UUID) ([]Follow, error) {
+	rows, err := q.db.QueryContext(ctx, listFollowsByUser, followerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Follow
+	for rows.Next() {
+		var i Follow
+		if err := rows.Scan(&i.FollowerID, &i.FolloweeID, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const unfollow = `-- name: Unfollow :exec
+DELETE FROM follows
+WHERE follower_id = $1 AND followee_id = $2
+`
+
+type UnfollowParams struct {
+	FollowerID uuid.UUID
+	FolloweeID uuid.UUID
+}
+
+func (q *Queries) Unfollow(ctx context.Context, arg UnfollowParams) error {
+	_, err := q.db.ExecContext(ctx, unfollow, arg.FollowerID, arg.FolloweeID)
+	return err
+}
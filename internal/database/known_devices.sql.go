@@ -0,0 +1,72 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: known_devices.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const listKnownDevicesForUser = `-- name: ListKnownDevicesForUser :many
+SELECT id, user_id, ip_address, user_agent, first_seen_at, last_seen_at FROM known_devices
+WHERE user_id = $1
+ORDER BY last_seen_at DESC
+`
+
+func (q *Queries) ListKnownDevicesForUser(ctx context.Context, userID uuid.UUID) ([]KnownDevice, error) {
+	rows, err := q.db.QueryContext(ctx, listKnownDevicesForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []KnownDevice
+	for rows.Next() {
+		var i KnownDevice
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.IpAddress,
+			&i.UserAgent,
+			&i.FirstSeenAt,
+			&i.LastSeenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertKnownDevice = `-- name: UpsertKnownDevice :one
+INSERT INTO known_devices (id, user_id, ip_address, user_agent, first_seen_at, last_seen_at)
+
+VALUES (gen_random_uuid(), $1, $2, $3, NOW(), NOW())
+
+ON CONFLICT (user_id, ip_address, user_agent)
+DO UPDATE SET last_seen_at = NOW()
+
+RETURNING (xmax = 0)::boolean AS is_new
+`
+
+type UpsertKnownDeviceParams struct {
+	UserID    uuid.UUID
+	IpAddress string
+	UserAgent string
+}
+
+func (q *Queries) UpsertKnownDevice(ctx context.Context, arg UpsertKnownDeviceParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, upsertKnownDevice, arg.UserID, arg.IpAddress, arg.UserAgent)
+	var is_new bool
+	err := row.Scan(&is_new)
+	return is_new, err
+}
@@ -0,0 +1,129 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: likes.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const deleteLikesByUser = `-- name: DeleteLikesByUser :exec
+DELETE FROM likes
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteLikesByUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteLikesByUser, userID)
+	return err
+}
+
+const getChirpLikeStats = `-- name: GetChirpLikeStats :many
+SELECT
+    chirp_id,
+    COUNT(*) AS like_count,
+    COALESCE(BOOL_OR(user_id = $1::uuid), false)::boolean AS liked_by_me
+FROM likes
+WHERE chirp_id = ANY($2::uuid[])
+GROUP BY chirp_id
+`
+
+type GetChirpLikeStatsParams struct {
+	ViewerID uuid.NullUUID
+	ChirpIds []uuid.UUID
+}
+
+type GetChirpLikeStatsRow struct {
+	ChirpID   uuid.UUID
+	LikeCount int64
+	LikedByMe bool
+}
+
+func (q *Queries) GetChirpLikeStats(ctx context.Context, arg GetChirpLikeStatsParams) ([]GetChirpLikeStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpLikeStats, arg.ViewerID, pq.Array(arg.ChirpIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetChirpLikeStatsRow
+	for rows.Next() {
+		var i GetChirpLikeStatsRow
+		if err := rows.Scan(&i.ChirpID, &i.LikeCount, &i.LikedByMe); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const likeChirp = `-- name: LikeChirp :exec
+INSERT INTO likes (user_id, chirp_id, created_at)
+
+VALUES ($1, $2, NOW())
+
+ON CONFLICT (user_id, chirp_id) DO NOTHING
+`
+
+type LikeChirpParams struct {
+	UserID  uuid.UUID
+	ChirpID uuid.UUID
+}
+
+func (q *Queries) LikeChirp(ctx context.Context, arg LikeChirpParams) error {
+	_, err := q.db.ExecContext(ctx, likeChirp, arg.UserID, arg.ChirpID)
+	return err
+}
+
+const listLikesByUser = `-- name: ListLikesByUser :many
+SELECT user_id, chirp_id, created_at FROM likes
+WHERE user_id = $1
+ORDER BY created_at ASC
+`
+
+func (q *Queries) ListLikesByUser(ctx context.Context, userID uuid.UUID) ([]Like, error) {
+	rows, err := q.db.QueryContext(ctx, listLikesByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Like
+	for rows.Next() {
+		var i Like
+		if err := rows.Scan(&i.UserID, &i.ChirpID, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const unlikeChirp = `-- name: UnlikeChirp :exec
+DELETE FROM likes
+WHERE user_id = $1 AND chirp_id = $2
+`
+
+type UnlikeChirpParams struct {
+	UserID  uuid.UUID
+	ChirpID uuid.UUID
+}
+
+func (q *Queries) UnlikeChirp(ctx context.Context, arg UnlikeChirpParams) error {
+	_, err := q.db.ExecContext(ctx, unlikeChirp, arg.UserID, arg.ChirpID)
+	return err
+}
@@ -0,0 +1,94 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: link_previews.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const deleteLinkPreview = `-- name: DeleteLinkPreview :exec
+DELETE FROM link_previews
+WHERE chirp_id = $1
+`
+
+func (q *Queries) DeleteLinkPreview(ctx context.Context, chirpID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteLinkPreview, chirpID)
+	return err
+}
+
+const listLinkPreviews = `-- name: ListLinkPreviews :many
+SELECT chirp_id, fetched_at, url, title, description, image_url FROM link_previews
+WHERE chirp_id = ANY($1::uuid[])
+`
+
+func (q *Queries) ListLinkPreviews(ctx context.Context, chirpIds []uuid.UUID) ([]LinkPreview, error) {
+	rows, err := q.db.QueryContext(ctx, listLinkPreviews, pq.Array(chirpIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LinkPreview
+	for rows.Next() {
+		var i LinkPreview
+		if err := rows.Scan(
+			&i.ChirpID,
+			&i.FetchedAt,
+			&i.Url,
+			&i.Title,
+			&i.Description,
+			&i.ImageUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const saveLinkPreview = `-- name: SaveLinkPreview :exec
+INSERT INTO link_previews (chirp_id, fetched_at, url, title, description, image_url)
+SELECT chirps.id, NOW(), $1, $2, $3, $4
+FROM chirps
+WHERE chirps.id = $5
+AND chirps.updated_at = $6
+ON CONFLICT (chirp_id) DO UPDATE
+SET fetched_at = EXCLUDED.fetched_at,
+    url = EXCLUDED.url,
+    title = EXCLUDED.title,
+    description = EXCLUDED.description,
+    image_url = EXCLUDED.image_url
+`
+
+type SaveLinkPreviewParams struct {
+	Url            string
+	Title          string
+	Description    string
+	ImageUrl       string
+	ChirpID        uuid.UUID
+	ChirpUpdatedAt time.Time
+}
+
+func (q *Queries) SaveLinkPreview(ctx context.Context, arg SaveLinkPreviewParams) error {
+	_, err := q.db.ExecContext(ctx, saveLinkPreview,
+		arg.Url,
+		arg.Title,
+		arg.Description,
+		arg.ImageUrl,
+		arg.ChirpID,
+		arg.ChirpUpdatedAt,
+	)
+	return err
+}
@@ -0,0 +1,60 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: login_attempts.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const clearFailedLoginAttempts = `-- name: ClearFailedLoginAttempts :exec
+DELETE FROM login_attempts
+WHERE user_id = $1
+AND succeeded = FALSE
+`
+
+func (q *Queries) ClearFailedLoginAttempts(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, clearFailedLoginAttempts, userID)
+	return err
+}
+
+const countRecentFailedLoginAttempts = `-- name: CountRecentFailedLoginAttempts :one
+SELECT COUNT(*) FROM login_attempts
+WHERE user_id = $1
+AND succeeded = FALSE
+AND created_at > $2
+`
+
+type CountRecentFailedLoginAttemptsParams struct {
+	UserID    uuid.UUID
+	CreatedAt time.Time
+}
+
+func (q *Queries) CountRecentFailedLoginAttempts(ctx context.Context, arg CountRecentFailedLoginAttemptsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countRecentFailedLoginAttempts, arg.UserID, arg.CreatedAt)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const recordLoginAttempt = `-- name: RecordLoginAttempt :exec
+INSERT INTO login_attempts (id, created_at, user_id, ip_address, succeeded)
+
+VALUES (gen_random_uuid(), NOW(), $1, $2, $3)
+`
+
+type RecordLoginAttemptParams struct {
+	UserID    uuid.UUID
+	IpAddress string
+	Succeeded bool
+}
+
+func (q *Queries) RecordLoginAttempt(ctx context.Context, arg RecordLoginAttemptParams) error {
+	_, err := q.db.ExecContext(ctx, recordLoginAttempt, arg.UserID, arg.IpAddress, arg.Succeeded)
+	return err
+}
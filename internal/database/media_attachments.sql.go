@@ -0,0 +1,136 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: media_attachments.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const countMediaAttachments = `-- name: CountMediaAttachments :one
+SELECT COUNT(*) FROM media_attachments
+WHERE chirp_id = $1
+`
+
+func (q *Queries) CountMediaAttachments(ctx context.Context, chirpID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countMediaAttachments, chirpID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createMediaAttachment = `-- name: CreateMediaAttachment :one
+INSERT INTO media_attachments (id, created_at, chirp_id, user_id, storage_key, content_type, size_bytes)
+
+VALUES (gen_random_uuid(), NOW(), $1, $2, $3, $4, $5)
+
+RETURNING id, created_at, chirp_id, user_id, storage_key, content_type, size_bytes
+`
+
+type CreateMediaAttachmentParams struct {
+	ChirpID     uuid.UUID
+	UserID      uuid.UUID
+	StorageKey  string
+	ContentType string
+	SizeBytes   int64
+}
+
+func (q *Queries) CreateMediaAttachment(ctx context.Context, arg CreateMediaAttachmentParams) (MediaAttachment, error) {
+	row := q.db.QueryRowContext(ctx, createMediaAttachment,
+		arg.ChirpID,
+		arg.UserID,
+		arg.StorageKey,
+		arg.ContentType,
+		arg.SizeBytes,
+	)
+	var i MediaAttachment
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.ChirpID,
+		&i.UserID,
+		&i.StorageKey,
+		&i.ContentType,
+		&i.SizeBytes,
+	)
+	return i, err
+}
+
+const listMediaAttachments = `-- name: ListMediaAttachments :many
+SELECT id, created_at, chirp_id, user_id, storage_key, content_type, size_bytes FROM media_attachments
+WHERE chirp_id = ANY($1::uuid[])
+ORDER BY created_at ASC, id ASC
+`
+
+func (q *Queries) ListMediaAttachments(ctx context.Context, chirpIds []uuid.UUID) ([]MediaAttachment, error) {
+	rows, err := q.db.QueryContext(ctx, listMediaAttachments, pq.Array(chirpIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MediaAttachment
+	for rows.Next() {
+		var i MediaAttachment
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.ChirpID,
+			&i.UserID,
+			&i.StorageKey,
+			&i.ContentType,
+			&i.SizeBytes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMediaAttachmentsByUser = `-- name: ListMediaAttachmentsByUser :many
+SELECT id, created_at, chirp_id, user_id, storage_key, content_type, size_bytes FROM media_attachments
+WHERE user_id = $1
+ORDER BY created_at ASC, id ASC
+`
+
+func (q *Queries) ListMediaAttachmentsByUser(ctx context.Context, userID uuid.UUID) ([]MediaAttachment, error) {
+	rows, err := q.db.QueryContext(ctx, listMediaAttachmentsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MediaAttachment
+	for rows.Next() {
+		var i MediaAttachment
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.ChirpID,
+			&i.UserID,
+			&i.StorageKey,
+			&i.ContentType,
+			&i.SizeBytes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,173 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: mentions.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const addMention = `-- name: AddMention :exec
+INSERT INTO mentions (chirp_id, user_id, created_at)
+
+VALUES ($1, $2, NOW())
+
+ON CONFLICT DO NOTHING
+`
+
+type AddMentionParams struct {
+	ChirpID uuid.UUID
+	UserID  uuid.UUID
+}
+
+func (q *Queries) AddMention(ctx context.Context, arg AddMentionParams) error {
+	_, err := q.db.ExecContext(ctx, addMention, arg.ChirpID, arg.UserID)
+	return err
+}
+
+const countMentioningChirps = `-- name: CountMentioningChirps :one
+SELECT COUNT(*) FROM chirps
+JOIN mentions ON mentions.chirp_id = chirps.id
+WHERE mentions.user_id = $1
+AND chirps.deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = $1 AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = $1 AND mutes.muted_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id <> $1
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = $1 AND follows.followee_id = users.id
+    )
+)
+`
+
+func (q *Queries) CountMentioningChirps(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countMentioningChirps, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteMentions = `-- name: DeleteMentions :exec
+DELETE FROM mentions
+WHERE chirp_id = $1
+`
+
+func (q *Queries) DeleteMentions(ctx context.Context, chirpID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteMentions, chirpID)
+	return err
+}
+
+const isMentioned = `-- name: IsMentioned :one
+SELECT EXISTS (
+    SELECT 1 FROM mentions
+    WHERE chirp_id = $1 AND user_id = $2
+)
+`
+
+type IsMentionedParams struct {
+	ChirpID uuid.UUID
+	UserID  uuid.UUID
+}
+
+func (q *Queries) IsMentioned(ctx context.Context, arg IsMentionedParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isMentioned, arg.ChirpID, arg.UserID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listMentioningChirps = `-- name: ListMentioningChirps :many
+SELECT chirps.id, chirps.created_at, chirps.updated_at, chirps.body, chirps.user_id, chirps.search_vector, chirps.deleted_at, chirps.hidden_at, chirps.view_count, chirps.in_reply_to_id, chirps.reply_policy, chirps.language FROM chirps
+JOIN mentions ON mentions.chirp_id = chirps.id
+WHERE mentions.user_id = $1
+AND chirps.deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = $1 AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = $1 AND mutes.muted_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id <> $1
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = $1 AND follows.followee_id = users.id
+    )
+)
+AND (
+    $2::timestamp IS NULL
+    OR (chirps.created_at, chirps.id) < ($2::timestamp, $3::uuid)
+)
+ORDER BY chirps.created_at DESC, chirps.id DESC
+LIMIT $4
+`
+
+type ListMentioningChirpsParams struct {
+	UserID         uuid.UUID
+	AfterCreatedAt sql.NullTime
+	AfterID        uuid.NullUUID
+	MaxResults     int32
+}
+
+func (q *Queries) ListMentioningChirps(ctx context.Context, arg ListMentioningChirpsParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listMentioningChirps,
+		arg.UserID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.SearchVector,
+			&i.DeletedAt,
+			&i.HiddenAt,
+			&i.ViewCount,
+			&i.InReplyToID,
+			&i.ReplyPolicy,
+			&i.Language,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
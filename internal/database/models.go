@@ -5,14 +5,287 @@
 package database
 
 import (
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-type User struct {
+type ApiKey struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Name      string
+	KeyHash   string
+	RevokedAt sql.NullTime
+}
+
+type AuthEvent struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UserID    uuid.NullUUID
+	EventType string
+	IpAddress string
+	UserAgent string
+	Detail    string
+}
+
+type Block struct {
+	BlockerID uuid.UUID
+	BlockedID uuid.UUID
+	CreatedAt time.Time
+}
+
+type Bookmark struct {
+	UserID    uuid.UUID
+	ChirpID   uuid.UUID
+	CreatedAt time.Time
+}
+
+type Chirp struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Body         string
+	UserID       uuid.UUID
+	SearchVector interface{}
+	DeletedAt    sql.NullTime
+	HiddenAt     sql.NullTime
+	ViewCount    int64
+	InReplyToID  uuid.NullUUID
+	ReplyPolicy  string
+	Language     string
+}
+
+type ChirpHashtag struct {
+	ChirpID   uuid.UUID
+	Tag       string
+	CreatedAt time.Time
+}
+
+type ChirpReport struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	ChirpID    uuid.UUID
+	ReporterID uuid.UUID
+	Reason     string
+	Details    string
+	Status     string
+	Action     sql.NullString
+	ResolvedBy uuid.NullUUID
+	ResolvedAt sql.NullTime
+}
+
+type ChirpRevision struct {
+	ID         uuid.UUID
+	ChirpID    uuid.UUID
+	Body       string
+	CreatedAt  time.Time
+	ReplacedAt time.Time
+}
+
+type Draft struct {
 	ID        uuid.UUID
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	Body      string
+	UserID    uuid.UUID
+}
+
+type EmailChangeToken struct {
+	TokenHash string
+	CreatedAt time.Time
+	UserID    uuid.UUID
 	Email     string
+	ExpiresAt time.Time
+	UsedAt    sql.NullTime
+}
+
+type Export struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	UserID     uuid.UUID
+	Status     string
+	StorageKey sql.NullString
+	Error      sql.NullString
+}
+
+type Follow struct {
+	FollowerID uuid.UUID
+	FolloweeID uuid.UUID
+	CreatedAt  time.Time
+}
+
+type FollowRequest struct {
+	RequesterID uuid.UUID
+	TargetID    uuid.UUID
+	CreatedAt   time.Time
+}
+
+type IdempotencyKey struct {
+	UserID       uuid.NullUUID
+	Route        string
+	Key          string
+	RequestHash  []byte
+	StatusCode   sql.NullInt32
+	ContentType  sql.NullString
+	ResponseBody []byte
+	CreatedAt    time.Time
+}
+
+type KnownDevice struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	IpAddress   string
+	UserAgent   string
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
+}
+
+type Like struct {
+	UserID    uuid.UUID
+	ChirpID   uuid.UUID
+	CreatedAt time.Time
+}
+
+type LinkPreview struct {
+	ChirpID     uuid.UUID
+	FetchedAt   time.Time
+	Url         string
+	Title       string
+	Description string
+	ImageUrl    string
+}
+
+type LoginAttempt struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UserID    uuid.UUID
+	IpAddress string
+	Succeeded bool
+}
+
+type MediaAttachment struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	ChirpID     uuid.UUID
+	UserID      uuid.UUID
+	StorageKey  string
+	ContentType string
+	SizeBytes   int64
+}
+
+type Mention struct {
+	ChirpID   uuid.UUID
+	UserID    uuid.UUID
+	CreatedAt time.Time
+}
+
+type Mute struct {
+	MuterID   uuid.UUID
+	MutedID   uuid.UUID
+	CreatedAt time.Time
+}
+
+type Notification struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UserID    uuid.UUID
+	Kind      string
+	Body      string
+	ReadAt    sql.NullTime
+}
+
+type NotificationPreference struct {
+	UserID    uuid.UUID
+	Kind      string
+	InApp     bool
+	Email     bool
+	UpdatedAt time.Time
+}
+
+type PersonalAccessToken struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	UserID     uuid.UUID
+	Name       string
+	TokenHash  string
+	Scopes     []string
+	LastUsedAt sql.NullTime
+	RevokedAt  sql.NullTime
+}
+
+type Rechirp struct {
+	UserID    uuid.UUID
+	ChirpID   uuid.UUID
+	CreatedAt time.Time
+}
+
+type RefreshToken struct {
+	Token     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	ExpiresAt time.Time
+	RevokedAt sql.NullTime
+	ID        uuid.UUID
+	UserAgent string
+	IpAddress string
+	Kind      string
+}
+
+type ResetToken struct {
+	TokenHash string
+	CreatedAt time.Time
+	UserID    uuid.UUID
+	ExpiresAt time.Time
+	UsedAt    sql.NullTime
+}
+
+type ScheduledChirp struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	Body      string
+	UserID    uuid.UUID
+	PublishAt time.Time
+}
+
+type Suggestion struct {
+	UserID      uuid.NullUUID
+	SuggestedID uuid.UUID
+	MutualCount int32
+	Score       int64
+	ComputedAt  time.Time
+}
+
+type User struct {
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Email          string
+	HashedPassword string
+	Role           string
+	LockedUntil    sql.NullTime
+	Handle         string
+	PinnedChirpID  uuid.NullUUID
+	SuspendedAt    sql.NullTime
+	DisplayName    sql.NullString
+	Bio            sql.NullString
+	DeletedAt      sql.NullTime
+	AvatarKey      sql.NullString
+	Location       sql.NullString
+	Website        sql.NullString
+	DeactivatedAt  sql.NullTime
+	IsChirpyRed    bool
+	IsPrivate      bool
+	IsVerified     bool
+	PendingEmail   sql.NullString
+	LastSeenAt     sql.NullTime
+	ShowPresence   bool
+	FollowerCount  int64
+	FollowingCount int64
+	ChirpCount     int64
 }
@@ -0,0 +1,180 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: mutes.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const countMutes = `-- name: CountMutes :one
+SELECT COUNT(*) FROM mutes
+JOIN users ON users.id = mutes.muted_id
+WHERE mutes.muter_id = $1
+`
+
+func (q *Queries) CountMutes(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countMutes, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteMutesByUser = `-- name: DeleteMutesByUser :exec
+DELETE FROM mutes
+WHERE muter_id = $1 OR muted_id = $1
+`
+
+func (q *Queries) DeleteMutesByUser(ctx context.Context, muterID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteMutesByUser, muterID)
+	return err
+}
+
+const listMutes = `-- name: ListMutes :many
+SELECT users.id, users.created_at, users.updated_at, users.email, users.hashed_password, users.role, users.locked_until, users.handle, users.pinned_chirp_id, users.suspended_at, users.display_name, users.bio, users.deleted_at, users.avatar_key, users.location, users.website, users.deactivated_at, users.is_chirpy_red, users.is_private, users.is_verified, users.pending_email, users.last_seen_at, users.show_presence, users.follower_count, users.following_count, users.chirp_count, mutes.created_at AS muted_at
+FROM mutes
+JOIN users ON users.id = mutes.muted_id
+WHERE mutes.muter_id = $1
+AND (
+    $2::timestamp IS NULL
+    OR (mutes.created_at, users.id) < ($2::timestamp, $3::uuid)
+)
+ORDER BY mutes.created_at DESC, users.id DESC
+LIMIT $4
+`
+
+type ListMutesParams struct {
+	UserID         uuid.UUID
+	AfterCreatedAt sql.NullTime
+	AfterID        uuid.NullUUID
+	MaxResults     int32
+}
+
+type ListMutesRow struct {
+	User    User
+	MutedAt time.Time
+}
+
+func (q *Queries) ListMutes(ctx context.Context, arg ListMutesParams) ([]ListMutesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listMutes,
+		arg.UserID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMutesRow
+	for rows.Next() {
+		var i ListMutesRow
+		if err := rows.Scan(
+			&i.User.ID,
+			&i.User.CreatedAt,
+			&i.User.UpdatedAt,
+			&i.User.Email,
+			&i.User.HashedPassword,
+			&i.User.Role,
+			&i.User.LockedUntil,
+			&i.User.Handle,
+			&i.User.PinnedChirpID,
+			&i.User.SuspendedAt,
+			&i.User.DisplayName,
+			&i.User.Bio,
+			&i.User.DeletedAt,
+			&i.User.AvatarKey,
+			&i.User.Location,
+			&i.User.Website,
+			&i.User.DeactivatedAt,
+			&i.User.IsChirpyRed,
+			&i.User.IsPrivate,
+			&i.User.IsVerified,
+			&i.User.PendingEmail,
+			&i.User.LastSeenAt,
+			&i.User.ShowPresence,
+			&i.User.FollowerCount,
+			&i.User.FollowingCount,
+			&i.User.ChirpCount,
+			&i.MutedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMutesByUser = `-- name: ListMutesByUser :many
+SELECT muter_id, muted_id, created_at FROM mutes
+WHERE muter_id = $1
+ORDER BY created_at ASC
+`
+
+func (q *Queries) ListMutesByUser(ctx context.Context, muterID uuid.UUID) ([]Mute, error) {
+	rows, err := q.db.QueryContext(ctx, listMutesByUser, muterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Mute
+	for rows.Next() {
+		var i Mute
+		if err := rows.Scan(&i.MuterID, &i.MutedID, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const mute = `-- name: Mute :exec
+INSERT INTO mutes (muter_id, muted_id, created_at)
+
+VALUES ($1, $2, NOW())
+
+ON CONFLICT (muter_id, muted_id) DO NOTHING
+`
+
+type MuteParams struct {
+	MuterID uuid.UUID
+	MutedID uuid.UUID
+}
+
+func (q *Queries) Mute(ctx context.Context, arg MuteParams) error {
+	_, err := q.db.ExecContext(ctx, mute, arg.MuterID, arg.MutedID)
+	return err
+}
+
+const unmute = `-- name: Unmute :exec
+DELETE FROM mutes
+WHERE muter_id = $1 AND muted_id = $2
+`
+
+type UnmuteParams struct {
+	MuterID uuid.UUID
+	MutedID uuid.UUID
+}
+
+func (q *Queries) Unmute(ctx context.Context, arg UnmuteParams) error {
+	_, err := q.db.ExecContext(ctx, unmute, arg.MuterID, arg.MutedID)
+	return err
+}
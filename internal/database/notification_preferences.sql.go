@@ -0,0 +1,96 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: notification_preferences.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const getNotificationPreference = `-- name: GetNotificationPreference :one
+SELECT user_id, kind, in_app, email, updated_at FROM notification_preferences
+WHERE user_id = $1 AND kind = $2
+`
+
+type GetNotificationPreferenceParams struct {
+	UserID uuid.UUID
+	Kind   string
+}
+
+func (q *Queries) GetNotificationPreference(ctx context.Context, arg GetNotificationPreferenceParams) (NotificationPreference, error) {
+	row := q.db.QueryRowContext(ctx, getNotificationPreference, arg.UserID, arg.Kind)
+	var i NotificationPreference
+	err := row.Scan(
+		&i.UserID,
+		&i.Kind,
+		&i.InApp,
+		&i.Email,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listNotificationPreferences = `-- name: ListNotificationPreferences :many
+SELECT user_id, kind, in_app, email, updated_at FROM notification_preferences
+WHERE user_id = $1
+ORDER BY kind ASC
+`
+
+func (q *Queries) ListNotificationPreferences(ctx context.Context, userID uuid.UUID) ([]NotificationPreference, error) {
+	rows, err := q.db.QueryContext(ctx, listNotificationPreferences, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationPreference
+	for rows.Next() {
+		var i NotificationPreference
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Kind,
+			&i.InApp,
+			&i.Email,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertNotificationPreference = `-- name: UpsertNotificationPreference :exec
+INSERT INTO notification_preferences (user_id, kind, in_app, email, updated_at)
+
+VALUES ($1, $2, $3, $4, NOW())
+
+ON CONFLICT (user_id, kind)
+DO UPDATE SET in_app = EXCLUDED.in_app, email = EXCLUDED.email, updated_at = NOW()
+`
+
+type UpsertNotificationPreferenceParams struct {
+	UserID uuid.UUID
+	Kind   string
+	InApp  bool
+	Email  bool
+}
+
+func (q *Queries) UpsertNotificationPreference(ctx context.Context, arg UpsertNotificationPreferenceParams) error {
+	_, err := q.db.ExecContext(ctx, upsertNotificationPreference,
+		arg.UserID,
+		arg.Kind,
+		arg.InApp,
+		arg.Email,
+	)
+	return err
+}
@@ -0,0 +1,245 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: rechirps.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const countUserTimeline = `-- name: CountUserTimeline :one
+SELECT COUNT(*) FROM (
+    SELECT id AS chirp_id
+    FROM chirps
+    WHERE chirps.user_id = $1
+    UNION ALL
+    SELECT rechirps.chirp_id
+    FROM rechirps
+    WHERE rechirps.user_id = $1
+) AS timeline
+JOIN chirps ON chirps.id = timeline.chirp_id
+WHERE chirps.deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = $2::uuid AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = $2::uuid AND mutes.muted_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id IS DISTINCT FROM $2::uuid
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = $2::uuid AND follows.followee_id = users.id
+    )
+)
+`
+
+type CountUserTimelineParams struct {
+	UserID   uuid.UUID
+	ViewerID uuid.NullUUID
+}
+
+func (q *Queries) CountUserTimeline(ctx context.Context, arg CountUserTimelineParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUserTimeline, arg.UserID, arg.ViewerID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteRechirpsByUser = `-- name: DeleteRechirpsByUser :exec
+DELETE FROM rechirps
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteRechirpsByUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteRechirpsByUser, userID)
+	return err
+}
+
+const getChirpRechirpStats = `-- name: GetChirpRechirpStats :many
+SELECT
+    chirp_id,
+    COUNT(*) AS rechirp_count,
+    COALESCE(BOOL_OR(user_id = $1::uuid), false)::boolean AS rechirped_by_me
+FROM rechirps
+WHERE chirp_id = ANY($2::uuid[])
+GROUP BY chirp_id
+`
+
+type GetChirpRechirpStatsParams struct {
+	ViewerID uuid.NullUUID
+	ChirpIds []uuid.UUID
+}
+
+type GetChirpRechirpStatsRow struct {
+	ChirpID       uuid.UUID
+	RechirpCount  int64
+	RechirpedByMe bool
+}
+
+func (q *Queries) GetChirpRechirpStats(ctx context.Context, arg GetChirpRechirpStatsParams) ([]GetChirpRechirpStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getChirpRechirpStats, arg.ViewerID, pq.Array(arg.ChirpIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetChirpRechirpStatsRow
+	for rows.Next() {
+		var i GetChirpRechirpStatsRow
+		if err := rows.Scan(&i.ChirpID, &i.RechirpCount, &i.RechirpedByMe); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserTimeline = `-- name: ListUserTimeline :many
+SELECT chirps.id, chirps.created_at, chirps.updated_at, chirps.body, chirps.user_id, chirps.search_vector, chirps.deleted_at, chirps.hidden_at, chirps.view_count, chirps.in_reply_to_id, chirps.reply_policy, chirps.language, timeline.activity_at::timestamp AS activity_at, timeline.rechirped::boolean AS rechirped
+FROM (
+    SELECT id AS chirp_id, created_at AS activity_at, false AS rechirped
+    FROM chirps
+    WHERE chirps.user_id = $1
+    UNION ALL
+    SELECT rechirps.chirp_id, rechirps.created_at, true
+    FROM rechirps
+    WHERE rechirps.user_id = $1
+) AS timeline
+JOIN chirps ON chirps.id = timeline.chirp_id
+WHERE chirps.deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = $2::uuid AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = $2::uuid AND mutes.muted_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id IS DISTINCT FROM $2::uuid
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = $2::uuid AND follows.followee_id = users.id
+    )
+)
+AND (
+    $3::timestamp IS NULL
+    OR (timeline.activity_at, chirps.id) < ($3::timestamp, $4::uuid)
+)
+ORDER BY timeline.activity_at DESC, chirps.id DESC
+LIMIT $5
+`
+
+type ListUserTimelineParams struct {
+	UserID         uuid.UUID
+	ViewerID       uuid.NullUUID
+	AfterCreatedAt sql.NullTime
+	AfterID        uuid.NullUUID
+	MaxResults     int32
+}
+
+type ListUserTimelineRow struct {
+	Chirp      Chirp
+	ActivityAt time.Time
+	Rechirped  bool
+}
+
+func (q *Queries) ListUserTimeline(ctx context.Context, arg ListUserTimelineParams) ([]ListUserTimelineRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUserTimeline,
+		arg.UserID,
+		arg.ViewerID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUserTimelineRow
+	for rows.Next() {
+		var i ListUserTimelineRow
+		if err := rows.Scan(
+			&i.Chirp.ID,
+			&i.Chirp.CreatedAt,
+			&i.Chirp.UpdatedAt,
+			&i.Chirp.Body,
+			&i.Chirp.UserID,
+			&i.Chirp.SearchVector,
+			&i.Chirp.DeletedAt,
+			&i.Chirp.HiddenAt,
+			&i.Chirp.ViewCount,
+			&i.Chirp.InReplyToID,
+			&i.Chirp.ReplyPolicy,
+			&i.Chirp.Language,
+			&i.ActivityAt,
+			&i.Rechirped,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const rechirp = `-- name: Rechirp :exec
+INSERT INTO rechirps (user_id, chirp_id, created_at)
+
+VALUES ($1, $2, NOW())
+
+ON CONFLICT (user_id, chirp_id) DO NOTHING
+`
+
+type RechirpParams struct {
+	UserID  uuid.UUID
+	ChirpID uuid.UUID
+}
+
+func (q *Queries) Rechirp(ctx context.Context, arg RechirpParams) error {
+	_, err := q.db.ExecContext(ctx, rechirp, arg.UserID, arg.ChirpID)
+	return err
+}
+
+const undoRechirp = `-- name: UndoRechirp :exec
+DELETE FROM rechirps
+WHERE user_id = $1 AND chirp_id = $2
+`
+
+type UndoRechirpParams struct {
+	UserID  uuid.UUID
+	ChirpID uuid.UUID
+}
+
+func (q *Queries) UndoRechirp(ctx context.Context, arg UndoRechirpParams) error {
+	_, err := q.db.ExecContext(ctx, undoRechirp, arg.UserID, arg.ChirpID)
+	return err
+}
@@ -0,0 +1,56 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: reset_tokens.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const consumeResetToken = `-- name: ConsumeResetToken :one
+UPDATE reset_tokens
+SET used_at = NOW()
+WHERE token_hash = $1
+AND used_at IS NULL
+AND expires_at > NOW()
+RETURNING user_id
+`
+
+func (q *Queries) ConsumeResetToken(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, consumeResetToken, tokenHash)
+	var user_id uuid.UUID
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
+const createResetToken = `-- name: CreateResetToken :one
+INSERT INTO reset_tokens (token_hash, created_at, user_id, expires_at, used_at)
+
+VALUES ($1, NOW(), $2, $3, NULL)
+
+RETURNING token_hash, created_at, user_id, expires_at, used_at
+`
+
+type CreateResetTokenParams struct {
+	TokenHash string
+	UserID    uuid.UUID
+	ExpiresAt time.Time
+}
+
+func (q *Queries) CreateResetToken(ctx context.Context, arg CreateResetTokenParams) (ResetToken, error) {
+	row := q.db.QueryRowContext(ctx, createResetToken, arg.TokenHash, arg.UserID, arg.ExpiresAt)
+	var i ResetToken
+	err := row.Scan(
+		&i.TokenHash,
+		&i.CreatedAt,
+		&i.UserID,
+		&i.ExpiresAt,
+		&i.UsedAt,
+	)
+	return i, err
+}
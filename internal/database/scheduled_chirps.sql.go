@@ -0,0 +1,160 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: scheduled_chirps.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const claimDueScheduledChirps = `-- name: ClaimDueScheduledChirps :many
+DELETE FROM scheduled_chirps
+WHERE id IN (
+    SELECT id FROM scheduled_chirps
+    WHERE publish_at <= NOW()
+    ORDER BY publish_at ASC
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, created_at, body, user_id, publish_at
+`
+
+func (q *Queries) ClaimDueScheduledChirps(ctx context.Context, maxResults int32) ([]ScheduledChirp, error) {
+	rows, err := q.db.QueryContext(ctx, claimDueScheduledChirps, maxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ScheduledChirp
+	for rows.Next() {
+		var i ScheduledChirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.PublishAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createScheduledChirp = `-- name: CreateScheduledChirp :one
+INSERT INTO scheduled_chirps (id, created_at, body, user_id, publish_at)
+
+VALUES (gen_random_uuid(), NOW(), $1, $2, $3)
+
+RETURNING id, created_at, body, user_id, publish_at
+`
+
+type CreateScheduledChirpParams struct {
+	Body      string
+	UserID    uuid.UUID
+	PublishAt time.Time
+}
+
+func (q *Queries) CreateScheduledChirp(ctx context.Context, arg CreateScheduledChirpParams) (ScheduledChirp, error) {
+	row := q.db.QueryRowContext(ctx, createScheduledChirp, arg.Body, arg.UserID, arg.PublishAt)
+	var i ScheduledChirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.PublishAt,
+	)
+	return i, err
+}
+
+const deleteScheduledChirpsByUser = `-- name: DeleteScheduledChirpsByUser :exec
+DELETE FROM scheduled_chirps
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteScheduledChirpsByUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteScheduledChirpsByUser, userID)
+	return err
+}
+
+const listScheduledChirps = `-- name: ListScheduledChirps :many
+SELECT id, created_at, body, user_id, publish_at FROM scheduled_chirps
+ORDER BY publish_at ASC, id ASC
+`
+
+func (q *Queries) ListScheduledChirps(ctx context.Context) ([]ScheduledChirp, error) {
+	rows, err := q.db.QueryContext(ctx, listScheduledChirps)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ScheduledChirp
+	for rows.Next() {
+		var i ScheduledChirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.PublishAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listScheduledChirpsByUser = `-- name: ListScheduledChirpsByUser :many
+SELECT id, created_at, body, user_id, publish_at FROM scheduled_chirps
+WHERE user_id = $1
+ORDER BY publish_at ASC, id ASC
+`
+
+func (q *Queries) ListScheduledChirpsByUser(ctx context.Context, userID uuid.UUID) ([]ScheduledChirp, error) {
+	rows, err := q.db.QueryContext(ctx, listScheduledChirpsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ScheduledChirp
+	for rows.Next() {
+		var i ScheduledChirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.PublishAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
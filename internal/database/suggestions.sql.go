@@ -0,0 +1,187 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: suggestions.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const deleteSuggestions = `-- name: DeleteSuggestions :exec
+DELETE FROM suggestions
+`
+
+func (q *Queries) DeleteSuggestions(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteSuggestions)
+	return err
+}
+
+const insertGeneralSuggestions = `-- name: InsertGeneralSuggestions :exec
+INSERT INTO suggestions (user_id, suggested_id, mutual_count, score, computed_at)
+SELECT NULL, chirps.user_id, 0, COUNT(*), NOW()
+FROM chirps
+WHERE chirps.created_at > $1::timestamp
+AND chirps.deleted_at IS NULL
+GROUP BY chirps.user_id
+ORDER BY COUNT(*) DESC, chirps.user_id
+LIMIT $2
+`
+
+type InsertGeneralSuggestionsParams struct {
+	ActiveSince time.Time
+	MaxResults  int32
+}
+
+// Suggests the accounts that chirped the most recently, to everyone.
+func (q *Queries) InsertGeneralSuggestions(ctx context.Context, arg InsertGeneralSuggestionsParams) error {
+	_, err := q.db.ExecContext(ctx, insertGeneralSuggestions, arg.ActiveSince, arg.MaxResults)
+	return err
+}
+
+const insertPersonalSuggestions = `-- name: InsertPersonalSuggestions :exec
+INSERT INTO suggestions (user_id, suggested_id, mutual_count, score, computed_at)
+SELECT ranked.user_id, ranked.suggested_id, ranked.mutual_count, ranked.score, NOW()
+FROM (
+    SELECT candidates.user_id, candidates.suggested_id, candidates.mutual_count, candidates.score,
+        ROW_NUMBER() OVER (PARTITION BY candidates.user_id ORDER BY candidates.score DESC, candidates.suggested_id) AS position
+    FROM (
+        SELECT
+            mine.follower_id AS user_id,
+            theirs.followee_id AS suggested_id,
+            COUNT(*)::integer AS mutual_count,
+            COUNT(*) * $1::bigint + COALESCE(MAX(activity.chirp_count), 0) AS score
+        FROM follows mine
+        JOIN follows theirs ON theirs.follower_id = mine.followee_id
+        LEFT JOIN (
+            SELECT chirps.user_id, COUNT(*) AS chirp_count FROM chirps
+            WHERE chirps.created_at > $2::timestamp AND chirps.deleted_at IS NULL
+            GROUP BY chirps.user_id
+        ) AS activity ON activity.user_id = theirs.followee_id
+        WHERE theirs.followee_id <> mine.follower_id
+        AND NOT EXISTS (
+            SELECT 1 FROM follows
+            WHERE follows.follower_id = mine.follower_id AND follows.followee_id = theirs.followee_id
+        )
+        GROUP BY mine.follower_id, theirs.followee_id
+    ) AS candidates
+) AS ranked
+WHERE ranked.position <= $3::bigint
+`
+
+type InsertPersonalSuggestionsParams struct {
+	MutualWeight int64
+	ActiveSince  time.Time
+	PerUser      int64
+}
+
+// Suggests the accounts followed by the people a user follows. Each mutual
+// follow is worth mutual_weight points and each recent chirp one point.
+func (q *Queries) InsertPersonalSuggestions(ctx context.Context, arg InsertPersonalSuggestionsParams) error {
+	_, err := q.db.ExecContext(ctx, insertPersonalSuggestions, arg.MutualWeight, arg.ActiveSince, arg.PerUser)
+	return err
+}
+
+const listSuggestions = `-- name: ListSuggestions :many
+SELECT users.id, users.created_at, users.updated_at, users.email, users.hashed_password, users.role, users.locked_until, users.handle, users.pinned_chirp_id, users.suspended_at, users.display_name, users.bio, users.deleted_at, users.avatar_key, users.location, users.website, users.deactivated_at, users.is_chirpy_red, users.is_private, users.is_verified, users.pending_email, users.last_seen_at, users.show_presence, users.follower_count, users.following_count, users.chirp_count, suggestions.mutual_count
+FROM suggestions
+JOIN users ON users.id = suggestions.suggested_id
+WHERE (suggestions.user_id = $1::uuid OR suggestions.user_id IS NULL)
+AND users.id <> $1::uuid
+AND users.deleted_at IS NULL
+AND users.deactivated_at IS NULL
+AND users.suspended_at IS NULL
+AND (
+    suggestions.user_id IS NOT NULL
+    OR NOT EXISTS (
+        SELECT 1 FROM suggestions AS personal
+        WHERE personal.user_id = $1::uuid AND personal.suggested_id = suggestions.suggested_id
+    )
+)
+AND NOT EXISTS (
+    SELECT 1 FROM follows
+    WHERE follows.follower_id = $1::uuid AND follows.followee_id = users.id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM follow_requests
+    WHERE follow_requests.requester_id = $1::uuid AND follow_requests.target_id = users.id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE (blocks.blocker_id = $1::uuid AND blocks.blocked_id = users.id)
+    OR (blocks.blocker_id = users.id AND blocks.blocked_id = $1::uuid)
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = $1::uuid AND mutes.muted_id = users.id
+)
+ORDER BY suggestions.user_id IS NULL, suggestions.score DESC, users.id
+LIMIT $2
+`
+
+type ListSuggestionsParams struct {
+	UserID     uuid.UUID
+	MaxResults int32
+}
+
+type ListSuggestionsRow struct {
+	User        User
+	MutualCount int32
+}
+
+// Personal suggestions come first, then general ones. Follows, requests,
+// blocks and mutes made since the last refresh are applied here.
+func (q *Queries) ListSuggestions(ctx context.Context, arg ListSuggestionsParams) ([]ListSuggestionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listSuggestions, arg.UserID, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSuggestionsRow
+	for rows.Next() {
+		var i ListSuggestionsRow
+		if err := rows.Scan(
+			&i.User.ID,
+			&i.User.CreatedAt,
+			&i.User.UpdatedAt,
+			&i.User.Email,
+			&i.User.HashedPassword,
+			&i.User.Role,
+			&i.User.LockedUntil,
+			&i.User.Handle,
+			&i.User.PinnedChirpID,
+			&i.User.SuspendedAt,
+			&i.User.DisplayName,
+			&i.User.Bio,
+			&i.User.DeletedAt,
+			&i.User.AvatarKey,
+			&i.User.Location,
+			&i.User.Website,
+			&i.User.DeactivatedAt,
+			&i.User.IsChirpyRed,
+			&i.User.IsPrivate,
+			&i.User.IsVerified,
+			&i.User.PendingEmail,
+			&i.User.LastSeenAt,
+			&i.User.ShowPresence,
+			&i.User.FollowerCount,
+			&i.User.FollowingCount,
+			&i.User.ChirpCount,
+			&i.MutualCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -7,24 +7,896 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const anonymizeUser = `-- name: AnonymizeUser :execrows
+UPDATE users
+SET email = 'deleted+' || id::text || '@invalid',
+    handle = 'del_' || LEFT(REPLACE(id::text, '-', ''), 10),
+    hashed_password = '',
+    pending_email = NULL,
+    display_name = NULL,
+    bio = NULL,
+    location = NULL,
+    website = NULL,
+    pinned_chirp_id = NULL,
+    avatar_key = NULL,
+    deleted_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) AnonymizeUser(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, anonymizeUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const confirmPendingEmail = `-- name: ConfirmPendingEmail :one
+UPDATE users
+SET email = pending_email, pending_email = NULL, updated_at = NOW()
+WHERE id = $1 AND pending_email = $2::text
+RETURNING id, created_at, updated_at, email, hashed_password, role, locked_until, handle, pinned_chirp_id, suspended_at, display_name, bio, deleted_at, avatar_key, location, website, deactivated_at, is_chirpy_red, is_private, is_verified, pending_email, last_seen_at, show_presence, follower_count, following_count, chirp_count
+`
+
+type ConfirmPendingEmailParams struct {
+	ID    uuid.UUID
+	Email string
+}
+
+func (q *Queries) ConfirmPendingEmail(ctx context.Context, arg ConfirmPendingEmailParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, confirmPendingEmail, arg.ID, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Email,
+		&i.HashedPassword,
+		&i.Role,
+		&i.LockedUntil,
+		&i.Handle,
+		&i.PinnedChirpID,
+		&i.SuspendedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.DeletedAt,
+		&i.AvatarKey,
+		&i.Location,
+		&i.Website,
+		&i.DeactivatedAt,
+		&i.IsChirpyRed,
+		&i.IsPrivate,
+		&i.IsVerified,
+		&i.PendingEmail,
+		&i.LastSeenAt,
+		&i.ShowPresence,
+		&i.FollowerCount,
+		&i.FollowingCount,
+		&i.ChirpCount,
+	)
+	return i, err
+}
+
+const countSearchUsers = `-- name: CountSearchUsers :one
+SELECT COUNT(*) FROM users
+WHERE (
+    LOWER(users.handle) LIKE $1
+    OR LOWER(users.display_name) LIKE $1
 )
+AND users.deleted_at IS NULL
+AND users.deactivated_at IS NULL
+AND users.suspended_at IS NULL
+`
+
+func (q *Queries) CountSearchUsers(ctx context.Context, pattern string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSearchUsers, pattern)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
 
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (id, created_at, updated_at, email)
+INSERT INTO users (id, created_at, updated_at, email, hashed_password, handle)
 
-VALUES (gen_random_uuid(), NOW(), NOW(), $1)
+VALUES (gen_random_uuid(), NOW(), NOW(), $1, $2, $3)
+ON CONFLICT DO NOTHING
+RETURNING id, created_at, updated_at, email, hashed_password, role, locked_until, handle, pinned_chirp_id, suspended_at, display_name, bio, deleted_at, avatar_key, location, website, deactivated_at, is_chirpy_red, is_private, is_verified, pending_email, last_seen_at, show_presence, follower_count, following_count, chirp_count
+`
 
-RETURNING id, created_at, updated_at, email
+type CreateUserParams struct {
+	Email          string
+	HashedPassword string
+	Handle         string
+}
+
+func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, createUser, arg.Email, arg.HashedPassword, arg.Handle)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Email,
+		&i.HashedPassword,
+		&i.Role,
+		&i.LockedUntil,
+		&i.Handle,
+		&i.PinnedChirpID,
+		&i.SuspendedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.DeletedAt,
+		&i.AvatarKey,
+		&i.Location,
+		&i.Website,
+		&i.DeactivatedAt,
+		&i.IsChirpyRed,
+		&i.IsPrivate,
+		&i.IsVerified,
+		&i.PendingEmail,
+		&i.LastSeenAt,
+		&i.ShowPresence,
+		&i.FollowerCount,
+		&i.FollowingCount,
+		&i.ChirpCount,
+	)
+	return i, err
+}
+
+const deactivateUser = `-- name: DeactivateUser :execrows
+UPDATE users
+SET deactivated_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND deactivated_at IS NULL
 `
 
-func (q *Queries) CreateUser(ctx context.Context, email string) (User, error) {
-	row := q.db.QueryRowContext(ctx, createUser, email)
+func (q *Queries) DeactivateUser(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deactivateUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getMentionableUserIDs = `-- name: GetMentionableUserIDs :many
+SELECT id FROM users
+WHERE LOWER(handle) = ANY($1::text[])
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = users.id AND blocks.blocked_id = $2
+)
+`
+
+type GetMentionableUserIDsParams struct {
+	Handles  []string
+	AuthorID uuid.UUID
+}
+
+func (q *Queries) GetMentionableUserIDs(ctx context.Context, arg GetMentionableUserIDsParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getMentionableUserIDs, pq.Array(arg.Handles), arg.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT id, created_at, updated_at, email, hashed_password, role, locked_until, handle, pinned_chirp_id, suspended_at, display_name, bio, deleted_at, avatar_key, location, website, deactivated_at, is_chirpy_red, is_private, is_verified, pending_email, last_seen_at, show_presence, follower_count, following_count, chirp_count FROM users
+WHERE email = $1
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmail, email)
 	var i User
 	err := row.Scan(
 		&i.ID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Email,
+		&i.HashedPassword,
+		&i.Role,
+		&i.LockedUntil,
+		&i.Handle,
+		&i.PinnedChirpID,
+		&i.SuspendedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.DeletedAt,
+		&i.AvatarKey,
+		&i.Location,
+		&i.Website,
+		&i.DeactivatedAt,
+		&i.IsChirpyRed,
+		&i.IsPrivate,
+		&i.IsVerified,
+		&i.PendingEmail,
+		&i.LastSeenAt,
+		&i.ShowPresence,
+		&i.FollowerCount,
+		&i.FollowingCount,
+		&i.ChirpCount,
 	)
 	return i, err
 }
+
+const getUserByHandle = `-- name: GetUserByHandle :one
+SELECT id, created_at, updated_at, email, hashed_password, role, locked_until, handle, pinned_chirp_id, suspended_at, display_name, bio, deleted_at, avatar_key, location, website, deactivated_at, is_chirpy_red, is_private, is_verified, pending_email, last_seen_at, show_presence, follower_count, following_count, chirp_count FROM users
+WHERE LOWER(handle) = LOWER($1)
+`
+
+func (q *Queries) GetUserByHandle(ctx context.Context, handle string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByHandle, handle)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Email,
+		&i.HashedPassword,
+		&i.Role,
+		&i.LockedUntil,
+		&i.Handle,
+		&i.PinnedChirpID,
+		&i.SuspendedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.DeletedAt,
+		&i.AvatarKey,
+		&i.Location,
+		&i.Website,
+		&i.DeactivatedAt,
+		&i.IsChirpyRed,
+		&i.IsPrivate,
+		&i.IsVerified,
+		&i.PendingEmail,
+		&i.LastSeenAt,
+		&i.ShowPresence,
+		&i.FollowerCount,
+		&i.FollowingCount,
+		&i.ChirpCount,
+	)
+	return i, err
+}
+
+const getUserByID = `-- name: GetUserByID :one
+SELECT id, created_at, updated_at, email, hashed_password, role, locked_until, handle, pinned_chirp_id, suspended_at, display_name, bio, deleted_at, avatar_key, location, website, deactivated_at, is_chirpy_red, is_private, is_verified, pending_email, last_seen_at, show_presence, follower_count, following_count, chirp_count FROM users
+WHERE id = $1
+`
+
+func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByID, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Email,
+		&i.HashedPassword,
+		&i.Role,
+		&i.LockedUntil,
+		&i.Handle,
+		&i.PinnedChirpID,
+		&i.SuspendedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.DeletedAt,
+		&i.AvatarKey,
+		&i.Location,
+		&i.Website,
+		&i.DeactivatedAt,
+		&i.IsChirpyRed,
+		&i.IsPrivate,
+		&i.IsVerified,
+		&i.PendingEmail,
+		&i.LastSeenAt,
+		&i.ShowPresence,
+		&i.FollowerCount,
+		&i.FollowingCount,
+		&i.ChirpCount,
+	)
+	return i, err
+}
+
+const listExpiredDeactivatedUsers = `-- name: ListExpiredDeactivatedUsers :many
+SELECT id FROM users
+WHERE deactivated_at < $1::timestamp
+AND deleted_at IS NULL
+LIMIT $2
+`
+
+type ListExpiredDeactivatedUsersParams struct {
+	Cutoff     time.Time
+	MaxResults int32
+}
+
+func (q *Queries) ListExpiredDeactivatedUsers(ctx context.Context, arg ListExpiredDeactivatedUsersParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, listExpiredDeactivatedUsers, arg.Cutoff, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserProfilesByHandles = `-- name: ListUserProfilesByHandles :many
+SELECT id, created_at, updated_at, email, hashed_password, role, locked_until, handle, pinned_chirp_id, suspended_at, display_name, bio, deleted_at, avatar_key, location, website, deactivated_at, is_chirpy_red, is_private, is_verified, pending_email, last_seen_at, show_presence, follower_count, following_count, chirp_count FROM users
+WHERE LOWER(users.handle) = ANY($1::text[])
+AND users.suspended_at IS NULL
+AND users.deleted_at IS NULL
+AND users.deactivated_at IS NULL
+ORDER BY users.handle
+`
+
+func (q *Queries) ListUserProfilesByHandles(ctx context.Context, handles []string) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUserProfilesByHandles, pq.Array(handles))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Email,
+			&i.HashedPassword,
+			&i.Role,
+			&i.LockedUntil,
+			&i.Handle,
+			&i.PinnedChirpID,
+			&i.SuspendedAt,
+			&i.DisplayName,
+			&i.Bio,
+			&i.DeletedAt,
+			&i.AvatarKey,
+			&i.Location,
+			&i.Website,
+			&i.DeactivatedAt,
+			&i.IsChirpyRed,
+			&i.IsPrivate,
+			&i.IsVerified,
+			&i.PendingEmail,
+			&i.LastSeenAt,
+			&i.ShowPresence,
+			&i.FollowerCount,
+			&i.FollowingCount,
+			&i.ChirpCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersByIDs = `-- name: ListUsersByIDs :many
+SELECT id, created_at, updated_at, email, hashed_password, role, locked_until, handle, pinned_chirp_id, suspended_at, display_name, bio, deleted_at, avatar_key, location, website, deactivated_at, is_chirpy_red, is_private, is_verified, pending_email, last_seen_at, show_presence, follower_count, following_count, chirp_count FROM users
+WHERE id = ANY($1::uuid[])
+`
+
+func (q *Queries) ListUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Email,
+			&i.HashedPassword,
+			&i.Role,
+			&i.LockedUntil,
+			&i.Handle,
+			&i.PinnedChirpID,
+			&i.SuspendedAt,
+			&i.DisplayName,
+			&i.Bio,
+			&i.DeletedAt,
+			&i.AvatarKey,
+			&i.Location,
+			&i.Website,
+			&i.DeactivatedAt,
+			&i.IsChirpyRed,
+			&i.IsPrivate,
+			&i.IsVerified,
+			&i.PendingEmail,
+			&i.LastSeenAt,
+			&i.ShowPresence,
+			&i.FollowerCount,
+			&i.FollowingCount,
+			&i.ChirpCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockUser = `-- name: LockUser :exec
+UPDATE users
+SET locked_until = $2, updated_at = NOW()
+WHERE id = $1
+`
+
+type LockUserParams struct {
+	ID          uuid.UUID
+	LockedUntil sql.NullTime
+}
+
+func (q *Queries) LockUser(ctx context.Context, arg LockUserParams) error {
+	_, err := q.db.ExecContext(ctx, lockUser, arg.ID, arg.LockedUntil)
+	return err
+}
+
+const reactivateUser = `-- name: ReactivateUser :exec
+UPDATE users
+SET deactivated_at = NULL, updated_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) ReactivateUser(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, reactivateUser, id)
+	return err
+}
+
+const rehashUserPassword = `-- name: RehashUserPassword :exec
+UPDATE users
+SET hashed_password = $1
+WHERE id = $2
+AND hashed_password = $3
+`
+
+type RehashUserPasswordParams struct {
+	NewHashedPassword string
+	ID                uuid.UUID
+	OldHashedPassword string
+}
+
+func (q *Queries) RehashUserPassword(ctx context.Context, arg RehashUserPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, rehashUserPassword, arg.NewHashedPassword, arg.ID, arg.OldHashedPassword)
+	return err
+}
+
+const searchUsers = `-- name: SearchUsers :many
+SELECT id, created_at, updated_at, email, hashed_password, role, locked_until, handle, pinned_chirp_id, suspended_at, display_name, bio, deleted_at, avatar_key, location, website, deactivated_at, is_chirpy_red, is_private, is_verified, pending_email, last_seen_at, show_presence, follower_count, following_count, chirp_count FROM users
+WHERE (
+    LOWER(users.handle) LIKE $1
+    OR LOWER(users.display_name) LIKE $1
+)
+AND users.deleted_at IS NULL
+AND users.deactivated_at IS NULL
+AND users.suspended_at IS NULL
+ORDER BY
+    CASE
+        WHEN LOWER(users.handle) = $2 THEN 0
+        WHEN LOWER(users.handle) LIKE $3 THEN 1
+        WHEN LOWER(users.display_name) LIKE $3 THEN 2
+        ELSE 3
+    END,
+    LOWER(users.handle)
+LIMIT $5
+OFFSET $4
+`
+
+type SearchUsersParams struct {
+	Pattern    string
+	Query      string
+	Prefix     string
+	Skip       int32
+	MaxResults int32
+}
+
+func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, searchUsers,
+		arg.Pattern,
+		arg.Query,
+		arg.Prefix,
+		arg.Skip,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Email,
+			&i.HashedPassword,
+			&i.Role,
+			&i.LockedUntil,
+			&i.Handle,
+			&i.PinnedChirpID,
+			&i.SuspendedAt,
+			&i.DisplayName,
+			&i.Bio,
+			&i.DeletedAt,
+			&i.AvatarKey,
+			&i.Location,
+			&i.Website,
+			&i.DeactivatedAt,
+			&i.IsChirpyRed,
+			&i.IsPrivate,
+			&i.IsVerified,
+			&i.PendingEmail,
+			&i.LastSeenAt,
+			&i.ShowPresence,
+			&i.FollowerCount,
+			&i.FollowingCount,
+			&i.ChirpCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setPinnedChirp = `-- name: SetPinnedChirp :one
+UPDATE users
+SET pinned_chirp_id = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING id, created_at, updated_at, email, hashed_password, role, locked_until, handle, pinned_chirp_id, suspended_at, display_name, bio, deleted_at, avatar_key, location, website, deactivated_at, is_chirpy_red, is_private, is_verified, pending_email, last_seen_at, show_presence, follower_count, following_count, chirp_count
+`
+
+type SetPinnedChirpParams struct {
+	ID            uuid.UUID
+	PinnedChirpID uuid.NullUUID
+}
+
+func (q *Queries) SetPinnedChirp(ctx context.Context, arg SetPinnedChirpParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, setPinnedChirp, arg.ID, arg.PinnedChirpID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Email,
+		&i.HashedPassword,
+		&i.Role,
+		&i.LockedUntil,
+		&i.Handle,
+		&i.PinnedChirpID,
+		&i.SuspendedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.DeletedAt,
+		&i.AvatarKey,
+		&i.Location,
+		&i.Website,
+		&i.DeactivatedAt,
+		&i.IsChirpyRed,
+		&i.IsPrivate,
+		&i.IsVerified,
+		&i.PendingEmail,
+		&i.LastSeenAt,
+		&i.ShowPresence,
+		&i.FollowerCount,
+		&i.FollowingCount,
+		&i.ChirpCount,
+	)
+	return i, err
+}
+
+const setUserAvatar = `-- name: SetUserAvatar :one
+UPDATE users
+SET avatar_key = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING id, created_at, updated_at, email, hashed_password, role, locked_until, handle, pinned_chirp_id, suspended_at, display_name, bio, deleted_at, avatar_key, location, website, deactivated_at, is_chirpy_red, is_private, is_verified, pending_email, last_seen_at, show_presence, follower_count, following_count, chirp_count
+`
+
+type SetUserAvatarParams struct {
+	ID        uuid.UUID
+	AvatarKey sql.NullString
+}
+
+func (q *Queries) SetUserAvatar(ctx context.Context, arg SetUserAvatarParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, setUserAvatar, arg.ID, arg.AvatarKey)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Email,
+		&i.HashedPassword,
+		&i.Role,
+		&i.LockedUntil,
+		&i.Handle,
+		&i.PinnedChirpID,
+		&i.SuspendedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.DeletedAt,
+		&i.AvatarKey,
+		&i.Location,
+		&i.Website,
+		&i.DeactivatedAt,
+		&i.IsChirpyRed,
+		&i.IsPrivate,
+		&i.IsVerified,
+		&i.PendingEmail,
+		&i.LastSeenAt,
+		&i.ShowPresence,
+		&i.FollowerCount,
+		&i.FollowingCount,
+		&i.ChirpCount,
+	)
+	return i, err
+}
+
+const setUserVerified = `-- name: SetUserVerified :execrows
+UPDATE users
+SET is_verified = $2, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+type SetUserVerifiedParams struct {
+	ID         uuid.UUID
+	IsVerified bool
+}
+
+func (q *Queries) SetUserVerified(ctx context.Context, arg SetUserVerifiedParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setUserVerified, arg.ID, arg.IsVerified)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setUsersLastSeen = `-- name: SetUsersLastSeen :exec
+UPDATE users
+SET last_seen_at = seen.at
+FROM (
+    SELECT UNNEST($1::uuid[]) AS id, UNNEST($2::timestamp[]) AS at
+) AS seen
+WHERE users.id = seen.id
+AND (users.last_seen_at IS NULL OR users.last_seen_at < seen.at)
+`
+
+type SetUsersLastSeenParams struct {
+	UserIds []uuid.UUID
+	SeenAt  []time.Time
+}
+
+func (q *Queries) SetUsersLastSeen(ctx context.Context, arg SetUsersLastSeenParams) error {
+	_, err := q.db.ExecContext(ctx, setUsersLastSeen, pq.Array(arg.UserIds), pq.Array(arg.SeenAt))
+	return err
+}
+
+const suspendUser = `-- name: SuspendUser :exec
+UPDATE users
+SET suspended_at = NOW(), updated_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) SuspendUser(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, suspendUser, id)
+	return err
+}
+
+const unlockUser = `-- name: UnlockUser :execrows
+UPDATE users
+SET locked_until = NULL, updated_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) UnlockUser(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, unlockUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const unpinChirp = `-- name: UnpinChirp :exec
+UPDATE users
+SET pinned_chirp_id = NULL
+WHERE pinned_chirp_id = $1
+`
+
+func (q *Queries) UnpinChirp(ctx context.Context, pinnedChirpID uuid.NullUUID) error {
+	_, err := q.db.ExecContext(ctx, unpinChirp, pinnedChirpID)
+	return err
+}
+
+const updateUser = `-- name: UpdateUser :one
+UPDATE users
+SET hashed_password = $2, pending_email = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING id, created_at, updated_at, email, hashed_password, role, locked_until, handle, pinned_chirp_id, suspended_at, display_name, bio, deleted_at, avatar_key, location, website, deactivated_at, is_chirpy_red, is_private, is_verified, pending_email, last_seen_at, show_presence, follower_count, following_count, chirp_count
+`
+
+type UpdateUserParams struct {
+	ID             uuid.UUID
+	HashedPassword string
+	PendingEmail   sql.NullString
+}
+
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUser, arg.ID, arg.HashedPassword, arg.PendingEmail)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Email,
+		&i.HashedPassword,
+		&i.Role,
+		&i.LockedUntil,
+		&i.Handle,
+		&i.PinnedChirpID,
+		&i.SuspendedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.DeletedAt,
+		&i.AvatarKey,
+		&i.Location,
+		&i.Website,
+		&i.DeactivatedAt,
+		&i.IsChirpyRed,
+		&i.IsPrivate,
+		&i.IsVerified,
+		&i.PendingEmail,
+		&i.LastSeenAt,
+		&i.ShowPresence,
+		&i.FollowerCount,
+		&i.FollowingCount,
+		&i.ChirpCount,
+	)
+	return i, err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users
+SET hashed_password = $2, updated_at = NOW()
+WHERE id = $1
+`
+
+type UpdateUserPasswordParams struct {
+	ID             uuid.UUID
+	HashedPassword string
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserPassword, arg.ID, arg.HashedPassword)
+	return err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+UPDATE users
+SET display_name = $2, bio = $3, location = $4, website = $5, is_private = $6, show_presence = $7, updated_at = NOW()
+WHERE id = $1
+RETURNING id, created_at, updated_at, email, hashed_password, role, locked_until, handle, pinned_chirp_id, suspended_at, display_name, bio, deleted_at, avatar_key, location, website, deactivated_at, is_chirpy_red, is_private, is_verified, pending_email, last_seen_at, show_presence, follower_count, following_count, chirp_count
+`
+
+type UpdateUserProfileParams struct {
+	ID           uuid.UUID
+	DisplayName  sql.NullString
+	Bio          sql.NullString
+	Location     sql.NullString
+	Website      sql.NullString
+	IsPrivate    bool
+	ShowPresence bool
+}
+
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserProfile,
+		arg.ID,
+		arg.DisplayName,
+		arg.Bio,
+		arg.Location,
+		arg.Website,
+		arg.IsPrivate,
+		arg.ShowPresence,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Email,
+		&i.HashedPassword,
+		&i.Role,
+		&i.LockedUntil,
+		&i.Handle,
+		&i.PinnedChirpID,
+		&i.SuspendedAt,
+		&i.DisplayName,
+		&i.Bio,
+		&i.DeletedAt,
+		&i.AvatarKey,
+		&i.Location,
+		&i.Website,
+		&i.DeactivatedAt,
+		&i.IsChirpyRed,
+		&i.IsPrivate,
+		&i.IsVerified,
+		&i.PendingEmail,
+		&i.LastSeenAt,
+		&i.ShowPresence,
+		&i.FollowerCount,
+		&i.FollowingCount,
+		&i.ChirpCount,
+	)
+	return i, err
+}
+
+const upgradeUserToChirpyRed = `-- name: UpgradeUserToChirpyRed :execrows
+UPDATE users
+SET is_chirpy_red = TRUE, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) UpgradeUserToChirpyRed(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, upgradeUserToChirpyRed, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,148 @@
+// Package langdetect guesses the language of short texts such as chirps.
+package langdetect
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Undetermined is the ISO 639 code for "language not identified".
+const Undetermined = "und"
+
+// Detector returns the ISO 639-1 code of the language text is written in,
+// or Undetermined.
+type Detector interface {
+	Detect(text string) string
+}
+
+// Heuristic detects languages written in a distinctive script from the
+// script alone, and Latin-script languages by counting common function
+// words. It needs no model data, at the cost of only knowing a handful of
+// languages and abstaining on texts that are too short to tell.
+type Heuristic struct{}
+
+var scripts = []struct {
+	table *unicode.RangeTable
+	lang  string
+}{
+	{unicode.Hangul, "ko"},
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Han, "zh"},
+	{unicode.Cyrillic, "ru"},
+	{unicode.Greek, "el"},
+	{unicode.Arabic, "ar"},
+	{unicode.Hebrew, "he"},
+	{unicode.Thai, "th"},
+	{unicode.Devanagari, "hi"},
+}
+
+var stopwords = map[string][]string{
+	"en": {"the", "and", "is", "are", "was", "to", "of", "in", "it", "that", "this", "for", "with", "you", "i", "my", "have", "be", "not", "on"},
+	"es": {"el", "la", "los", "las", "que", "y", "es", "en", "de", "por", "para", "con", "una", "un", "no", "mi", "pero", "muy", "esta", "del"},
+	"fr": {"le", "la", "les", "et", "est", "que", "des", "une", "un", "je", "pas", "pour", "dans", "du", "ce", "sur", "avec", "mais", "tres", "au"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ich", "ein", "eine", "zu", "mit", "von", "den", "auf", "es", "sich", "auch", "aber", "sehr", "dem"},
+	"pt": {"o", "a", "os", "as", "que", "e", "em", "de", "do", "da", "um", "uma", "para", "com", "nao", "eu", "mas", "muito", "isso", "voce"},
+	"it": {"il", "lo", "la", "gli", "che", "e", "di", "un", "una", "per", "non", "sono", "con", "mi", "ma", "molto", "questo", "della", "anche", "io"},
+}
+
+var stopwordLangs = func() map[string][]string {
+	m := make(map[string][]string)
+	for lang, words := range stopwords {
+		for _, w := range words {
+			m[w] = append(m[w], lang)
+		}
+	}
+	return m
+}()
+
+func (Heuristic) Detect(text string) string {
+	counts := make(map[string]int)
+	letters := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		for _, s := range scripts {
+			if unicode.Is(s.table, r) {
+				counts[s.lang]++
+				break
+			}
+		}
+	}
+	// Japanese mixes kana with Han characters, so any kana at all decides
+	// between the two.
+	if counts["ja"] > 0 {
+		return "ja"
+	}
+	for _, s := range scripts {
+		if counts[s.lang]*2 > letters {
+			return s.lang
+		}
+	}
+
+	scores := make(map[string]int)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		for _, lang := range stopwordLangs[foldAccents(word)] {
+			scores[lang]++
+		}
+	}
+
+	best, bestScore, tie := Undetermined, 0, false
+	for lang, score := range scores {
+		switch {
+		case score > bestScore:
+			best, bestScore, tie = lang, score, false
+		case score == bestScore:
+			tie = true
+		}
+	}
+	if bestScore < 2 || tie {
+		return Undetermined
+	}
+	return best
+}
+
+// foldAccents strips the diacritics that appear in the stopword lists, so
+// "très" and "tres" match alike.
+func foldAccents(word string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case 'á', 'à', 'â', 'ã':
+			return 'a'
+		case 'é', 'è', 'ê':
+			return 'e'
+		case 'í', 'î':
+			return 'i'
+		case 'ó', 'ô', 'õ':
+			return 'o'
+		case 'ú', 'û', 'ü':
+			return 'u'
+		case 'ç':
+			return 'c'
+		}
+		return r
+	}, word)
+}
+
+// None never identifies a language. It's used when detection is disabled.
+type None struct{}
+
+func (None) Detect(string) string {
+	return Undetermined
+}
+
+// New returns the named detector ("heuristic" or "none").
+func New(name string) (Detector, error) {
+	switch name {
+	case "heuristic":
+		return Heuristic{}, nil
+	case "none":
+		return None{}, nil
+	}
+	return nil, fmt.Errorf("unknown language detector %q", name)
+}
@@ -0,0 +1,29 @@
+package langdetect
+
+import "testing"
+
+func TestHeuristicDetect(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"I think this is the best day of my life", "en"},
+		{"No sé por qué la gente dice que es muy difícil", "es"},
+		{"Je ne sais pas pourquoi c'est très difficile pour moi", "fr"},
+		{"Ich weiß nicht, warum das so schwer ist und nicht einfacher", "de"},
+		{"今日はとても良い天気ですね", "ja"},
+		{"今天天气很好", "zh"},
+		{"오늘 날씨가 정말 좋네요", "ko"},
+		{"Сегодня очень хорошая погода", "ru"},
+		{"lol", Undetermined},
+		{"#golang @alice", Undetermined},
+		{"", Undetermined},
+	}
+
+	var d Heuristic
+	for _, tt := range tests {
+		if got := d.Detect(tt.text); got != tt.want {
+			t.Errorf("Detect(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
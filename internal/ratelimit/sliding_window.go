@@ -0,0 +1,98 @@
+// Package ratelimit provides in-memory request limiters keyed by caller.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// SlidingWindow allows up to Limit requests per key in any rolling Window.
+// It uses the sliding window counter approximation: the previous fixed
+// window's count is weighted by how much of it still overlaps the rolling
+// window, which keeps memory per key constant.
+type SlidingWindow struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	counters  map[string]*windowCounter
+	lastSweep time.Time
+}
+
+type windowCounter struct {
+	start    time.Time
+	current  int
+	previous int
+}
+
+// NewSlidingWindow returns a limiter allowing limit requests per window.
+func NewSlidingWindow(limit int, window time.Duration) *SlidingWindow {
+	return &SlidingWindow{
+		limit:    limit,
+		window:   window,
+		now:      time.Now,
+		counters: make(map[string]*windowCounter),
+	}
+}
+
+// Allow records a request for key and reports whether it is within the
+// limit. When it isn't, retryAfter estimates how long until it would be.
+func (l *SlidingWindow) Allow(key string) (allowed bool, retryAfter time.Duration) {
+	return l.AllowN(key, 1)
+}
+
+// AllowN is like Allow for n requests at once. Either all of them are
+// recorded or, if they would exceed the limit together, none is.
+func (l *SlidingWindow) AllowN(key string, n int) (allowed bool, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	c, ok := l.counters[key]
+	if !ok {
+		c = &windowCounter{start: now.Truncate(l.window)}
+		l.counters[key] = c
+	}
+	c.advance(now, l.window)
+
+	elapsed := now.Sub(c.start)
+	weight := 1 - float64(elapsed)/float64(l.window)
+	estimate := float64(c.previous)*weight + float64(c.current)
+	if estimate+float64(n) > float64(l.limit) {
+		return false, c.start.Add(l.window).Sub(now)
+	}
+
+	c.current += n
+	return true, 0
+}
+
+func (c *windowCounter) advance(now time.Time, window time.Duration) {
+	start := now.Truncate(window)
+	switch {
+	case start.Equal(c.start):
+		return
+	case start.Sub(c.start) == window:
+		c.previous = c.current
+	default:
+		c.previous = 0
+	}
+	c.current = 0
+	c.start = start
+}
+
+// sweep drops keys that have been idle for two full windows, at most once
+// per window, so the map doesn't grow without bound.
+func (l *SlidingWindow) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.window {
+		return
+	}
+	l.lastSweep = now
+	for key, c := range l.counters {
+		if now.Sub(c.start) >= 2*l.window {
+			delete(l.counters, key)
+		}
+	}
+}
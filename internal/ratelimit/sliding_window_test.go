@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSlidingWindow(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewSlidingWindow(3, time.Minute)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if ok, _ := l.Allow("a"); !ok {
+			t.Fatalf("request %d was limited, want allowed", i+1)
+		}
+	}
+	ok, retryAfter := l.Allow("a")
+	if ok {
+		t.Fatal("fourth request was allowed, want limited")
+	}
+	if retryAfter <= 0 || retryAfter > time.Minute {
+		t.Errorf("retryAfter = %v, want within one window", retryAfter)
+	}
+
+	if ok, _ := l.Allow("b"); !ok {
+		t.Error("other key was limited, want allowed")
+	}
+
+	// Halfway into the next window, half of the previous window's three
+	// requests still count, leaving room for one more.
+	now = now.Add(90 * time.Second)
+	if ok, _ := l.Allow("a"); !ok {
+		t.Error("request after window rolled was limited, want allowed")
+	}
+	if ok, _ := l.Allow("a"); ok {
+		t.Error("request exceeding weighted estimate was allowed, want limited")
+	}
+
+	// Two windows later nothing from before counts.
+	now = now.Add(2 * time.Minute)
+	for i := 0; i < 3; i++ {
+		if ok, _ := l.Allow("a"); !ok {
+			t.Fatalf("request %d after idle period was limited, want allowed", i+1)
+		}
+	}
+}
+
+func TestSlidingWindowAllowN(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewSlidingWindow(5, time.Minute)
+	l.now = func() time.Time { return now }
+
+	if ok, _ := l.AllowN("a", 3); !ok {
+		t.Fatal("first batch of 3 was limited, want allowed")
+	}
+	if ok, _ := l.AllowN("a", 3); ok {
+		t.Fatal("batch exceeding the remaining budget was allowed, want limited")
+	}
+	// The rejected batch mustn't have used up any of the budget.
+	if ok, _ := l.AllowN("a", 2); !ok {
+		t.Error("batch fitting the remaining budget was limited, want allowed")
+	}
+	if ok, _ := l.Allow("a"); ok {
+		t.Error("request over the limit was allowed, want limited")
+	}
+}
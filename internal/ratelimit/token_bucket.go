@@ -0,0 +1,105 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// TokenBucket allows bursts of up to Limit requests per key, refilling at
+// Limit tokens per Period, so a caller's sustained rate is capped without
+// penalizing occasional bursts.
+type TokenBucket struct {
+	limit  int
+	period time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// Result is the outcome of TokenBucket.Take.
+type Result struct {
+	Allowed bool
+	Limit   int
+	// Remaining is how many more requests the key could make right now.
+	Remaining int
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is how long until the next request would be allowed, or
+	// zero if it would be now.
+	RetryAfter time.Duration
+}
+
+// NewTokenBucket returns a limiter allowing limit requests per period.
+func NewTokenBucket(limit int, period time.Duration) *TokenBucket {
+	return &TokenBucket{
+		limit:   limit,
+		period:  period,
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Take spends a token from key's bucket if there is one.
+func (l *TokenBucket) Take(key string) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.limit), updated: now}
+		l.buckets[key] = b
+	}
+	b.refill(now, l.rate(), float64(l.limit))
+
+	res := Result{Limit: l.limit}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = l.durationFor(1 - b.tokens)
+	}
+	res.Remaining = int(math.Floor(b.tokens))
+	res.Reset = l.durationFor(float64(l.limit) - b.tokens)
+	return res
+}
+
+// rate is the refill rate in tokens per nanosecond.
+func (l *TokenBucket) rate() float64 {
+	return float64(l.limit) / float64(l.period)
+}
+
+// durationFor is how long refilling the given number of tokens takes.
+func (l *TokenBucket) durationFor(tokens float64) time.Duration {
+	return time.Duration(math.Ceil(tokens / l.rate()))
+}
+
+func (b *bucket) refill(now time.Time, rate, capacity float64) {
+	b.tokens = math.Min(capacity, b.tokens+float64(now.Sub(b.updated))*rate)
+	b.updated = now
+}
+
+// sweep drops buckets that have refilled completely, at most once per
+// period, so the map doesn't grow without bound. A dropped bucket is
+// indistinguishable from a new one.
+func (l *TokenBucket) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.period {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) >= l.period {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewTokenBucket(3, time.Minute)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		res := l.Take("a")
+		if !res.Allowed {
+			t.Fatalf("request %d was limited, want allowed", i+1)
+		}
+		if want := 2 - i; res.Remaining != want {
+			t.Errorf("request %d: Remaining = %d, want %d", i+1, res.Remaining, want)
+		}
+	}
+	res := l.Take("a")
+	if res.Allowed {
+		t.Fatal("fourth request was allowed, want limited")
+	}
+	if res.RetryAfter != 20*time.Second {
+		t.Errorf("RetryAfter = %v, want 20s", res.RetryAfter)
+	}
+	if res.Reset != time.Minute {
+		t.Errorf("Reset = %v, want 1m", res.Reset)
+	}
+
+	if res := l.Take("b"); !res.Allowed {
+		t.Error("other key was limited, want allowed")
+	}
+
+	// One token refills every 20 seconds.
+	now = now.Add(20 * time.Second)
+	if res := l.Take("a"); !res.Allowed {
+		t.Error("request after refill was limited, want allowed")
+	}
+	if res := l.Take("a"); res.Allowed {
+		t.Error("request beyond refilled tokens was allowed, want limited")
+	}
+
+	// A long idle period refills the bucket only up to the limit.
+	now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		if res := l.Take("a"); !res.Allowed {
+			t.Fatalf("request %d after idle period was limited, want allowed", i+1)
+		}
+	}
+	if res := l.Take("a"); res.Allowed {
+		t.Error("request beyond limit after idle period was allowed, want limited")
+	}
+}
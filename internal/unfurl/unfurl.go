@@ -0,0 +1,178 @@
+// Package unfurl fetches the OpenGraph metadata of web pages linked from
+// chirps, so clients can render a preview card.
+package unfurl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// Preview is the metadata of a linked page. Any field may be empty.
+type Preview struct {
+	URL         string
+	Title       string
+	Description string
+	ImageURL    string
+}
+
+var (
+	ErrNotHTML        = errors.New("unfurl: not an HTML page")
+	errPrivateAddress = errors.New("unfurl: refusing to connect to a private address")
+)
+
+// Fetcher downloads pages with a bounded time and size budget. It refuses to
+// connect to loopback, private and link-local addresses so chirp authors
+// can't use it to probe the server's internal network.
+type Fetcher struct {
+	client   *http.Client
+	maxBytes int64
+}
+
+func NewFetcher(timeout time.Duration, maxBytes int64) *Fetcher {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !ip.IsGlobalUnicast() || ip.IsPrivate() {
+				return errPrivateAddress
+			}
+			return nil
+		},
+	}
+	transport := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: timeout,
+	}
+	return &Fetcher{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 5 {
+					return errors.New("unfurl: too many redirects")
+				}
+				return nil
+			},
+		},
+		maxBytes: maxBytes,
+	}
+}
+
+// Fetch downloads rawURL and extracts its preview metadata.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (Preview, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return Preview{}, err
+	}
+	req.Header.Set("Accept", "text/html")
+	req.Header.Set("User-Agent", "Chirpy-LinkPreview/1.0")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return Preview{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Preview{}, fmt.Errorf("unfurl: unexpected status %d", resp.StatusCode)
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" {
+		return Preview{}, ErrNotHTML
+	}
+
+	preview, err := Parse(io.LimitReader(resp.Body, f.maxBytes), resp.Request.URL)
+	if err != nil {
+		return Preview{}, err
+	}
+	preview.URL = rawURL
+	return preview, nil
+}
+
+// Parse extracts OpenGraph metadata from an HTML document, falling back to
+// <title> and the description meta tag. Relative image URLs are resolved
+// against base.
+func Parse(r io.Reader, base *url.URL) (Preview, error) {
+	var preview Preview
+	var title, description string
+
+	z := html.NewTokenizer(r)
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			if errors.Is(z.Err(), io.EOF) {
+				return finish(preview, title, description, base), nil
+			}
+			return Preview{}, z.Err()
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			switch tok.Data {
+			case "title":
+				if title == "" && z.Next() == html.TextToken {
+					title = strings.TrimSpace(string(z.Text()))
+				}
+			case "meta":
+				key, content := metaAttrs(tok)
+				switch key {
+				case "og:title":
+					preview.Title = content
+				case "og:description":
+					preview.Description = content
+				case "og:image":
+					preview.ImageURL = content
+				case "description":
+					description = content
+				}
+			case "body":
+				// Metadata lives in <head>; don't read the rest of the page.
+				return finish(preview, title, description, base), nil
+			}
+		}
+	}
+}
+
+func metaAttrs(tok html.Token) (key, content string) {
+	for _, attr := range tok.Attr {
+		switch attr.Key {
+		case "property", "name":
+			key = strings.ToLower(attr.Val)
+		case "content":
+			content = strings.TrimSpace(attr.Val)
+		}
+	}
+	return key, content
+}
+
+func finish(preview Preview, title, description string, base *url.URL) Preview {
+	if preview.Title == "" {
+		preview.Title = title
+	}
+	if preview.Description == "" {
+		preview.Description = description
+	}
+	if preview.ImageURL != "" && base != nil {
+		image, err := base.Parse(preview.ImageURL)
+		if err != nil || (image.Scheme != "http" && image.Scheme != "https") {
+			preview.ImageURL = ""
+		} else {
+			preview.ImageURL = image.String()
+		}
+	}
+	return preview
+}
@@ -0,0 +1,54 @@
+package unfurl
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	base, _ := url.Parse("https://example.com/posts/1")
+
+	tests := []struct {
+		name string
+		doc  string
+		want Preview
+	}{
+		{
+			name: "opengraph",
+			doc: `<html><head><title>Fallback</title>
+				<meta property="og:title" content="Hello">
+				<meta property="og:description" content=" A post ">
+				<meta property="og:image" content="/img/cover.png">
+				</head><body></body></html>`,
+			want: Preview{Title: "Hello", Description: "A post", ImageURL: "https://example.com/img/cover.png"},
+		},
+		{
+			name: "fallbacks",
+			doc:  `<html><head><title> Plain page </title><meta name="description" content="About"></head></html>`,
+			want: Preview{Title: "Plain page", Description: "About"},
+		},
+		{
+			name: "stops at body",
+			doc:  `<html><head></head><body><meta property="og:title" content="Ignored"></body></html>`,
+			want: Preview{},
+		},
+		{
+			name: "non-http image dropped",
+			doc:  `<meta property="og:image" content="javascript:alert(1)">`,
+			want: Preview{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(strings.NewReader(tt.doc), base)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+// Package validate checks request structs against rules declared in their
+// `validate` struct tags, such as `validate:"required,max=140"`.
+//
+// The rules are:
+//
+//   - required: strings must not be blank, slices and maps must not be
+//     empty, pointers must not be nil, and other values must not be zero.
+//   - min=N, max=N: bounds on the length of strings (in characters), slices
+//     and maps, or on the value of numbers.
+//   - email: a bare address such as user@example.com.
+//   - url: an absolute http or https URL.
+//
+// Strings are checked without their surrounding whitespace.
+// Rules other than required pass zero values, so optional fields only need
+// to be valid when given. Rules on a pointer apply to what it points to.
+package validate
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FieldError describes why one field of a request is invalid. Field is the
+// field's JSON name; fields of nested structs are joined with dots.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Struct checks the fields of v, a struct or pointer to one, and returns
+// the first failed rule of each invalid field, or nil if all are valid. It
+// panics on an unknown rule, which is a mistake in the tag.
+func Struct(v any) []FieldError {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	return checkStruct(rv, "")
+}
+
+func checkStruct(v reflect.Value, prefix string) []FieldError {
+	var errs []FieldError
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		// Embedded structs' fields are encoded as if they were v's own.
+		if f.Anonymous && f.Tag.Get("json") == "" {
+			if inner := reflect.Indirect(fv); inner.Kind() == reflect.Struct {
+				errs = append(errs, checkStruct(inner, prefix)...)
+				continue
+			}
+		}
+
+		name := jsonName(f)
+		if name == "-" {
+			continue
+		}
+		name = prefix + name
+
+		if tag := f.Tag.Get("validate"); tag != "" {
+			if msg := checkRules(fv, tag); msg != "" {
+				errs = append(errs, FieldError{Field: name, Message: msg})
+				continue
+			}
+		}
+		if inner := reflect.Indirect(fv); inner.Kind() == reflect.Struct {
+			errs = append(errs, checkStruct(inner, name+".")...)
+		}
+	}
+	return errs
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// checkRules returns the message for the first rule in tag that v fails,
+// or "" if it passes them all.
+func checkRules(v reflect.Value, tag string) string {
+	for _, rule := range strings.Split(tag, ",") {
+		name, arg, _ := strings.Cut(rule, "=")
+		if name == "required" {
+			if isEmpty(v) {
+				return "is required"
+			}
+			continue
+		}
+
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return ""
+			}
+			v = v.Elem()
+		}
+		if isEmpty(v) {
+			continue
+		}
+
+		var msg string
+		switch name {
+		case "min", "max":
+			msg = checkBound(v, name, arg)
+		case "email":
+			s := strings.TrimSpace(v.String())
+			addr, err := mail.ParseAddress(s)
+			if err != nil || addr.Address != s {
+				msg = "must be a valid email address"
+			}
+		case "url":
+			u, err := url.Parse(strings.TrimSpace(v.String()))
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				msg = "must be an http or https URL"
+			}
+		default:
+			panic(fmt.Sprintf("validate: unknown rule %q", rule))
+		}
+		if msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(v.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	default:
+		return v.IsZero()
+	}
+}
+
+func checkBound(v reflect.Value, rule, arg string) string {
+	bound, err := strconv.Atoi(arg)
+	if err != nil {
+		panic(fmt.Sprintf("validate: bad bound in %s=%s", rule, arg))
+	}
+
+	var n int64
+	unit := ""
+	switch v.Kind() {
+	case reflect.String:
+		n, unit = int64(utf8.RuneCountInString(strings.TrimSpace(v.String()))), " characters"
+	case reflect.Slice, reflect.Map:
+		n, unit = int64(v.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = v.Int()
+	default:
+		panic(fmt.Sprintf("validate: %s doesn't apply to %s", rule, v.Kind()))
+	}
+
+	if rule == "min" && n < int64(bound) {
+		return fmt.Sprintf("must be at least %d%s", bound, unit)
+	}
+	if rule == "max" && n > int64(bound) {
+		return fmt.Sprintf("must be at most %d%s", bound, unit)
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package validate
+
+import (
+	"reflect"
+	"testing"
+)
+
+type address struct {
+	City string `json:"city" validate:"required"`
+}
+
+type request struct {
+	Email    string   `json:"email" validate:"required,email"`
+	Name     string   `json:"name" validate:"max=5"`
+	Bio      *string  `json:"bio" validate:"max=3"`
+	Website  string   `json:"website" validate:"url"`
+	Tags     []string `json:"tags" validate:"required,max=2"`
+	Age      int      `json:"age" validate:"min=13"`
+	Address  *address `json:"address"`
+	Internal string   `json:"-" validate:"required"`
+}
+
+func TestStruct(t *testing.T) {
+	long := "long bio"
+	short := " ab "
+
+	tests := []struct {
+		name string
+		req  request
+		want []FieldError
+	}{
+		{
+			name: "valid",
+			req: request{
+				Email:    "user@example.com",
+				Name:     " Ann ",
+				Bio:      &short,
+				Website:  "https://example.com",
+				Tags:     []string{"a"},
+				Address:  &address{City: "Oslo"},
+				Internal: "x",
+			},
+		},
+		{
+			name: "missing required fields",
+			req:  request{Email: "  ", Internal: "x"},
+			want: []FieldError{
+				{"email", "is required"},
+				{"tags", "is required"},
+			},
+		},
+		{
+			name: "invalid formats and lengths",
+			req: request{
+				Email:    "Ann <ann@example.com>",
+				Name:     "Annabel",
+				Bio:      &long,
+				Website:  "ftp://example.com",
+				Tags:     []string{"a", "b", "c"},
+				Age:      12,
+				Address:  &address{},
+				Internal: "x",
+			},
+			want: []FieldError{
+				{"email", "must be a valid email address"},
+				{"name", "must be at most 5 characters"},
+				{"bio", "must be at most 3 characters"},
+				{"website", "must be an http or https URL"},
+				{"tags", "must be at most 2 items"},
+				{"age", "must be at least 13"},
+				{"address.city", "is required"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Struct(&tt.req)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Struct() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+const jsonAPIContentType = "application/vnd.api+json"
+
+var jsonAPIEncoder = responseEncoder{jsonAPIContentType, marshalJSONAPI}
+
+// jsonAPIIdentifier is a JSON:API resource identifier object.
+type jsonAPIIdentifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// jsonAPIResource is implemented by response types that are JSON:API
+// resources. Their attributes are their plain JSON fields other than "id".
+type jsonAPIResource interface {
+	jsonAPIIdentifier() jsonAPIIdentifier
+}
+
+// jsonAPIRelationship links a resource to another. The fields named by
+// replaces are dropped from the attributes, which would otherwise repeat
+// the link.
+type jsonAPIRelationship struct {
+	name     string
+	replaces []string
+	// data is nil for an empty to-one relationship.
+	data *jsonAPIIdentifier
+	// included, if set, is the full related resource, sent in the
+	// document's "included" member.
+	included jsonAPIResource
+}
+
+// jsonAPIRelated is implemented by resources with relationships.
+type jsonAPIRelated interface {
+	jsonAPIRelationships() []jsonAPIRelationship
+}
+
+type jsonAPIDocument struct {
+	Data     any                     `json:"data,omitempty"`
+	Errors   []jsonAPIError          `json:"errors,omitempty"`
+	Included []jsonAPIResourceObject `json:"included,omitempty"`
+	Meta     any                     `json:"meta,omitempty"`
+}
+
+type jsonAPIResourceObject struct {
+	jsonAPIIdentifier
+	Attributes    map[string]json.RawMessage         `json:"attributes"`
+	Relationships map[string]jsonAPIRelationshipData `json:"relationships,omitempty"`
+}
+
+type jsonAPIRelationshipData struct {
+	Data *jsonAPIIdentifier `json:"data"`
+}
+
+type jsonAPIError struct {
+	Code   string                     `json:"code"`
+	Detail string                     `json:"detail"`
+	Meta   map[string]json.RawMessage `json:"meta,omitempty"`
+}
+
+// jsonAPIErrorBody is implemented by errorReturnVals, including when it is
+// embedded in an error body with extra fields.
+type jsonAPIErrorBody interface {
+	jsonAPIError() jsonAPIError
+}
+
+func (e errorReturnVals) jsonAPIError() jsonAPIError {
+	return jsonAPIError{Code: e.Code, Detail: e.Error}
+}
+
+// marshalJSONAPI encodes payload as a JSON:API document:
+//   - a jsonAPIResource, or a slice of them, becomes the primary data;
+//   - a struct with one field holding a slice of resources, such as a page
+//     of chirps, becomes that collection with the other fields (the next
+//     cursor) as meta;
+//   - an error body becomes an errors document, with any fields besides
+//     the message and code as the error's meta;
+//   - anything else is sent whole as meta, since it isn't a resource.
+func marshalJSONAPI(payload any) ([]byte, error) {
+	var doc jsonAPIDocument
+	b := &jsonAPIBuilder{seen: make(map[jsonAPIIdentifier]bool)}
+
+	switch p := payload.(type) {
+	case jsonAPIErrorBody:
+		e := p.jsonAPIError()
+		if err := unmarshalFields(p, &e.Meta); err != nil {
+			return nil, err
+		}
+		delete(e.Meta, "error")
+		delete(e.Meta, "code")
+		if len(e.Meta) == 0 {
+			e.Meta = nil
+		}
+		doc.Errors = []jsonAPIError{e}
+	case jsonAPIResource:
+		obj, err := b.resourceObject(p)
+		if err != nil {
+			return nil, err
+		}
+		doc.Data = obj
+	default:
+		if data, ok, err := b.collection(reflect.ValueOf(payload)); ok || err != nil {
+			if err != nil {
+				return nil, err
+			}
+			doc.Data = data
+		} else if data, meta, ok, err := b.page(reflect.ValueOf(payload)); ok || err != nil {
+			if err != nil {
+				return nil, err
+			}
+			doc.Data, doc.Meta = data, meta
+		} else {
+			doc.Meta = payload
+		}
+	}
+	doc.Included = b.included
+	return json.Marshal(doc)
+}
+
+// jsonAPIBuilder accumulates the included resources of a document, each
+// once, leaving out those already in the primary data.
+type jsonAPIBuilder struct {
+	seen     map[jsonAPIIdentifier]bool
+	included []jsonAPIResourceObject
+	// pending are related resources to include once the primary data is
+	// known.
+	pending []jsonAPIResource
+}
+
+func (b *jsonAPIBuilder) resourceObject(res jsonAPIResource) (jsonAPIResourceObject, error) {
+	obj, err := b.object(res)
+	if err != nil {
+		return jsonAPIResourceObject{}, err
+	}
+	b.seen[obj.jsonAPIIdentifier] = true
+	return obj, b.flushIncluded()
+}
+
+// collection converts v if it is a slice of resources.
+func (b *jsonAPIBuilder) collection(v reflect.Value) ([]jsonAPIResourceObject, bool, error) {
+	if v.Kind() != reflect.Slice || !v.Type().Elem().Implements(reflect.TypeFor[jsonAPIResource]()) {
+		return nil, false, nil
+	}
+	objs := make([]jsonAPIResourceObject, 0, v.Len())
+	for i := range v.Len() {
+		obj, err := b.object(v.Index(i).Interface().(jsonAPIResource))
+		if err != nil {
+			return nil, true, err
+		}
+		b.seen[obj.jsonAPIIdentifier] = true
+		objs = append(objs, obj)
+	}
+	return objs, true, b.flushIncluded()
+}
+
+// page converts v if it is a struct with exactly one field that is a slice
+// of resources, returning the other fields, by their JSON names, as meta.
+func (b *jsonAPIBuilder) page(v reflect.Value) ([]jsonAPIResourceObject, map[string]json.RawMessage, bool, error) {
+	if v.Kind() != reflect.Struct {
+		return nil, nil, false, nil
+	}
+	dataField := -1
+	for i := range v.NumField() {
+		f := v.Type().Field(i)
+		if f.Type.Kind() == reflect.Slice && f.Type.Elem().Implements(reflect.TypeFor[jsonAPIResource]()) {
+			if dataField >= 0 {
+				return nil, nil, false, nil
+			}
+			dataField = i
+		}
+	}
+	if dataField < 0 {
+		return nil, nil, false, nil
+	}
+
+	data, _, err := b.collection(v.Field(dataField))
+	if err != nil {
+		return nil, nil, true, err
+	}
+	var meta map[string]json.RawMessage
+	if err := unmarshalFields(v.Interface(), &meta); err != nil {
+		return nil, nil, true, err
+	}
+	dataName := v.Type().Field(dataField).Name
+	if tag := v.Type().Field(dataField).Tag.Get("json"); tag != "" {
+		dataName, _, _ = strings.Cut(tag, ",")
+	}
+	delete(meta, dataName)
+	if len(meta) == 0 {
+		meta = nil
+	}
+	return data, meta, true, nil
+}
+
+// object builds res's resource object, queueing its related resources for
+// inclusion.
+func (b *jsonAPIBuilder) object(res jsonAPIResource) (jsonAPIResourceObject, error) {
+	obj := jsonAPIResourceObject{jsonAPIIdentifier: res.jsonAPIIdentifier()}
+	if err := unmarshalFields(res, &obj.Attributes); err != nil {
+		return jsonAPIResourceObject{}, err
+	}
+	delete(obj.Attributes, "id")
+
+	if related, ok := res.(jsonAPIRelated); ok {
+		obj.Relationships = make(map[string]jsonAPIRelationshipData)
+		for _, rel := range related.jsonAPIRelationships() {
+			for _, name := range rel.replaces {
+				delete(obj.Attributes, name)
+			}
+			obj.Relationships[rel.name] = jsonAPIRelationshipData{Data: rel.data}
+			if rel.included != nil {
+				b.pending = append(b.pending, rel.included)
+			}
+		}
+	}
+	return obj, nil
+}
+
+// flushIncluded adds the queued related resources to the included ones,
+// along with what they relate to in turn.
+func (b *jsonAPIBuilder) flushIncluded() error {
+	for len(b.pending) > 0 {
+		res := b.pending[0]
+		b.pending = b.pending[1:]
+		if b.seen[res.jsonAPIIdentifier()] {
+			continue
+		}
+		obj, err := b.object(res)
+		if err != nil {
+			return err
+		}
+		b.seen[obj.jsonAPIIdentifier] = true
+		b.included = append(b.included, obj)
+	}
+	return nil
+}
+
+// unmarshalFields decodes the plain JSON encoding of v into a map of its
+// fields.
+func unmarshalFields(v any, fields *map[string]json.RawMessage) error {
+	dat, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(dat, fields)
+}
+
+func (c Chirp) jsonAPIIdentifier() jsonAPIIdentifier {
+	return jsonAPIIdentifier{Type: "chirps", ID: c.ID.String()}
+}
+
+func (c Chirp) jsonAPIRelationships() []jsonAPIRelationship {
+	author := jsonAPIRelationship{
+		name:     "author",
+		replaces: []string{"user_id", "author"},
+		data:     &jsonAPIIdentifier{Type: "users", ID: c.UserID.String()},
+	}
+	if c.Author != nil {
+		author.included = *c.Author
+	}
+	inReplyTo := jsonAPIRelationship{name: "in_reply_to", replaces: []string{"in_reply_to_id"}}
+	if c.InReplyToID != nil {
+		inReplyTo.data = &jsonAPIIdentifier{Type: "chirps", ID: c.InReplyToID.String()}
+	}
+	return []jsonAPIRelationship{author, inReplyTo}
+}
+
+func (p Profile) jsonAPIIdentifier() jsonAPIIdentifier {
+	return jsonAPIIdentifier{Type: "users", ID: p.ID.String()}
+}
+
+func (p Profile) jsonAPIRelationships() []jsonAPIRelationship {
+	pinned := jsonAPIRelationship{name: "pinned_chirp", replaces: []string{"pinned_chirp_id"}}
+	if p.PinnedChirpID != nil {
+		pinned.data = &jsonAPIIdentifier{Type: "chirps", ID: p.PinnedChirpID.String()}
+	}
+	return []jsonAPIRelationship{pinned}
+}
+
+func (u User) jsonAPIIdentifier() jsonAPIIdentifier {
+	return jsonAPIIdentifier{Type: "users", ID: u.ID.String()}
+}
+
+func (u User) jsonAPIRelationships() []jsonAPIRelationship {
+	pinned := jsonAPIRelationship{name: "pinned_chirp", replaces: []string{"pinned_chirp_id"}}
+	if u.PinnedChirpID != nil {
+		pinned.data = &jsonAPIIdentifier{Type: "chirps", ID: u.PinnedChirpID.String()}
+	}
+	return []jsonAPIRelationship{pinned}
+}
+
+func (s UserSummary) jsonAPIIdentifier() jsonAPIIdentifier {
+	return jsonAPIIdentifier{Type: "users", ID: s.ID.String()}
+}
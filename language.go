@@ -0,0 +1,24 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// parseLanguage validates a ?lang= filter: a two or three letter ISO 639
+// code, or "und" for chirps whose language wasn't detected. An empty value
+// means no filter.
+func parseLanguage(v string) (sql.NullString, error) {
+	if v == "" {
+		return sql.NullString{}, nil
+	}
+	if len(v) < 2 || len(v) > 3 {
+		return sql.NullString{}, errors.New("lang must be an ISO 639 language code")
+	}
+	for _, c := range v {
+		if c < 'a' || c > 'z' {
+			return sql.NullString{}, errors.New("lang must be an ISO 639 language code")
+		}
+	}
+	return sql.NullString{String: v, Valid: true}, nil
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/unfurl"
+)
+
+const (
+	defaultLinkPreviewTimeout = 5 * time.Second
+	linkPreviewMaxBytes       = 512 << 10
+	linkPreviewWorkers        = 4
+	linkPreviewQueueSize      = 256
+)
+
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"]+`)
+
+// LinkPreview is the OpenGraph metadata of the first link in a chirp.
+type LinkPreview struct {
+	URL         string `json:"url"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	ImageURL    string `json:"image_url,omitempty"`
+}
+
+// linkPreviewer fetches link previews in the background so that posting a
+// chirp never waits on a third-party site.
+type linkPreviewer struct {
+	fetcher *unfurl.Fetcher
+	db      *database.Queries
+	queue   chan database.Chirp
+}
+
+func newLinkPreviewer(db *database.Queries, timeout time.Duration) *linkPreviewer {
+	return &linkPreviewer{
+		fetcher: unfurl.NewFetcher(timeout, linkPreviewMaxBytes),
+		db:      db,
+		queue:   make(chan database.Chirp, linkPreviewQueueSize),
+	}
+}
+
+// run processes queued chirps with a fixed number of workers until ctx is
+// cancelled, and returns once every worker has stopped.
+func (p *linkPreviewer) run(ctx context.Context) {
+	var wg sync.WaitGroup
+	wg.Add(linkPreviewWorkers)
+	for i := 0; i < linkPreviewWorkers; i++ {
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case chirp := <-p.queue:
+					p.preview(ctx, chirp)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// enqueue schedules a preview for the chirp's first link, if it has one. It
+// never blocks; when the queue is full the chirp simply gets no preview.
+func (p *linkPreviewer) enqueue(chirp database.Chirp) {
+	if p == nil || !urlPattern.MatchString(chirp.Body) {
+		return
+	}
+	select {
+	case p.queue <- chirp:
+	default:
+		log.Printf("Link preview queue full, skipping chirp %s", chirp.ID)
+	}
+}
+
+func (p *linkPreviewer) preview(ctx context.Context, chirp database.Chirp) {
+	url := urlPattern.FindString(chirp.Body)
+
+	preview, err := p.fetcher.Fetch(ctx, url)
+	if err != nil {
+		log.Printf("Error fetching link preview for %s: %s", url, err)
+		return
+	}
+
+	// The preview is only stored if the chirp hasn't been edited since, so a
+	// slow fetch can't attach a stale link to the new body.
+	err = p.db.SaveLinkPreview(ctx, database.SaveLinkPreviewParams{
+		Url:            url,
+		Title:          preview.Title,
+		Description:    preview.Description,
+		ImageUrl:       preview.ImageURL,
+		ChirpID:        chirp.ID,
+		ChirpUpdatedAt: chirp.UpdatedAt,
+	})
+	if err != nil {
+		log.Printf("Error saving link preview: %s", err)
+	}
+}
+
+// loadLinkPreviews fills in the link previews of chirps in place.
+func (cfg *apiConfig) loadLinkPreviews(ctx context.Context, chirps []Chirp) error {
+	if len(chirps) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(chirps))
+	for _, chirp := range chirps {
+		ids = append(ids, chirp.ID)
+	}
+
+	rows, err := cfg.dbQueries.ListLinkPreviews(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	previews := make(map[uuid.UUID]*LinkPreview, len(rows))
+	for _, row := range rows {
+		previews[row.ChirpID] = &LinkPreview{
+			URL:         row.Url,
+			Title:       row.Title,
+			Description: row.Description,
+			ImageURL:    row.ImageUrl,
+		}
+	}
+	for i := range chirps {
+		chirps[i].LinkPreview = previews[chirps[i].ID]
+	}
+	return nil
+}
@@ -1,41 +1,90 @@
 package main
 
 import (
+	"context"
 	"database/sql"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"sync"
 	"sync/atomic"
+	"syscall"
 	"time"
 	"unicode"
 
 	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/auth"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/captcha"
 	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/langdetect"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/mailer"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/storage"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 )
 
 type parameters struct {
-	Body string `body:"age"`
+	Body string `json:"body" validate:"required"`
 }
 
+// errorReturnVals is the body of every error response. Error is a message for
+// people; Code is a stable identifier for clients to branch on.
 type errorReturnVals struct {
 	Error string `json:"error"`
+	Code  string `json:"code"`
 }
 
 type valiedReturnVals struct {
 	Valid bool `json:"valid"`
 }
 
+const (
+	roleUser  = "user"
+	roleAdmin = "admin"
+)
+
+// defaultShutdownTimeout is how long the server waits for in-flight requests
+// on SIGTERM, a little under the 30s grace period container runtimes give.
+const defaultShutdownTimeout = 25 * time.Second
+
 type User struct {
-	ID        uuid.UUID `json:"id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Email     string    `json:"email"`
+	ID            uuid.UUID  `json:"id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	Email         string     `json:"email"`
+	Handle        string     `json:"handle"`
+	Role          string     `json:"role"`
+	PinnedChirpID *uuid.UUID `json:"pinned_chirp_id"`
+	AvatarURL     *string    `json:"avatar_url"`
+	IsChirpyRed   bool       `json:"is_chirpy_red"`
+	ShowPresence  bool       `json:"show_presence"`
+	// PendingEmail awaits confirmation before it replaces Email.
+	PendingEmail *string `json:"pending_email,omitempty"`
+}
+
+func (cfg *apiConfig) userFromDB(user database.User) User {
+	u := User{
+		ID:           user.ID,
+		CreatedAt:    user.CreatedAt,
+		UpdatedAt:    user.UpdatedAt,
+		Email:        user.Email,
+		Handle:       user.Handle,
+		Role:         user.Role,
+		IsChirpyRed:  user.IsChirpyRed,
+		ShowPresence: user.ShowPresence,
+	}
+	if user.PinnedChirpID.Valid {
+		u.PinnedChirpID = &user.PinnedChirpID.UUID
+	}
+	if user.PendingEmail.Valid {
+		u.PendingEmail = &user.PendingEmail.String
+	}
+	u.AvatarURL = cfg.avatarURL(user)
+	return u
 }
 
 type cleanedReturnVals struct {
@@ -47,12 +96,30 @@ type apiConfig struct {
 	dbQueries      *database.Queries
 	platform       string
 	db             *sql.DB
-}
-
-func readinessHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("OK"))
+	tokenSigner    auth.TokenSigner
+	accessTokenTTL time.Duration
+	rememberMeTTL  time.Duration
+	mailer         mailer.Mailer
+	lockout        lockoutPolicy
+	passwordPolicy auth.PasswordPolicy
+	passwordHasher auth.PasswordHasher
+	captcha        captcha.Verifier
+	restoreWindow  time.Duration
+	mediaStore     storage.Store
+	exportStore    storage.Store
+	mediaMaxBytes  int64
+	linkPreviews   *linkPreviewer
+	tasks          *backgroundTasks
+	maxChirpLength int
+	views          *viewCounter
+	presence       *presenceTracker
+	publicBaseURL  string
+	languages      langdetect.Detector
+	polkaKey       string
+
+	deactivationRetention time.Duration
+	redPerks              redPerks
+	maintenance           *maintenanceMode
 }
 
 func (cfg *apiConfig) getMetricsHandler(w http.ResponseWriter, r *http.Request) {
@@ -81,50 +148,23 @@ func (cfg *apiConfig) middlewareMetricsInc(next http.Handler) http.Handler {
 	})
 }
 
-func chirpHandler(w http.ResponseWriter, r *http.Request) {
-	decoder := json.NewDecoder(r.Body)
+func (cfg *apiConfig) chirpHandler(w http.ResponseWriter, r *http.Request) {
 	params := parameters{}
-	err := decoder.Decode(&params)
-
-	if err != nil {
-		log.Printf("Error decoding parameters: %s", err)
-		w.WriteHeader(500)
+	if !decodeParameters(w, r, &params) {
 		return
 	}
 
-	if len(params.Body) > 140 {
-
-		respBody := errorReturnVals{
-			Error: "Chirp is too long",
-		}
-		dat, err := json.Marshal(respBody)
-		if err != nil {
-			log.Printf("Error marshalling JSON: %s", err)
-			w.WriteHeader(500)
-			return
-		}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(400)
-		w.Write(dat)
+	if chirpLength(params.Body) > cfg.maxChirpLength {
+		respondWithError(w, http.StatusBadRequest, "Chirp is too long")
 		return
 	}
 
 	//check if params contain forbidden words
 	processedWords, _ := processWords(params.Body)
 
-	cleanedBody := cleanedReturnVals{
+	respondWithJSON(w, http.StatusOK, cleanedReturnVals{
 		CleanedBody: processedWords,
-	}
-	dat, err := json.Marshal(cleanedBody)
-	if err != nil {
-		log.Printf("Error marshalling JSON: %s", err)
-		w.WriteHeader(500)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(200)
-	w.Write(dat)
+	})
 }
 
 func hasPunctuation(s string) bool {
@@ -161,73 +201,493 @@ func processWords(input string) (string, error) {
 	return input, nil
 }
 
-func createUser(db *sql.DB) (db *sql.DB, username, password string) error {
-    query := `INSERT INTO users (username, password) VALUES ($1, $2)`
-    _, err := db.Exec(query, username, password)
-    return err
-}
-
 func (cfg *apiConfig) createUserHandler(w http.ResponseWriter, r *http.Request) {
-	decoder := json.NewDecoder(r.Body)
-	params := parameters{}
-	err := decoder.Decode(&params)
+	type userParameters struct {
+		Email        string `json:"email" validate:"required,email"`
+		Handle       string `json:"handle" validate:"required"`
+		Password     string `json:"password" validate:"required"`
+		CaptchaToken string `json:"captcha_token"`
+	}
 
+	params := userParameters{}
+	if !decodeParameters(w, r, &params) {
+		return
+	}
+
+	err := validateHandle(params.Handle)
 	if err != nil {
-		log.Printf("Error decoding parameters: %s", err)
-		w.WriteHeader(500)
+		respondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	user, err := cfg.db.CreateUser(r.Context(), params.Email)
+	if !cfg.checkPasswordPolicy(w, params.Password) {
+		return
+	}
 
-	fmt.Println(params.Body)
-	dat, _ := json.Marshal(params.email)
-	w.WriteHeader(http.StatusOK)
-	w.Write(dat)
+	if cfg.captcha != nil {
+		if params.CaptchaToken == "" {
+			respondWithError(w, http.StatusBadRequest, "Captcha token is required")
+			return
+		}
+		ok, err := cfg.captcha.Verify(r.Context(), params.CaptchaToken, clientIP(r))
+		if err != nil {
+			log.Printf("Error verifying captcha: %s", err)
+			respondWithError(w, http.StatusServiceUnavailable, "Couldn't verify captcha")
+			return
+		}
+		if !ok {
+			respondWithError(w, http.StatusBadRequest, "Invalid captcha token")
+			return
+		}
+	}
+
+	hashedPassword, err := cfg.passwordHasher.Hash(params.Password)
+	if err != nil {
+		log.Printf("Error hashing password: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't hash password")
+		return
+	}
+
+	user, err := cfg.dbQueries.CreateUser(r.Context(), database.CreateUserParams{
+		Email:          params.Email,
+		HashedPassword: hashedPassword,
+		Handle:         params.Handle,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		respondWithError(w, http.StatusConflict, "Email or handle is already taken")
+		return
+	}
+	if err != nil {
+		log.Printf("Error creating user: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create user")
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, cfg.userFromDB(user))
 }
 
-func respondWithError(w http.ResponseWriter, code int, msg string)         {}
-func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {}
+// checkPasswordPolicy writes a 400 listing the failed rules and returns false
+// when the password doesn't satisfy the configured policy.
+func (cfg *apiConfig) checkPasswordPolicy(w http.ResponseWriter, password string) bool {
+	type passwordPolicyError struct {
+		errorReturnVals
+		FailedRules []string `json:"failed_rules"`
+	}
+
+	failed := cfg.passwordPolicy.Validate(password)
+	if len(failed) == 0 {
+		return true
+	}
+	respondWithJSON(w, http.StatusBadRequest, passwordPolicyError{
+		errorReturnVals: newErrorReturnVals(http.StatusBadRequest, "Password does not meet the password policy"),
+		FailedRules:     failed,
+	})
+	return false
+}
+
+func respondWithError(w http.ResponseWriter, code int, msg string) {
+	respondWithJSON(w, code, newErrorReturnVals(code, msg))
+}
+
+// newErrorReturnVals builds an error body whose code is derived from the HTTP
+// status, e.g. "not_found" for 404.
+func newErrorReturnVals(status int, msg string) errorReturnVals {
+	code := "error"
+	if text := http.StatusText(status); text != "" {
+		code = strings.ReplaceAll(strings.ToLower(text), " ", "_")
+	}
+	return errorReturnVals{Error: msg, Code: code}
+}
+
+// respondWithJSON writes payload as JSON, or in the format negotiated by
+// middlewareNegotiate if the route has it.
+func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
+	enc := encoderFor(w)
+	dat, err := enc.marshal(payload)
+	if err != nil {
+		log.Printf("Error marshalling response: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't encode response")
+		return
+	}
+	w.Header().Set("Content-Type", enc.contentType)
+	w.WriteHeader(code)
+	w.Write(dat)
+}
 
 func main() {
 	godotenv.Load()
 
 	dbURL := os.Getenv("DB_URL")
 	platform := os.Getenv("PLATFORM")
-	db, err := sql.Open("postgres", dbURL)
+	tokenSigner, accessTokenTTL, err := loadJWTConfig()
+	if err != nil {
+		log.Fatalf("Invalid JWT configuration: %s", err)
+	}
 
+	lockout, err := loadLockoutPolicy()
 	if err != nil {
-		return
+		log.Fatalf("Invalid lockout configuration: %s", err)
+	}
+
+	passwordPolicy, err := loadPasswordPolicy()
+	if err != nil {
+		log.Fatalf("Invalid password policy: %s", err)
+	}
+
+	passwordHasher, err := loadPasswordHasher()
+	if err != nil {
+		log.Fatalf("Invalid password hashing configuration: %s", err)
+	}
+
+	rememberMeTTL, err := envDuration("REMEMBER_ME_TOKEN_TTL", rememberMeTokenTTL)
+	if err != nil {
+		log.Fatalf("Invalid remember-me configuration: %s", err)
+	}
+
+	authLimiter, err := loadAuthRateLimiter()
+	if err != nil {
+		log.Fatalf("Invalid auth rate limit configuration: %s", err)
+	}
+
+	captchaVerifier, err := loadCaptchaVerifier()
+	if err != nil {
+		log.Fatalf("Invalid captcha configuration: %s", err)
+	}
+
+	languageDetector, err := loadLanguageDetector()
+	if err != nil {
+		log.Fatalf("Invalid language detector configuration: %s", err)
+	}
+
+	maxChirpLength, err := envInt("CHIRP_MAX_LENGTH", defaultMaxChirpLength)
+	if err != nil {
+		log.Fatalf("Invalid chirp configuration: %s", err)
+	}
+
+	redPerks, err := loadRedPerks()
+	if err != nil {
+		log.Fatalf("Invalid Chirpy Red configuration: %s", err)
+	}
+
+	restoreWindow, err := envDuration("CHIRP_RESTORE_WINDOW", defaultRestoreWindow)
+	if err != nil {
+		log.Fatalf("Invalid chirp restore configuration: %s", err)
+	}
+
+	mediaStore, mediaMaxBytes, err := loadMediaStore()
+	if err != nil {
+		log.Fatalf("Invalid media configuration: %s", err)
+	}
+
+	exportStore, err := loadExportStore()
+	if err != nil {
+		log.Fatalf("Invalid export configuration: %s", err)
+	}
+
+	linkPreviewTimeout, err := envDuration("LINK_PREVIEW_TIMEOUT", defaultLinkPreviewTimeout)
+	if err != nil {
+		log.Fatalf("Invalid link preview configuration: %s", err)
+	}
+
+	viewFlushInterval, err := envDuration("VIEW_COUNT_FLUSH_INTERVAL", defaultViewFlushInterval)
+	if err != nil {
+		log.Fatalf("Invalid view count configuration: %s", err)
+	}
+
+	shutdownTimeout, err := envDuration("SHUTDOWN_TIMEOUT", defaultShutdownTimeout)
+	if err != nil {
+		log.Fatalf("Invalid shutdown configuration: %s", err)
+	}
+
+	deactivationRetention, err := envDuration("USER_DEACTIVATION_RETENTION", defaultDeactivationRetention)
+	if err != nil {
+		log.Fatalf("Invalid deactivation configuration: %s", err)
+	}
+
+	schedulerInterval, err := envDuration("SCHEDULED_CHIRP_POLL_INTERVAL", defaultSchedulerInterval)
+	if err != nil {
+		log.Fatalf("Invalid scheduler configuration: %s", err)
+	}
+
+	cors, err := loadCORSPolicy()
+	if err != nil {
+		log.Fatalf("Invalid CORS configuration: %s", err)
+	}
+
+	compressionMinSize, err := envInt("COMPRESSION_MIN_SIZE", defaultCompressionMinSize)
+	if err != nil {
+		log.Fatalf("Invalid compression configuration: %s", err)
+	}
+
+	limits, err := loadBodyLimits(mediaMaxBytes)
+	if err != nil {
+		log.Fatalf("Invalid body size configuration: %s", err)
+	}
+
+	timeouts, err := loadServerTimeouts()
+	if err != nil {
+		log.Fatalf("Invalid timeout configuration: %s", err)
+	}
+
+	rateLimits, err := loadRequestRateLimits()
+	if err != nil {
+		log.Fatalf("Invalid rate limit configuration: %s", err)
+	}
+
+	maintenance, err := loadMaintenanceMode()
+	if err != nil {
+		log.Fatalf("Invalid maintenance configuration: %s", err)
+	}
+
+	http2Cfg, err := loadHTTP2Config()
+	if err != nil {
+		log.Fatalf("Invalid HTTP/2 configuration: %s", err)
+	}
+
+	tlsCfg, err := loadTLSConfig()
+	if err != nil {
+		log.Fatalf("Invalid TLS configuration: %s", err)
+	}
+
+	db, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		log.Fatalf("Error opening database: %s", err)
 	}
 
 	dbQueries := database.New(db)
 
 	mux := http.NewServeMux()
 
+	handler := timeouts.middleware(mux, middlewareRouteErrors(mux))
+	handler = limits.middleware(mux, handler)
+	handler = rateLimits.middleware(mux, tokenSigner, handler)
+	handler = maintenance.middleware(mux, handler)
+	handler = cors.middleware(handler)
+	handler = middlewareRecover(handler)
+	handler = middlewareCompress(compressionMinSize, handler)
 	server := &http.Server{
 		Addr:    ":8080",
-		Handler: mux, // Use the new ServeMux
+		Handler: handler,
 	}
+	timeouts.apply(server)
+	http2Cfg.apply(server)
+	tlsCfg.configure(server)
+	challengeServer := tlsCfg.challengeServer(timeouts)
 	apiCfg := apiConfig{
 		fileserverHits: atomic.Int32{},
 		dbQueries:      dbQueries,
 		platform:       platform,
 		db:             db,
+		tokenSigner:    tokenSigner,
+		accessTokenTTL: accessTokenTTL,
+		rememberMeTTL:  rememberMeTTL,
+		mailer:         loadMailer(),
+		lockout:        lockout,
+		passwordPolicy: passwordPolicy,
+		passwordHasher: passwordHasher,
+		captcha:        captchaVerifier,
+		restoreWindow:  restoreWindow,
+		mediaStore:     mediaStore,
+		exportStore:    exportStore,
+		mediaMaxBytes:  mediaMaxBytes,
+		linkPreviews:   newLinkPreviewer(dbQueries, linkPreviewTimeout),
+		tasks:          newBackgroundTasks(),
+		maxChirpLength: maxChirpLength,
+		views:          newViewCounter(dbQueries),
+		presence:       newPresenceTracker(dbQueries),
+		publicBaseURL:  strings.TrimSuffix(os.Getenv("PUBLIC_BASE_URL"), "/"),
+		languages:      languageDetector,
+		polkaKey:       os.Getenv("POLKA_KEY"),
+
+		deactivationRetention: deactivationRetention,
+		redPerks:              redPerks,
+		maintenance:           maintenance,
 	}
 
 	// File server at /app/
 	fs := http.FileServer(http.Dir("."))
 	mux.Handle("/app/", apiCfg.middlewareMetricsInc(http.StripPrefix("/app", fs)))
 
-	mux.HandleFunc("GET /api/healthz", readinessHandler)
-
-	mux.HandleFunc("GET /admin/metrics", apiCfg.getMetricsHandler) // fixed method reference
-	mux.HandleFunc("POST /admin/reset", apiCfg.resetMetricsHandler)
-	mux.HandleFunc("POST /api/validate_chirp", chirpHandler)
-	mux.HandleFunc("POST /api/users", apiCfg.createUserHandler)
+	mux.HandleFunc("GET /chirps/{chirpID}", apiCfg.chirpPageHandler)
+
+	// The API is served under /api/v1/, and under /api/ for older clients.
+	v1 := &apiVersion{name: "v1", mux: mux}
+	v1.HandleFunc("GET /livez", livenessHandler)
+	v1.HandleFunc("GET /readyz", apiCfg.readinessHandler)
+	// healthz predates the split and stays as an alias of readyz.
+	v1.HandleFunc("GET /healthz", apiCfg.readinessHandler)
+	mux.HandleFunc("GET /.well-known/jwks.json", apiCfg.jwksHandler)
+	v1.HandleFunc("GET /config", apiCfg.configHandler)
+	v1.HandleFunc("GET /openapi.json", v1.openAPIHandler)
+	v1.HandleFunc("GET /docs", apiDocsHandler)
+
+	mux.HandleFunc("GET /admin/metrics", apiCfg.requireRole(roleAdmin, apiCfg.getMetricsHandler))
+	mux.HandleFunc("POST /admin/reset", apiCfg.requireRole(roleAdmin, apiCfg.resetMetricsHandler))
+	mux.HandleFunc("POST /admin/api_keys", apiCfg.requireRole(roleAdmin, apiCfg.createAPIKeyHandler))
+	mux.HandleFunc("POST /admin/refresh_tokens/expire", apiCfg.requireRole(roleAdmin, apiCfg.expireRefreshTokensHandler))
+	mux.HandleFunc("GET /admin/auth_events", apiCfg.requireRole(roleAdmin, apiCfg.listAuthEventsHandler))
+	mux.HandleFunc("POST /admin/impersonate/{userID}", apiCfg.requireRole(roleAdmin, apiCfg.impersonateUserHandler))
+	mux.HandleFunc("POST /admin/users/{userID}/unlock", apiCfg.requireRole(roleAdmin, apiCfg.unlockUserHandler))
+	mux.HandleFunc("PUT /admin/users/{userID}/verify", apiCfg.requireRole(roleAdmin, apiCfg.verifyUserHandler))
+	mux.HandleFunc("GET /admin/chirps/deleted", apiCfg.requireRole(roleAdmin, apiCfg.listDeletedChirpsHandler))
+	mux.HandleFunc("GET /admin/reports", apiCfg.requireRole(roleAdmin, apiCfg.listReportsHandler))
+	mux.HandleFunc("POST /admin/reports/{reportID}/dismiss", apiCfg.requireRole(roleAdmin, apiCfg.dismissReportHandler))
+	mux.HandleFunc("POST /admin/reports/{reportID}/hide_chirp", apiCfg.requireRole(roleAdmin, apiCfg.hideReportedChirpHandler))
+	mux.HandleFunc("POST /admin/reports/{reportID}/suspend_author", apiCfg.requireRole(roleAdmin, apiCfg.suspendReportedAuthorHandler))
+	mux.HandleFunc("GET /admin/scheduled_chirps", apiCfg.requireRole(roleAdmin, apiCfg.listScheduledChirpsHandler))
+	mux.HandleFunc("GET /admin/maintenance", apiCfg.requireRole(roleAdmin, apiCfg.getMaintenanceHandler))
+	mux.HandleFunc("PUT /admin/maintenance", apiCfg.requireRole(roleAdmin, apiCfg.setMaintenanceHandler))
+	v1.HandleFunc("POST /validate_chirp", apiCfg.chirpHandler)
+	v1.HandleFunc("POST /chirps", apiCfg.middlewareScope(scopeWriteChirps, apiCfg.middlewareIdempotency(apiCfg.createChirpHandler)))
+	v1.HandleFunc("POST /chirps/batch", apiCfg.middlewareScope(scopeWriteChirps, apiCfg.middlewareIdempotency(apiCfg.createThreadHandler)))
+	v1.HandleFunc("GET /feed", apiCfg.middlewareScope(scopeReadChirps, apiCfg.feedHandler))
+	v1.HandleFunc("GET /suggestions", apiCfg.middlewareAuth(apiCfg.suggestionsHandler))
+	v1.HandleFunc("GET /chirps", middlewareETag(apiCfg.middlewareOptionalAuth(apiCfg.listChirpsHandler)))
+	v1.HandleFunc("GET /chirps/search", middlewareETag(apiCfg.middlewareOptionalAuth(apiCfg.searchChirpsHandler)))
+	v1.HandleFunc("GET /chirps/{chirpID}", middlewareETag(apiCfg.middlewareOptionalAuth(apiCfg.getChirpHandler)))
+	v1.HandleFunc("PUT /chirps/{chirpID}", apiCfg.middlewareScope(scopeWriteChirps, apiCfg.updateChirpHandler))
+	v1.HandleFunc("DELETE /chirps/{chirpID}", apiCfg.middlewareScope(scopeWriteChirps, apiCfg.deleteChirpHandler))
+	v1.HandleFunc("POST /chirps/{chirpID}/media", apiCfg.middlewareScope(scopeWriteChirps, apiCfg.uploadChirpMediaHandler))
+	v1.HandleFunc("POST /chirps/{chirpID}/restore", apiCfg.middlewareScope(scopeWriteChirps, apiCfg.restoreChirpHandler))
+	v1.HandleFunc("POST /chirps/{chirpID}/report", apiCfg.middlewareAuth(apiCfg.reportChirpHandler))
+	v1.HandleFunc("GET /chirps/{chirpID}/history", middlewareETag(apiCfg.middlewareOptionalAuth(apiCfg.chirpHistoryHandler)))
+	v1.HandleFunc("POST /chirps/{chirpID}/like", apiCfg.middlewareScope(scopeWriteChirps, apiCfg.likeChirpHandler))
+	v1.HandleFunc("DELETE /chirps/{chirpID}/like", apiCfg.middlewareScope(scopeWriteChirps, apiCfg.unlikeChirpHandler))
+	v1.HandleFunc("POST /chirps/{chirpID}/rechirp", apiCfg.middlewareScope(scopeWriteChirps, apiCfg.rechirpHandler))
+	v1.HandleFunc("DELETE /chirps/{chirpID}/rechirp", apiCfg.middlewareScope(scopeWriteChirps, apiCfg.undoRechirpHandler))
+	v1.HandleFunc("POST /chirps/{chirpID}/bookmark", apiCfg.middlewareScope(scopeWriteChirps, apiCfg.bookmarkChirpHandler))
+	v1.HandleFunc("DELETE /chirps/{chirpID}/bookmark", apiCfg.middlewareScope(scopeWriteChirps, apiCfg.deleteBookmarkHandler))
+	v1.HandleFunc("POST /drafts", apiCfg.middlewareScope(scopeWriteChirps, apiCfg.createDraftHandler))
+	v1.HandleFunc("GET /drafts", apiCfg.middlewareScope(scopeWriteChirps, apiCfg.listDraftsHandler))
+	v1.HandleFunc("GET /drafts/{draftID}", apiCfg.middlewareScope(scopeWriteChirps, apiCfg.getDraftHandler))
+	v1.HandleFunc("PUT /drafts/{draftID}", apiCfg.middlewareScope(scopeWriteChirps, apiCfg.updateDraftHandler))
+	v1.HandleFunc("DELETE /drafts/{draftID}", apiCfg.middlewareScope(scopeWriteChirps, apiCfg.deleteDraftHandler))
+	v1.HandleFunc("POST /drafts/{draftID}/publish", apiCfg.middlewareScope(scopeWriteChirps, apiCfg.publishDraftHandler))
+	v1.HandleFunc("GET /bookmarks", apiCfg.middlewareScope(scopeReadChirps, apiCfg.listBookmarksHandler))
+	v1.HandleFunc("GET /users", middlewareETag(apiCfg.middlewareOptionalAuth(apiCfg.listProfilesHandler)))
+	v1.HandleFunc("GET /users/search", middlewareETag(apiCfg.middlewareOptionalAuth(apiCfg.searchUsersHandler)))
+	v1.HandleFunc("GET /users/{userID}", middlewareETag(apiCfg.middlewareOptionalAuth(apiCfg.getProfileHandler)))
+	v1.HandleFunc("POST /users/{userID}/follow", apiCfg.middlewareAuth(apiCfg.followHandler))
+	v1.HandleFunc("DELETE /users/{userID}/follow", apiCfg.middlewareAuth(apiCfg.unfollowHandler))
+	v1.HandleFunc("POST /users/{userID}/block", apiCfg.middlewareAuth(apiCfg.blockHandler))
+	v1.HandleFunc("DELETE /users/{userID}/block", apiCfg.middlewareAuth(apiCfg.unblockHandler))
+	v1.HandleFunc("POST /users/{userID}/mute", apiCfg.middlewareAuth(apiCfg.muteHandler))
+	v1.HandleFunc("DELETE /users/{userID}/mute", apiCfg.middlewareAuth(apiCfg.unmuteHandler))
+	v1.HandleFunc("GET /users/{userID}/followers", middlewareETag(apiCfg.listFollowersHandler))
+	v1.HandleFunc("GET /users/{userID}/following", middlewareETag(apiCfg.listFollowingHandler))
+	v1.HandleFunc("GET /users/{userID}/chirps", middlewareETag(apiCfg.middlewareOptionalAuth(apiCfg.userTimelineHandler)))
+	v1.HandleFunc("GET /hashtags/{tag}/chirps", middlewareETag(apiCfg.middlewareOptionalAuth(apiCfg.hashtagChirpsHandler)))
+	v1.HandleFunc("GET /trends", apiCfg.trendsHandler)
+	v1.HandleFunc("POST /users", middlewareRateLimit(authLimiter, apiCfg.middlewareIdempotency(apiCfg.createUserHandler)))
+	v1.HandleFunc("PUT /users", apiCfg.requireAccountOwner(apiCfg.updateUserHandler))
+	v1.HandleFunc("POST /users/email/confirm", apiCfg.confirmEmailChangeHandler)
+	v1.HandleFunc("PATCH /users/me", apiCfg.requireAccountOwner(apiCfg.updateProfileHandler))
+	v1.HandleFunc("POST /users/me/deactivate", apiCfg.requireAccountOwner(apiCfg.deactivateMeHandler))
+	v1.HandleFunc("DELETE /users/me", apiCfg.requireAccountOwner(apiCfg.deleteMeHandler))
+	v1.HandleFunc("PUT /users/me/avatar", apiCfg.middlewareAuth(apiCfg.uploadAvatarHandler))
+	v1.HandleFunc("PUT /users/me/pinned_chirp", apiCfg.middlewareAuth(apiCfg.pinChirpHandler))
+	v1.HandleFunc("GET /users/me/preferences", apiCfg.middlewareAuth(apiCfg.getPreferencesHandler))
+	v1.HandleFunc("PUT /users/me/preferences", apiCfg.middlewareAuth(apiCfg.updatePreferencesHandler))
+	v1.HandleFunc("GET /users/me/data", apiCfg.middlewareAuth(apiCfg.userDataHandler))
+	v1.HandleFunc("POST /users/me/export", apiCfg.middlewareAuth(apiCfg.createExportHandler))
+	v1.HandleFunc("GET /users/me/exports/{exportID}", apiCfg.middlewareAuth(apiCfg.getExportHandler))
+	v1.HandleFunc("GET /users/me/exports/{exportID}/download", apiCfg.middlewareAuth(apiCfg.downloadExportHandler))
+	v1.HandleFunc("GET /users/me/mutes", apiCfg.middlewareAuth(apiCfg.listMutesHandler))
+	v1.HandleFunc("GET /users/me/follow_requests", apiCfg.middlewareAuth(apiCfg.listFollowRequestsHandler))
+	v1.HandleFunc("POST /users/me/follow_requests/{userID}/approve", apiCfg.middlewareAuth(apiCfg.approveFollowRequestHandler))
+	v1.HandleFunc("POST /users/me/follow_requests/{userID}/deny", apiCfg.middlewareAuth(apiCfg.denyFollowRequestHandler))
+	v1.HandleFunc("GET /users/me/mentions", apiCfg.middlewareScope(scopeReadChirps, apiCfg.myMentionsHandler))
+	v1.HandleFunc("POST /login", middlewareRateLimit(authLimiter, apiCfg.loginHandler))
+	v1.HandleFunc("POST /refresh", apiCfg.refreshHandler)
+	v1.HandleFunc("POST /revoke", apiCfg.revokeHandler)
+	v1.HandleFunc("POST /logout", apiCfg.requireAccountOwner(apiCfg.logoutHandler))
+	v1.HandleFunc("POST /tokens", apiCfg.requireAccountOwner(apiCfg.createTokenHandler))
+	v1.HandleFunc("GET /tokens", apiCfg.middlewareAuth(apiCfg.listTokensHandler))
+	v1.HandleFunc("DELETE /tokens/{tokenID}", apiCfg.requireAccountOwner(apiCfg.deleteTokenHandler))
+	v1.HandleFunc("GET /devices", apiCfg.middlewareAuth(apiCfg.listDevicesHandler))
+	v1.HandleFunc("GET /notifications", apiCfg.middlewareAuth(apiCfg.listNotificationsHandler))
+	v1.HandleFunc("GET /sessions", apiCfg.middlewareAuth(apiCfg.listSessionsHandler))
+	v1.HandleFunc("DELETE /sessions/{sessionID}", apiCfg.requireAccountOwner(apiCfg.deleteSessionHandler))
+	v1.HandleFunc("POST /polka/webhooks", apiCfg.polkaWebhookHandler)
+	v1.HandleFunc("POST /password_reset", apiCfg.requestPasswordResetHandler)
+	v1.HandleFunc("POST /password_reset/confirm", apiCfg.confirmPasswordResetHandler)
+
+	// Background jobs get their own context, cancelled only once the server
+	// has drained, so the buffered views and presence from the last requests
+	// are still flushed.
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	startWorker := func(run func(ctx context.Context)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run(workerCtx)
+		}()
+	}
+	startWorker(func(ctx context.Context) { apiCfg.runScheduledChirpPublisher(ctx, schedulerInterval) })
+	startWorker(func(ctx context.Context) { apiCfg.runDeactivatedAccountPurger(ctx, deactivationPurgeInterval) })
+	startWorker(func(ctx context.Context) { apiCfg.runSuggestionsRefresher(ctx, suggestionsRefreshInterval) })
+	startWorker(func(ctx context.Context) { apiCfg.runIdempotencyKeyPurger(ctx, idempotencyKeyPurgeInterval) })
+	startWorker(apiCfg.linkPreviews.run)
+	startWorker(func(ctx context.Context) { apiCfg.views.run(ctx, viewFlushInterval) })
+	startWorker(func(ctx context.Context) { apiCfg.presence.run(ctx, presenceFlushInterval) })
+
+	// Background tasks, unlike the workers, aren't cancelled until shutdown
+	// has waited for them, so exports and emails the last requests started
+	// still finish.
+	taskCtx, cancelTasks := context.WithCancel(context.Background())
+	tasksDone := make(chan struct{})
+	go func() {
+		apiCfg.tasks.run(taskCtx)
+		close(tasksDone)
+	}()
+
+	signalCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	// Start the server
-	if err := server.ListenAndServe(); err != nil {
-		panic(err)
+	serverErr := make(chan error, 2)
+	go func() {
+		serverErr <- tlsCfg.listenAndServe(server)
+	}()
+	if challengeServer != nil {
+		go func() {
+			serverErr <- challengeServer.ListenAndServe()
+		}()
+	}
+
+	select {
+	case err := <-serverErr:
+		log.Fatalf("Error serving: %s", err)
+	case <-signalCtx.Done():
+	}
+	// A second signal kills the process without waiting.
+	stop()
+
+	log.Printf("Shutting down, waiting up to %s for requests to finish", shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Error shutting down server: %s", err)
+	}
+	if challengeServer != nil {
+		challengeServer.Shutdown(shutdownCtx)
+	}
+
+	stopWorkers()
+	workers.Wait()
+
+	apiCfg.tasks.close()
+	select {
+	case <-tasksDone:
+	case <-shutdownCtx.Done():
+		log.Printf("Cancelling unfinished background tasks")
+		cancelTasks()
+		<-tasksDone
+	}
+	cancelTasks()
+
+	if err := db.Close(); err != nil {
+		log.Printf("Error closing database: %s", err)
 	}
 }
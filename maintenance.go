@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const defaultMaintenanceRetryAfter = 5 * time.Minute
+
+// maintenanceRoutes are the mux patterns, besides the admin ones, that keep
+// working in maintenance mode: the health checks, so orchestrators can still
+// probe the server, and login and refresh, so admins whose access tokens
+// expire can still turn maintenance mode off.
+var maintenanceRoutes = []string{
+	"GET /api/v1/livez",
+	"GET /api/v1/readyz",
+	"GET /api/v1/healthz",
+	"POST /api/v1/login",
+	"POST /api/v1/refresh",
+}
+
+// maintenanceMode turns away every request but those to maintenanceRoutes
+// and the admin routes while it is on, for migrations and incidents that
+// need the server quiet without taking it down.
+type maintenanceMode struct {
+	enabled atomic.Bool
+	// retryAfter is what clients are told to wait before trying again.
+	retryAfter time.Duration
+	// failsReadiness takes the server out of rotation while maintenance mode
+	// is on. Turn it off to have clients see the 503s with Retry-After, and
+	// to reach /admin/maintenance through the load balancer.
+	failsReadiness bool
+}
+
+// loadMaintenanceMode reads MAINTENANCE_MODE, whether the server starts in
+// maintenance mode, MAINTENANCE_RETRY_AFTER and MAINTENANCE_FAILS_READINESS.
+func loadMaintenanceMode() (*maintenanceMode, error) {
+	enabled, err := envBool("MAINTENANCE_MODE", false)
+	if err != nil {
+		return nil, err
+	}
+	retryAfter, err := envDuration("MAINTENANCE_RETRY_AFTER", defaultMaintenanceRetryAfter)
+	if err != nil {
+		return nil, err
+	}
+	failsReadiness, err := envBool("MAINTENANCE_FAILS_READINESS", true)
+	if err != nil {
+		return nil, err
+	}
+	m := &maintenanceMode{retryAfter: retryAfter, failsReadiness: failsReadiness}
+	m.enabled.Store(enabled)
+	return m, nil
+}
+
+// isMaintenanceRoute reports whether mux would serve r with an admin route or
+// one of maintenanceRoutes.
+func isMaintenanceRoute(mux *http.ServeMux, r *http.Request) bool {
+	_, pattern := mux.Handler(r)
+	_, path, _ := strings.Cut(pattern, " ")
+	return strings.HasPrefix(path, "/admin/") || slices.Contains(maintenanceRoutes, canonicalAPIPattern(pattern))
+}
+
+// middleware answers 503 with a Retry-After header while maintenance mode is
+// on, except on the routes that keep working.
+func (m *maintenanceMode) middleware(mux *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.enabled.Load() && !isMaintenanceRoute(mux, r) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(m.retryAfter.Seconds()))))
+			respondWithError(w, http.StatusServiceUnavailable, "Chirpy is down for maintenance, try again later")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// check fails while maintenance mode is on, if it should take the server out
+// of rotation.
+func (m *maintenanceMode) check(ctx context.Context) error {
+	if m.failsReadiness && m.enabled.Load() {
+		return errors.New("maintenance mode is on")
+	}
+	return nil
+}
+
+type maintenanceResponse struct {
+	Enabled bool `json:"enabled"`
+}
+
+func (cfg *apiConfig) getMaintenanceHandler(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, maintenanceResponse{Enabled: cfg.maintenance.enabled.Load()})
+}
+
+// setMaintenanceHandler turns maintenance mode on or off until the next
+// change or restart.
+func (cfg *apiConfig) setMaintenanceHandler(w http.ResponseWriter, r *http.Request) {
+	type maintenanceParameters struct {
+		Enabled *bool `json:"enabled" validate:"required"`
+	}
+	params := maintenanceParameters{}
+	if !decodeParameters(w, r, &params) {
+		return
+	}
+
+	cfg.maintenance.enabled.Store(*params.Enabled)
+	respondWithJSON(w, http.StatusOK, maintenanceResponse{Enabled: *params.Enabled})
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMaintenanceMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	ok := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+	for _, pattern := range []string{
+		"GET /api/v1/chirps",
+		"GET /api/chirps",
+		"GET /api/v1/readyz",
+		"POST /api/v1/login",
+		"POST /api/login",
+		"POST /admin/maintenance",
+	} {
+		mux.HandleFunc(pattern, ok)
+	}
+
+	m := &maintenanceMode{retryAfter: 90 * time.Second}
+	m.enabled.Store(true)
+	handler := m.middleware(mux, mux)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{"regular route", http.MethodGet, "/api/v1/chirps", http.StatusServiceUnavailable},
+		{"unversioned regular route", http.MethodGet, "/api/chirps", http.StatusServiceUnavailable},
+		{"health check", http.MethodGet, "/api/v1/readyz", http.StatusOK},
+		{"login", http.MethodPost, "/api/v1/login", http.StatusOK},
+		{"unversioned login", http.MethodPost, "/api/login", http.StatusOK},
+		{"admin route", http.MethodPost, "/admin/maintenance", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusServiceUnavailable {
+				if got := w.Header().Get("Retry-After"); got != "90" {
+					t.Errorf("Retry-After = %q, want 90", got)
+				}
+			}
+		})
+	}
+
+	m.enabled.Store(false)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/chirps", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("status with maintenance off = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestMaintenanceCheck(t *testing.T) {
+	tests := []struct {
+		name           string
+		enabled        bool
+		failsReadiness bool
+		wantErr        bool
+	}{
+		{"off", false, true, false},
+		{"on", true, true, true},
+		{"on without failing readiness", true, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &maintenanceMode{failsReadiness: tt.failsReadiness}
+			m.enabled.Store(tt.enabled)
+			if err := m.check(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/auth"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/ratelimit"
+)
+
+type contextKey string
+
+const (
+	userIDContextKey         contextKey = "userID"
+	impersonatorIDContextKey contextKey = "impersonatorID"
+	apiKeyIDContextKey       contextKey = "apiKeyID"
+)
+
+// middlewareAuth rejects requests without a valid bearer JWT and stores the
+// authenticated user's ID in the request context for the wrapped handler.
+func (cfg *apiConfig) middlewareAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, err := auth.GetBearerToken(r.Header)
+		if err != nil {
+			respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT")
+			return
+		}
+
+		claims, err := cfg.tokenSigner.ParseJWT(token)
+		if err != nil {
+			respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT")
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userIDContextKey, claims.UserID)
+		if claims.ImpersonatorID != uuid.Nil {
+			ctx = context.WithValue(ctx, impersonatorIDContextKey, claims.ImpersonatorID)
+		} else {
+			cfg.presence.seen(claims.UserID)
+		}
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// middlewareOptionalAuth lets anonymous requests through to public routes,
+// but authenticates requests that do present a bearer token (session JWT or
+// a personal access token with the read scope) so the handler can
+// personalise its response.
+func (cfg *apiConfig) middlewareOptionalAuth(next http.HandlerFunc) http.HandlerFunc {
+	authed := cfg.middlewareScope(scopeReadChirps, next)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			next(w, r)
+			return
+		}
+		authed(w, r)
+	}
+}
+
+// userIDFromContext returns the user ID stored by middlewareAuth.
+func userIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	userID, ok := ctx.Value(userIDContextKey).(uuid.UUID)
+	return userID, ok
+}
+
+// viewerFromContext returns the authenticated user's ID as a nullable query
+// argument, NULL for anonymous requests on public routes.
+func viewerFromContext(ctx context.Context) uuid.NullUUID {
+	userID, ok := userIDFromContext(ctx)
+	return uuid.NullUUID{UUID: userID, Valid: ok}
+}
+
+// middlewareAPIKey rejects requests that don't present an active API key and
+// stores the key's ID in the request context for the wrapped handler.
+func (cfg *apiConfig) middlewareAPIKey(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, err := auth.GetAPIKey(r.Header)
+		if err != nil {
+			respondWithError(w, http.StatusUnauthorized, "Couldn't find API key")
+			return
+		}
+
+		apiKey, err := cfg.dbQueries.GetAPIKeyByHash(r.Context(), auth.HashToken(key))
+		if err != nil {
+			respondWithError(w, http.StatusUnauthorized, "Invalid API key")
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), apiKeyIDContextKey, apiKey.ID)
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// impersonatorIDFromContext returns the admin acting as the authenticated
+// user, if the request carries an impersonation token.
+func impersonatorIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	impersonatorID, ok := ctx.Value(impersonatorIDContextKey).(uuid.UUID)
+	return impersonatorID, ok
+}
+
+// middlewareScope authenticates requests on routes that third-party apps may
+// call. Session JWTs have every scope; personal access tokens must have been
+// granted the route's scope, otherwise the request is rejected with 403.
+func (cfg *apiConfig) middlewareScope(scope string, next http.HandlerFunc) http.HandlerFunc {
+	jwtAuth := cfg.middlewareAuth(next)
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, err := auth.GetBearerToken(r.Header)
+		if err != nil || !auth.IsPersonalAccessToken(token) {
+			jwtAuth(w, r)
+			return
+		}
+
+		pat, err := cfg.dbQueries.GetPersonalAccessTokenByHash(r.Context(), auth.HashToken(token))
+		if err != nil {
+			respondWithError(w, http.StatusUnauthorized, "Invalid access token")
+			return
+		}
+		if !slices.Contains(pat.Scopes, scope) {
+			respondWithError(w, http.StatusForbidden, fmt.Sprintf("Access token is missing the %s scope", scope))
+			return
+		}
+
+		err = cfg.dbQueries.TouchPersonalAccessToken(r.Context(), pat.ID)
+		if err != nil {
+			log.Printf("Error updating access token usage: %s", err)
+		}
+		cfg.presence.seen(pat.UserID)
+
+		ctx := context.WithValue(r.Context(), userIDContextKey, pat.UserID)
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// requireAccountOwner authenticates the request like middlewareAuth but
+// rejects impersonation tokens with 403. It guards the routes that change
+// credentials or the account itself, so an impersonating admin can't outlast
+// the token's short lifetime, e.g. by minting a personal access token.
+func (cfg *apiConfig) requireAccountOwner(next http.HandlerFunc) http.HandlerFunc {
+	return cfg.middlewareAuth(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := impersonatorIDFromContext(r.Context()); ok {
+			respondWithError(w, http.StatusForbidden, "Not allowed while impersonating")
+			return
+		}
+		next(w, r)
+	})
+}
+
+// requireRole authenticates the request like requireAccountOwner and
+// additionally rejects users whose role doesn't match with 403. Impersonation
+// tokens are never granted a role, so support staff can't escalate through
+// them.
+func (cfg *apiConfig) requireRole(role string, next http.HandlerFunc) http.HandlerFunc {
+	return cfg.requireAccountOwner(func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := userIDFromContext(r.Context())
+		user, err := cfg.dbQueries.GetUserByID(r.Context(), userID)
+		if err != nil {
+			respondWithError(w, http.StatusUnauthorized, "Couldn't find user")
+			return
+		}
+		if user.Role != role {
+			respondWithError(w, http.StatusForbidden, "Insufficient permissions")
+			return
+		}
+		next(w, r)
+	})
+}
+
+// clientIP returns the host part of the request's remote address.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// middlewareRateLimit rejects callers that exceed the limiter's budget for
+// this route with 429 and a Retry-After header. Callers are keyed by IP.
+func middlewareRateLimit(limiter *ratelimit.SlidingWindow, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		allowed, retryAfter := limiter.Allow(canonicalAPIPattern(r.Pattern) + " " + clientIP(r))
+		if !allowed {
+			respondTooManyRequests(w, retryAfter)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func respondTooManyRequests(w http.ResponseWriter, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	respondWithError(w, http.StatusTooManyRequests, "Too many requests, try again later")
+}
+
+// middlewareRouteErrors serves mux, replacing its plain-text pages for
+// unknown routes and wrong methods with the JSON error envelope. The 405
+// keeps the Allow header the mux sets.
+func middlewareRouteErrors(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h, pattern := mux.Handler(r)
+		if pattern != "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		buf := &bufferedResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(buf, r)
+		switch buf.status {
+		case http.StatusNotFound:
+			respondWithError(w, http.StatusNotFound, "Not found")
+		case http.StatusMethodNotAllowed:
+			respondWithError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		default:
+			w.WriteHeader(buf.status)
+			w.Write(buf.body.Bytes())
+		}
+	})
+}
+
+// middlewareRecover turns a panic in any handler into a logged stack trace
+// and a 500, instead of a dropped connection. http.ErrAbortHandler is passed
+// on, since it is how handlers deliberately abort a response.
+func middlewareRecover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			err := recover()
+			if err == nil {
+				return
+			}
+			if err == http.ErrAbortHandler {
+				panic(err)
+			}
+			log.Printf("Panic serving %s %s: %v\n%s", r.Method, r.URL.Path, err, debug.Stack())
+			respondWithError(w, http.StatusInternalServerError, "Internal server error")
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// responseEncoder serializes response bodies in one format.
+type responseEncoder struct {
+	contentType string
+	marshal     func(payload any) ([]byte, error)
+}
+
+var (
+	jsonEncoder = responseEncoder{"application/json", json.Marshal}
+	xmlEncoder  = responseEncoder{"application/xml; charset=utf-8", marshalXML}
+)
+
+// middlewareNegotiate picks the encoder respondWithJSON uses for the
+// request by its Accept header: JSON:API or, on GET routes, XML for clients
+// that prefer them over plain JSON, and plain JSON for everyone else.
+func middlewareNegotiate(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+		enc := negotiateEncoder(r.Header.Get("Accept"), r.Method == http.MethodGet)
+		next(&negotiatedResponseWriter{ResponseWriter: w, enc: enc}, r)
+	}
+}
+
+// negotiatedResponseWriter carries the encoder chosen for the request down
+// to respondWithJSON.
+type negotiatedResponseWriter struct {
+	http.ResponseWriter
+	enc responseEncoder
+}
+
+func (n *negotiatedResponseWriter) Unwrap() http.ResponseWriter {
+	return n.ResponseWriter
+}
+
+// encoderFor returns the encoder negotiated for w, looking through
+// ResponseWriters that wrap it, or jsonEncoder if there wasn't one.
+func encoderFor(w http.ResponseWriter) responseEncoder {
+	for {
+		switch rw := w.(type) {
+		case *negotiatedResponseWriter:
+			return rw.enc
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = rw.Unwrap()
+		default:
+			return jsonEncoder
+		}
+	}
+}
+
+// negotiateEncoder returns the encoder whose format the Accept header gives
+// the highest quality, among XML only if allowXML. Plain JSON wins ties, and
+// is also the answer when the header is missing or accepts none of them.
+func negotiateEncoder(accept string, allowXML bool) responseEncoder {
+	best, bestQ := jsonEncoder, acceptQuality(accept, "application/json")
+	if q := acceptQuality(accept, jsonAPIContentType); q > bestQ {
+		best, bestQ = jsonAPIEncoder, q
+	}
+	if allowXML && acceptQuality(accept, "application/xml", "text/xml") > bestQ {
+		best = xmlEncoder
+	}
+	return best
+}
+
+// acceptQuality returns the q-value the Accept header gives the best of
+// types, taking each from its most specific matching media range.
+func acceptQuality(accept string, types ...string) float64 {
+	best := 0.0
+	for _, typ := range types {
+		major, _, _ := strings.Cut(typ, "/")
+		q, specificity := 0.0, -1
+		for _, mediaRange := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(mediaRange)
+			if err != nil {
+				continue
+			}
+			s := -1
+			switch mediaType {
+			case typ:
+				s = 2
+			case major + "/*":
+				s = 1
+			case "*/*":
+				s = 0
+			}
+			if s <= specificity {
+				continue
+			}
+			specificity, q = s, 1.0
+			if v, err := strconv.ParseFloat(params["q"], 64); err == nil {
+				q = v
+			}
+		}
+		best = max(best, q)
+	}
+	return best
+}
+
+// xmlNamePattern matches JSON keys that can be used as XML element names
+// as they are.
+var xmlNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// marshalXML encodes payload as XML mirroring its JSON encoding, so both
+// formats share field names and omissions. The document's root element is
+// <response>. Objects become elements named after their keys, or <entry
+// key="..."> for keys that aren't valid names; array items become <item>
+// elements; null becomes an empty element with nil="true".
+func marshalXML(payload any) ([]byte, error) {
+	dat, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(dat))
+	dec.UseNumber()
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	err = writeXMLValue(enc, dec, xml.StartElement{Name: xml.Name{Local: "response"}})
+	if err != nil {
+		return nil, err
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeXMLValue reads the next JSON value from dec and writes it to enc as
+// the element start.
+func writeXMLValue(enc *xml.Encoder, dec *json.Decoder, start xml.StartElement) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "nil"}, Value: "true"})
+	}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch v := tok.(type) {
+	case nil:
+	case json.Delim:
+		for dec.More() {
+			child := xml.StartElement{Name: xml.Name{Local: "item"}}
+			if v == '{' {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				child = xmlElementFor(keyTok.(string))
+			}
+			if err := writeXMLValue(enc, dec, child); err != nil {
+				return err
+			}
+		}
+		// Consume the closing delimiter.
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	default:
+		if err := enc.EncodeToken(xml.CharData(fmt.Sprint(v))); err != nil {
+			return err
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
+
+func xmlElementFor(key string) xml.StartElement {
+	if xmlNamePattern.MatchString(key) && !strings.HasPrefix(strings.ToLower(key), "xml") {
+		return xml.StartElement{Name: xml.Name{Local: key}}
+	}
+	return xml.StartElement{
+		Name: xml.Name{Local: "entry"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: key}},
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+const notificationKindNewDevice = "new_device_login"
+
+// notificationKinds are the kinds users can set preferences for.
+var notificationKinds = []string{notificationKindNewDevice}
+
+// notify stores an in-app notification for the user and emails it as a
+// background task so delivery never delays the request that triggered it.
+// Each channel is skipped if the user turned it off for this kind.
+func (cfg *apiConfig) notify(ctx context.Context, user database.User, kind, subject, body string) error {
+	channels := defaultNotificationChannels
+	pref, err := cfg.dbQueries.GetNotificationPreference(ctx, database.GetNotificationPreferenceParams{
+		UserID: user.ID,
+		Kind:   kind,
+	})
+	if err == nil {
+		channels = notificationChannelsFromDB(pref)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	if channels.InApp {
+		_, err = cfg.dbQueries.CreateNotification(ctx, database.CreateNotificationParams{
+			UserID: user.ID,
+			Kind:   kind,
+			Body:   body,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if !channels.Email {
+		return nil
+	}
+	queued := cfg.tasks.enqueue(func(ctx context.Context) {
+		err := cfg.mailer.Send(ctx, user.Email, subject, body)
+		if err != nil {
+			log.Printf("Error emailing %s notification: %s", kind, err)
+		}
+	})
+	if !queued {
+		log.Printf("Background task queue full, not emailing %s notification", kind)
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// routeAuth is how a route authenticates its caller.
+type routeAuth int
+
+const (
+	authNone routeAuth = iota
+	// authOptional routes accept a bearer token and personalize their
+	// response with it, but work without one.
+	authOptional
+	authBearer
+	authAPIKey
+)
+
+// routeDoc describes a route for the OpenAPI document.
+type routeDoc struct {
+	summary string
+	auth    routeAuth
+}
+
+// routeDocs describes the routes of the current API version, keyed by the
+// pattern they are registered with. Routes missing here are still listed,
+// just without a summary.
+var routeDocs = map[string]routeDoc{
+	"GET /livez":           {"Check that the server process is up", authNone},
+	"GET /readyz":          {"Check that the server is ready for traffic", authNone},
+	"GET /healthz":         {"Same as /readyz", authNone},
+	"GET /config":          {"Get client-facing limits such as the maximum chirp length", authNone},
+	"GET /openapi.json":    {"Get this OpenAPI document", authNone},
+	"GET /docs":            {"Browse this document in Swagger UI", authNone},
+	"POST /validate_chirp": {"Check a chirp's length and censor banned words", authNone},
+
+	"POST /chirps":                      {"Post a chirp", authBearer},
+	"POST /chirps/batch":                {"Post a thread of chirps at once", authBearer},
+	"GET /feed":                         {"List chirps from the user and the accounts they follow", authBearer},
+	"GET /suggestions":                  {"List accounts the user might want to follow", authBearer},
+	"GET /chirps":                       {"List chirps", authOptional},
+	"GET /chirps/search":                {"Search chirps", authOptional},
+	"GET /chirps/{chirpID}":             {"Get a chirp", authOptional},
+	"PUT /chirps/{chirpID}":             {"Edit a chirp", authBearer},
+	"DELETE /chirps/{chirpID}":          {"Delete a chirp", authBearer},
+	"POST /chirps/{chirpID}/media":      {"Attach an image to a chirp", authBearer},
+	"POST /chirps/{chirpID}/restore":    {"Restore a recently deleted chirp", authBearer},
+	"POST /chirps/{chirpID}/report":     {"Report a chirp to the moderators", authBearer},
+	"GET /chirps/{chirpID}/history":     {"List a chirp's earlier versions", authOptional},
+	"POST /chirps/{chirpID}/like":       {"Like a chirp", authBearer},
+	"DELETE /chirps/{chirpID}/like":     {"Unlike a chirp", authBearer},
+	"POST /chirps/{chirpID}/rechirp":    {"Rechirp a chirp", authBearer},
+	"DELETE /chirps/{chirpID}/rechirp":  {"Undo a rechirp", authBearer},
+	"POST /chirps/{chirpID}/bookmark":   {"Bookmark a chirp", authBearer},
+	"DELETE /chirps/{chirpID}/bookmark": {"Remove a bookmark", authBearer},
+	"GET /hashtags/{tag}/chirps":        {"List chirps with a hashtag", authOptional},
+	"GET /trends":                       {"List trending hashtags", authNone},
+
+	"POST /drafts":                   {"Save a draft", authBearer},
+	"GET /drafts":                    {"List the user's drafts", authBearer},
+	"GET /drafts/{draftID}":          {"Get a draft", authBearer},
+	"PUT /drafts/{draftID}":          {"Update a draft", authBearer},
+	"DELETE /drafts/{draftID}":       {"Delete a draft", authBearer},
+	"POST /drafts/{draftID}/publish": {"Publish a draft as a chirp", authBearer},
+	"GET /bookmarks":                 {"List the user's bookmarks", authBearer},
+
+	"GET /users":                    {"Get profiles by handle", authOptional},
+	"GET /users/search":             {"Search users", authOptional},
+	"GET /users/{userID}":           {"Get a profile by user ID or handle", authOptional},
+	"POST /users/{userID}/follow":   {"Follow a user, or ask to if their account is private", authBearer},
+	"DELETE /users/{userID}/follow": {"Unfollow a user", authBearer},
+	"POST /users/{userID}/block":    {"Block a user", authBearer},
+	"DELETE /users/{userID}/block":  {"Unblock a user", authBearer},
+	"POST /users/{userID}/mute":     {"Mute a user", authBearer},
+	"DELETE /users/{userID}/mute":   {"Unmute a user", authBearer},
+	"GET /users/{userID}/followers": {"List a user's followers", authNone},
+	"GET /users/{userID}/following": {"List the accounts a user follows", authNone},
+	"GET /users/{userID}/chirps":    {"List a user's chirps", authOptional},
+
+	"POST /users":                                     {"Create an account", authNone},
+	"PUT /users":                                      {"Change the user's email or password, given the current one", authBearer},
+	"POST /users/email/confirm":                       {"Confirm an email change", authNone},
+	"PATCH /users/me":                                 {"Update the user's profile", authBearer},
+	"POST /users/me/deactivate":                       {"Deactivate the user's account", authBearer},
+	"DELETE /users/me":                                {"Delete the user's account", authBearer},
+	"PUT /users/me/avatar":                            {"Upload the user's avatar", authBearer},
+	"PUT /users/me/pinned_chirp":                      {"Pin or unpin one of the user's chirps", authBearer},
+	"GET /users/me/preferences":                       {"Get the user's preferences", authBearer},
+	"PUT /users/me/preferences":                       {"Update the user's preferences", authBearer},
+	"GET /users/me/data":                              {"Get everything stored about the user", authBearer},
+	"POST /users/me/export":                           {"Start an export of the user's data", authBearer},
+	"GET /users/me/exports/{exportID}":                {"Get the status of an export", authBearer},
+	"GET /users/me/exports/{exportID}/download":       {"Download a finished export", authBearer},
+	"GET /users/me/mutes":                             {"List the accounts the user has muted", authBearer},
+	"GET /users/me/follow_requests":                   {"List pending requests to follow the user", authBearer},
+	"POST /users/me/follow_requests/{userID}/approve": {"Approve a follow request", authBearer},
+	"POST /users/me/follow_requests/{userID}/deny":    {"Deny a follow request", authBearer},
+	"GET /users/me/mentions":                          {"List chirps mentioning the user", authBearer},
+	"POST /login":                                     {"Log in with email and password", authNone},
+	"POST /refresh":                                   {"Get a new access token with a refresh token", authBearer},
+	"POST /revoke":                                    {"Revoke a refresh token", authBearer},
+	"POST /logout":                                    {"Log out of the current session", authBearer},
+	"POST /tokens":                                    {"Create a personal access token", authBearer},
+	"GET /tokens":                                     {"List the user's personal access tokens", authBearer},
+	"DELETE /tokens/{tokenID}":                        {"Revoke a personal access token", authBearer},
+	"GET /devices":                                    {"List devices the user has logged in from", authBearer},
+	"GET /notifications":                              {"List the user's notifications", authBearer},
+	"GET /sessions":                                   {"List the user's active sessions", authBearer},
+	"DELETE /sessions/{sessionID}":                    {"End a session", authBearer},
+	"POST /polka/webhooks":                            {"Receive payment events from Polka", authAPIKey},
+	"POST /password_reset":                            {"Email a password reset link", authNone},
+	"POST /password_reset/confirm":                    {"Set a new password with a reset token", authNone},
+}
+
+var pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+type openAPIDocument struct {
+	OpenAPI    string                                 `json:"openapi"`
+	Info       openAPIInfo                            `json:"info"`
+	Servers    []openAPIServer                        `json:"servers"`
+	Paths      map[string]map[string]openAPIOperation `json:"paths"`
+	Components openAPIComponents                      `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIServer struct {
+	URL string `json:"url"`
+}
+
+type openAPIOperation struct {
+	Summary    string                     `json:"summary,omitempty"`
+	Tags       []string                   `json:"tags"`
+	Parameters []openAPIParameter         `json:"parameters,omitempty"`
+	Security   []map[string][]string      `json:"security"`
+	Responses  map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name     string            `json:"name"`
+	In       string            `json:"in"`
+	Required bool              `json:"required"`
+	Schema   map[string]string `json:"schema"`
+}
+
+type openAPIResponse struct {
+	Description string         `json:"description"`
+	Content     map[string]any `json:"content,omitempty"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]any `json:"schemas"`
+	SecuritySchemes map[string]any `json:"securitySchemes"`
+}
+
+// openAPIDocument describes the version's routes in OpenAPI 3: their paths,
+// path parameters and authentication. Bodies are left undescribed.
+func (v *apiVersion) openAPIDocument() openAPIDocument {
+	doc := openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info:    openAPIInfo{Title: "Chirpy API", Version: v.name},
+		Servers: []openAPIServer{{URL: "/api/" + v.name}},
+		Paths:   make(map[string]map[string]openAPIOperation),
+		Components: openAPIComponents{
+			Schemas: map[string]any{
+				"Error": map[string]any{
+					"type":     "object",
+					"required": []string{"error", "code"},
+					"properties": map[string]any{
+						"error": map[string]string{"type": "string"},
+						"code":  map[string]string{"type": "string"},
+						"fields": map[string]any{
+							"type":        "array",
+							"description": "The invalid fields of a request, if that is the problem",
+							"items": map[string]any{
+								"type":     "object",
+								"required": []string{"field", "message"},
+								"properties": map[string]any{
+									"field":   map[string]string{"type": "string"},
+									"message": map[string]string{"type": "string"},
+								},
+							},
+						},
+					},
+				},
+			},
+			SecuritySchemes: map[string]any{
+				"bearerAuth": map[string]string{"type": "http", "scheme": "bearer"},
+				"apiKey":     map[string]string{"type": "apiKey", "in": "header", "name": "Authorization"},
+			},
+		},
+	}
+
+	errorResponse := openAPIResponse{
+		Description: "Error",
+		Content: map[string]any{
+			"application/json": map[string]any{
+				"schema": map[string]string{"$ref": "#/components/schemas/Error"},
+			},
+		},
+	}
+
+	for _, pattern := range v.routes {
+		method, path, _ := strings.Cut(pattern, " ")
+		routeDoc := routeDocs[pattern]
+
+		op := openAPIOperation{
+			Summary: routeDoc.summary,
+			Tags:    []string{strings.Split(strings.TrimPrefix(path, "/"), "/")[0]},
+			Responses: map[string]openAPIResponse{
+				"2XX":     {Description: "Success"},
+				"default": errorResponse,
+			},
+		}
+		for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+			op.Parameters = append(op.Parameters, openAPIParameter{
+				Name:     match[1],
+				In:       "path",
+				Required: true,
+				Schema:   map[string]string{"type": "string"},
+			})
+		}
+		switch routeDoc.auth {
+		case authNone:
+			op.Security = []map[string][]string{}
+		case authOptional:
+			op.Security = []map[string][]string{{}, {"bearerAuth": {}}}
+		case authBearer:
+			op.Security = []map[string][]string{{"bearerAuth": {}}}
+		case authAPIKey:
+			op.Security = []map[string][]string{{"apiKey": {}}}
+		}
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]openAPIOperation)
+		}
+		doc.Paths[path][strings.ToLower(method)] = op
+	}
+	return doc
+}
+
+func (v *apiVersion) openAPIHandler(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, v.openAPIDocument())
+}
+
+// apiDocsPage shows openapi.json, next to it, in Swagger UI loaded from a
+// CDN.
+const apiDocsPage = `<!doctype html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Chirpy API</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+<script>
+SwaggerUIBundle({url: new URL("openapi.json", location.href).href, dom_id: "#swagger-ui"});
+</script>
+</body>
+</html>
+`
+
+func apiDocsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(apiDocsPage))
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// pageParams are the keyset pagination parameters of a listing request:
+// ?limit= and ?after=, where after is an opaque cursor naming the last item
+// of the previous page by its (created_at, id).
+type pageParams struct {
+	// paginated is false when the client sent neither parameter, for
+	// endpoints that still return unbounded arrays to older clients.
+	paginated      bool
+	limit          int32
+	afterCreatedAt sql.NullTime
+	afterID        uuid.NullUUID
+}
+
+func parsePageParams(r *http.Request) (pageParams, error) {
+	query := r.URL.Query()
+	page := pageParams{limit: defaultPageSize}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > maxPageSize {
+			return pageParams{}, errors.New("limit must be between 1 and " + strconv.Itoa(maxPageSize))
+		}
+		page.limit = int32(limit)
+		page.paginated = true
+	}
+
+	if v := query.Get("after"); v != "" {
+		createdAt, id, err := decodeCursor(v)
+		if err != nil {
+			return pageParams{}, errors.New("Invalid cursor")
+		}
+		page.afterCreatedAt = sql.NullTime{Time: createdAt, Valid: true}
+		page.afterID = uuid.NullUUID{UUID: id, Valid: true}
+		page.paginated = true
+	}
+
+	return page, nil
+}
+
+// maxResults is the query LIMIT; NULL (no limit) for unpaginated requests.
+func (p pageParams) maxResults() sql.NullInt32 {
+	return sql.NullInt32{Int32: p.limit, Valid: p.paginated}
+}
+
+// nextCursor returns the cursor for the page after one ending with the given
+// item, or "" when the page wasn't full and so was the last one.
+func (p pageParams) nextCursor(count int, createdAt time.Time, id uuid.UUID) string {
+	if count < int(p.limit) {
+		return ""
+	}
+	return encodeCursor(createdAt, id)
+}
+
+// setCursorPageHeaders describes a page of a keyset listing to generic
+// clients: X-Total-Count is the size of the whole listing, and the Link
+// header (RFC 8288) points to the first page and, unless this is the last
+// one, the next. Cursors only lead forward, so there is no rel="prev".
+func setCursorPageHeaders(w http.ResponseWriter, r *http.Request, nextCursor string, total int64) {
+	links := []string{pageLink(r, "first", "after", "")}
+	if nextCursor != "" {
+		links = append(links, pageLink(r, "next", "after", nextCursor))
+	}
+	w.Header().Set("Link", strings.Join(links, ", "))
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+}
+
+// setOffsetPageHeaders is setCursorPageHeaders for listings paged by
+// ?offset=, which can also link to the previous and last pages.
+func setOffsetPageHeaders(w http.ResponseWriter, r *http.Request, offset, limit int, total int64) {
+	links := []string{pageLink(r, "first", "offset", "")}
+	if offset > 0 {
+		links = append(links, pageLink(r, "prev", "offset", strconv.Itoa(max(offset-limit, 0))))
+	}
+	if int64(offset+limit) < total {
+		links = append(links, pageLink(r, "next", "offset", strconv.Itoa(offset+limit)))
+	}
+	if total > 0 {
+		last := (total - 1) / int64(limit) * int64(limit)
+		links = append(links, pageLink(r, "last", "offset", strconv.FormatInt(last, 10)))
+	}
+	w.Header().Set("Link", strings.Join(links, ", "))
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+}
+
+// pageLink formats a Link header entry for the request's URL with the query
+// parameter key set to value, or removed if value is "".
+func pageLink(r *http.Request, rel, key, value string) string {
+	query := r.URL.Query()
+	if value == "" {
+		query.Del(key)
+	} else {
+		query.Set(key, value)
+	}
+	u := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+	return fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel)
+}
+
+func encodeCursor(createdAt time.Time, id uuid.UUID) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, err
+	}
+	createdAtStr, idStr, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return time.Time{}, uuid.Nil, errors.New("malformed cursor")
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, createdAtStr)
+	if err != nil {
+		return time.Time{}, uuid.Nil, err
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return time.Time{}, uuid.Nil, err
+	}
+	return createdAt, id, nil
+}
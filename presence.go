@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+const (
+	presenceFlushInterval = time.Minute
+	// presenceOnlineWindow and presenceRecentWindow are how long after their
+	// last request users count as online and as recently active.
+	presenceOnlineWindow = 5 * time.Minute
+	presenceRecentWindow = 24 * time.Hour
+)
+
+const (
+	presenceOnline         = "online"
+	presenceRecentlyActive = "recently_active"
+	presenceOffline        = "offline"
+)
+
+// presenceTracker remembers when users last made an authenticated request
+// and periodically writes it to their last_seen_at in one query, so a user
+// is written at most once per flush however busy they are.
+type presenceTracker struct {
+	db *database.Queries
+
+	mu      sync.Mutex
+	pending map[uuid.UUID]time.Time
+}
+
+func newPresenceTracker(db *database.Queries) *presenceTracker {
+	return &presenceTracker{
+		db:      db,
+		pending: make(map[uuid.UUID]time.Time),
+	}
+}
+
+// seen records that the user is active now.
+func (t *presenceTracker) seen(userID uuid.UUID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending[userID] = time.Now().UTC()
+}
+
+// run flushes the recorded activity every interval until ctx is cancelled,
+// then flushes once more.
+func (t *presenceTracker) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			t.flush(context.Background())
+			return
+		case <-ticker.C:
+			t.flush(ctx)
+		}
+	}
+}
+
+func (t *presenceTracker) flush(ctx context.Context) {
+	t.mu.Lock()
+	pending := t.pending
+	t.pending = make(map[uuid.UUID]time.Time)
+	t.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	ids := make([]uuid.UUID, 0, len(pending))
+	seenAt := make([]time.Time, 0, len(pending))
+	for id, at := range pending {
+		ids = append(ids, id)
+		seenAt = append(seenAt, at)
+	}
+
+	err := t.db.SetUsersLastSeen(ctx, database.SetUsersLastSeenParams{
+		UserIds: ids,
+		SeenAt:  seenAt,
+	})
+	if err != nil {
+		// The users' next requests record them again, so there's nothing
+		// to put back.
+		log.Printf("Error flushing presence: %s", err)
+	}
+}
+
+// presenceOf describes how recently the user was active, or "" if they hide
+// it from the viewer.
+func presenceOf(user database.User, self bool) string {
+	if !user.ShowPresence && !self {
+		return ""
+	}
+	if !user.LastSeenAt.Valid {
+		return presenceOffline
+	}
+	since := time.Since(user.LastSeenAt.Time)
+	switch {
+	case since <= presenceOnlineWindow:
+		return presenceOnline
+	case since <= presenceRecentWindow:
+		return presenceRecentlyActive
+	default:
+		return presenceOffline
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/auth"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/ratelimit"
+)
+
+const (
+	defaultReadRateLimit   = 300
+	defaultWriteRateLimit  = 60
+	defaultUploadRateLimit = 10
+	defaultRateLimitPeriod = time.Minute
+)
+
+// requestRateLimits caps how often each caller may make requests, with
+// separate budgets for reads, writes and uploads. Callers are keyed by user
+// when they present a valid session token, and by IP otherwise.
+type requestRateLimits struct {
+	read   *ratelimit.TokenBucket
+	write  *ratelimit.TokenBucket
+	upload *ratelimit.TokenBucket
+}
+
+// loadRequestRateLimits reads RATE_LIMIT_READ, RATE_LIMIT_WRITE and
+// RATE_LIMIT_UPLOAD, the number of requests of each class allowed per
+// RATE_LIMIT_PERIOD.
+func loadRequestRateLimits() (requestRateLimits, error) {
+	period, err := envDuration("RATE_LIMIT_PERIOD", defaultRateLimitPeriod)
+	if err != nil {
+		return requestRateLimits{}, err
+	}
+	var l requestRateLimits
+	for _, setting := range []struct {
+		key      string
+		fallback int
+		dst      **ratelimit.TokenBucket
+	}{
+		{"RATE_LIMIT_READ", defaultReadRateLimit, &l.read},
+		{"RATE_LIMIT_WRITE", defaultWriteRateLimit, &l.write},
+		{"RATE_LIMIT_UPLOAD", defaultUploadRateLimit, &l.upload},
+	} {
+		limit, err := envInt(setting.key, setting.fallback)
+		if err != nil {
+			return requestRateLimits{}, err
+		}
+		*setting.dst = ratelimit.NewTokenBucket(limit, period)
+	}
+	return l, nil
+}
+
+// middleware spends a token from the caller's bucket for the class of route
+// mux would serve r with, reporting the bucket's state in RateLimit-Limit,
+// RateLimit-Remaining and RateLimit-Reset headers, and answers 429 once it
+// is empty.
+func (l requestRateLimits) middleware(mux *http.ServeMux, signer auth.TokenSigner, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limiter := l.write
+		switch {
+		case isUploadRoute(mux, r):
+			limiter = l.upload
+		case r.Method == http.MethodGet || r.Method == http.MethodHead:
+			limiter = l.read
+		}
+
+		res := limiter.Take(rateLimitKey(signer, r))
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		w.Header().Set("RateLimit-Reset", strconv.Itoa(int(res.Reset.Round(time.Second).Seconds())))
+		if !res.Allowed {
+			respondTooManyRequests(w, res.RetryAfter)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rateLimitKey identifies the caller by the user of a valid session token, or
+// else by client IP. Personal access tokens would need a query to verify, so
+// they count against the IP too; an unverified token can't be trusted, or
+// made-up ones would each get a fresh bucket.
+func rateLimitKey(signer auth.TokenSigner, r *http.Request) string {
+	token, err := auth.GetBearerToken(r.Header)
+	if err == nil && !auth.IsPersonalAccessToken(token) {
+		if claims, err := signer.ParseJWT(token); err == nil {
+			return "user:" + claims.UserID.String()
+		}
+	}
+	return "ip:" + clientIP(r)
+}
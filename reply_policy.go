@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+// Reply policies control who may reply to a chirp. The author can always
+// reply to their own chirps.
+const (
+	replyPolicyEveryone  = "everyone"
+	replyPolicyFollowers = "followers"
+	replyPolicyMentioned = "mentioned"
+)
+
+var replyPolicies = []string{replyPolicyEveryone, replyPolicyFollowers, replyPolicyMentioned}
+
+var replyPolicyMessages = map[string]string{
+	replyPolicyFollowers: "Only the author's followers can reply to this chirp",
+	replyPolicyMentioned: "Only people mentioned in this chirp can reply to it",
+}
+
+var (
+	errReplyNotAllowed = errors.New("reply not allowed by the chirp's reply policy")
+	errReplyBlocked    = errors.New("reply not allowed: blocked by the chirp's author")
+)
+
+// checkReplyPolicy returns errReplyBlocked if the author of parent has
+// blocked userID, or errReplyNotAllowed if parent's reply policy excludes
+// them.
+func (cfg *apiConfig) checkReplyPolicy(ctx context.Context, parent database.Chirp, userID uuid.UUID) error {
+	if parent.UserID == userID {
+		return nil
+	}
+
+	blocked, err := cfg.dbQueries.IsBlocked(ctx, database.IsBlockedParams{
+		BlockerID: parent.UserID,
+		BlockedID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return errReplyBlocked
+	}
+
+	switch parent.ReplyPolicy {
+	case replyPolicyMentioned:
+		mentioned, err := cfg.dbQueries.IsMentioned(ctx, database.IsMentionedParams{
+			ChirpID: parent.ID,
+			UserID:  userID,
+		})
+		if err != nil {
+			return err
+		}
+		if !mentioned {
+			return errReplyNotAllowed
+		}
+	case replyPolicyFollowers:
+		following, err := cfg.dbQueries.IsFollowing(ctx, database.IsFollowingParams{
+			FollowerID: userID,
+			FolloweeID: parent.UserID,
+		})
+		if err != nil {
+			return err
+		}
+		if !following {
+			return errReplyNotAllowed
+		}
+	}
+	return nil
+}
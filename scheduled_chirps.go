@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+const (
+	defaultSchedulerInterval = 30 * time.Second
+	// scheduledChirpBatchSize bounds how many due chirps one publisher pass
+	// claims, so a backlog is worked off over several short transactions.
+	scheduledChirpBatchSize = 100
+)
+
+// ScheduledChirp is a chirp waiting to be published at PublishAt.
+type ScheduledChirp struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Body      string    `json:"body"`
+	UserID    uuid.UUID `json:"user_id"`
+	PublishAt time.Time `json:"publish_at"`
+}
+
+func scheduledChirpFromDB(chirp database.ScheduledChirp) ScheduledChirp {
+	return ScheduledChirp{
+		ID:        chirp.ID,
+		CreatedAt: chirp.CreatedAt,
+		Body:      chirp.Body,
+		UserID:    chirp.UserID,
+		PublishAt: chirp.PublishAt,
+	}
+}
+
+// runScheduledChirpPublisher publishes due scheduled chirps every interval
+// until ctx is cancelled.
+func (cfg *apiConfig) runScheduledChirpPublisher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for {
+			published, err := cfg.publishDueChirps(ctx)
+			if err != nil {
+				log.Printf("Error publishing scheduled chirps: %s", err)
+				break
+			}
+			if published < scheduledChirpBatchSize {
+				break
+			}
+		}
+	}
+}
+
+// publishDueChirps turns one batch of due scheduled chirps into real chirps.
+// Claimed rows are locked with SKIP LOCKED, so several server instances can
+// run the publisher without publishing anything twice.
+func (cfg *apiConfig) publishDueChirps(ctx context.Context) (int, error) {
+	tx, err := cfg.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.dbQueries.WithTx(tx)
+
+	due, err := qtx.ClaimDueScheduledChirps(ctx, scheduledChirpBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	published := make([]database.Chirp, 0, len(due))
+	for _, scheduled := range due {
+		chirp, err := cfg.insertChirp(ctx, qtx, database.CreateChirpParams{
+			Body:   scheduled.Body,
+			UserID: scheduled.UserID,
+		})
+		if err != nil {
+			return 0, err
+		}
+		published = append(published, chirp)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+	for _, chirp := range published {
+		cfg.linkPreviews.enqueue(chirp)
+	}
+	return len(published), nil
+}
+
+// listScheduledChirpsHandler shows admins the queue of chirps waiting to be
+// published, soonest first.
+func (cfg *apiConfig) listScheduledChirpsHandler(w http.ResponseWriter, r *http.Request) {
+	rows, err := cfg.dbQueries.ListScheduledChirps(r.Context())
+	if err != nil {
+		log.Printf("Error listing scheduled chirps: %s", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't list scheduled chirps")
+		return
+	}
+
+	chirps := make([]ScheduledChirp, 0, len(rows))
+	for _, row := range rows {
+		chirps = append(chirps, scheduledChirpFromDB(row))
+	}
+	respondWithJSON(w, http.StatusOK, chirps)
+}
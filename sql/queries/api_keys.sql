@@ -0,0 +1,11 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (id, created_at, updated_at, name, key_hash, revoked_at)
+
+VALUES (gen_random_uuid(), NOW(), NOW(), $1, $2, NULL)
+
+RETURNING *;
+
+-- name: GetAPIKeyByHash :one
+SELECT * FROM api_keys
+WHERE key_hash = $1
+AND revoked_at IS NULL;
@@ -0,0 +1,18 @@
+-- name: CreateAuthEvent :exec
+INSERT INTO auth_events (id, created_at, user_id, event_type, ip_address, user_agent, detail)
+
+VALUES (gen_random_uuid(), NOW(), $1, $2, $3, $4, $5);
+
+-- name: ListAuthEvents :many
+SELECT * FROM auth_events
+WHERE (sqlc.narg('user_id')::uuid IS NULL OR user_id = sqlc.narg('user_id'))
+AND (sqlc.narg('event_type')::text IS NULL OR event_type = sqlc.narg('event_type'))
+AND (sqlc.narg('since')::timestamp IS NULL OR created_at >= sqlc.narg('since'))
+AND (sqlc.narg('until')::timestamp IS NULL OR created_at < sqlc.narg('until'))
+ORDER BY created_at DESC
+LIMIT sqlc.arg('max_results');
+
+-- name: ListAuthEventsForUser :many
+SELECT * FROM auth_events
+WHERE user_id = $1
+ORDER BY created_at ASC;
@@ -0,0 +1,25 @@
+-- name: Block :exec
+INSERT INTO blocks (blocker_id, blocked_id, created_at)
+
+VALUES ($1, $2, NOW())
+
+ON CONFLICT (blocker_id, blocked_id) DO NOTHING;
+
+-- name: Unblock :exec
+DELETE FROM blocks
+WHERE blocker_id = $1 AND blocked_id = $2;
+
+-- name: IsBlocked :one
+SELECT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocker_id = $1 AND blocked_id = $2
+);
+
+-- name: DeleteBlocksByUser :exec
+DELETE FROM blocks
+WHERE blocker_id = $1 OR blocked_id = $1;
+
+-- name: ListBlocksByUser :many
+SELECT * FROM blocks
+WHERE blocker_id = $1
+ORDER BY created_at ASC;
@@ -0,0 +1,40 @@
+-- name: AddBookmark :exec
+INSERT INTO bookmarks (user_id, chirp_id, created_at)
+
+VALUES ($1, $2, NOW())
+
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteBookmark :exec
+DELETE FROM bookmarks
+WHERE user_id = $1 AND chirp_id = $2;
+
+-- name: ListBookmarkedChirps :many
+SELECT sqlc.embed(chirps), bookmarks.created_at AS bookmarked_at
+FROM bookmarks
+JOIN chirps ON chirps.id = bookmarks.chirp_id
+WHERE bookmarks.user_id = sqlc.arg('user_id')
+AND chirps.deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND (
+    sqlc.narg('after_created_at')::timestamp IS NULL
+    OR (bookmarks.created_at, bookmarks.chirp_id) < (sqlc.narg('after_created_at')::timestamp, sqlc.narg('after_id')::uuid)
+)
+ORDER BY bookmarks.created_at DESC, bookmarks.chirp_id DESC
+LIMIT sqlc.arg('max_results');
+
+-- name: CountBookmarkedChirps :one
+SELECT COUNT(*) FROM bookmarks
+JOIN chirps ON chirps.id = bookmarks.chirp_id
+WHERE bookmarks.user_id = sqlc.arg('user_id')
+AND chirps.deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL);
+
+-- name: ListBookmarksByUser :many
+SELECT * FROM bookmarks
+WHERE user_id = $1
+ORDER BY created_at ASC;
+
+-- name: DeleteBookmarksByUser :exec
+DELETE FROM bookmarks
+WHERE user_id = $1;
@@ -0,0 +1,76 @@
+-- name: AddChirpHashtag :exec
+INSERT INTO chirp_hashtags (chirp_id, tag, created_at)
+
+VALUES ($1, $2, NOW())
+
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteChirpHashtags :exec
+DELETE FROM chirp_hashtags
+WHERE chirp_id = $1;
+
+-- name: ListChirpsByHashtag :many
+SELECT chirps.* FROM chirps
+JOIN chirp_hashtags ON chirp_hashtags.chirp_id = chirps.id
+WHERE chirp_hashtags.tag = sqlc.arg('tag')
+AND chirps.deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = sqlc.narg('viewer_id')::uuid AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = sqlc.narg('viewer_id')::uuid AND mutes.muted_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = sqlc.narg('viewer_id')::uuid AND follows.followee_id = users.id
+    )
+)
+AND (
+    sqlc.narg('after_created_at')::timestamp IS NULL
+    OR (chirps.created_at, chirps.id) < (sqlc.narg('after_created_at')::timestamp, sqlc.narg('after_id')::uuid)
+)
+ORDER BY chirps.created_at DESC, chirps.id DESC
+LIMIT sqlc.arg('max_results');
+
+-- name: CountChirpsByHashtag :one
+SELECT COUNT(*) FROM chirps
+JOIN chirp_hashtags ON chirp_hashtags.chirp_id = chirps.id
+WHERE chirp_hashtags.tag = sqlc.arg('tag')
+AND chirps.deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = sqlc.narg('viewer_id')::uuid AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = sqlc.narg('viewer_id')::uuid AND mutes.muted_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = sqlc.narg('viewer_id')::uuid AND follows.followee_id = users.id
+    )
+);
+
+-- name: ListTrendingHashtags :many
+SELECT chirp_hashtags.tag, COUNT(*) AS chirp_count FROM chirp_hashtags
+JOIN chirps ON chirps.id = chirp_hashtags.chirp_id
+WHERE chirp_hashtags.created_at > $1
+AND chirps.deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+GROUP BY chirp_hashtags.tag
+ORDER BY chirp_count DESC, chirp_hashtags.tag ASC
+LIMIT $2;
@@ -0,0 +1,30 @@
+-- name: CreateChirpReport :one
+INSERT INTO chirp_reports (id, created_at, updated_at, chirp_id, reporter_id, reason, details)
+
+VALUES (gen_random_uuid(), NOW(), NOW(), $1, $2, $3, $4)
+
+ON CONFLICT (chirp_id, reporter_id) DO NOTHING
+
+RETURNING *;
+
+-- name: GetChirpReport :one
+SELECT * FROM chirp_reports
+WHERE id = $1;
+
+-- name: ListChirpReports :many
+SELECT * FROM chirp_reports
+WHERE sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status')::text
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg('max_results');
+
+-- name: ListChirpReportsByReporter :many
+SELECT * FROM chirp_reports
+WHERE reporter_id = $1
+ORDER BY created_at ASC, id ASC;
+
+-- name: ResolveChirpReport :one
+UPDATE chirp_reports
+SET status = $2, action = $3, resolved_by = $4, resolved_at = NOW(), updated_at = NOW()
+WHERE id = $1
+AND status = 'open'
+RETURNING *;
@@ -0,0 +1,313 @@
+-- name: CreateChirp :one
+INSERT INTO chirps (id, created_at, updated_at, body, user_id, in_reply_to_id, reply_policy, language)
+
+VALUES (gen_random_uuid(), NOW(), NOW(), $1, $2, $3, $4, $5)
+
+RETURNING *;
+
+-- name: GetChirp :one
+SELECT * FROM chirps
+WHERE chirps.id = $1 AND chirps.deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL);
+
+-- name: GetChirpIncludingDeleted :one
+SELECT * FROM chirps
+WHERE id = $1;
+
+-- name: SoftDeleteChirp :exec
+UPDATE chirps
+SET deleted_at = NOW()
+WHERE id = $1;
+
+-- name: RestoreChirp :exec
+UPDATE chirps
+SET deleted_at = NULL
+WHERE id = $1;
+
+-- name: ListDeletedChirps :many
+SELECT * FROM chirps
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, id DESC
+LIMIT $1;
+
+-- name: UpdateChirpBody :one
+UPDATE chirps
+SET body = $2, language = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: ListChirps :many
+SELECT * FROM chirps
+WHERE deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND (sqlc.narg('language')::text IS NULL OR language = sqlc.narg('language'))
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = sqlc.narg('viewer_id')::uuid AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = sqlc.narg('viewer_id')::uuid AND mutes.muted_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = sqlc.narg('viewer_id')::uuid AND follows.followee_id = users.id
+    )
+)
+AND (
+    sqlc.narg('after_created_at')::timestamp IS NULL
+    OR (created_at, id) > (sqlc.narg('after_created_at')::timestamp, sqlc.narg('after_id')::uuid)
+)
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.narg('max_results');
+
+-- name: CountChirps :one
+SELECT COUNT(*) FROM chirps
+WHERE deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND (sqlc.narg('language')::text IS NULL OR language = sqlc.narg('language'))
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = sqlc.narg('viewer_id')::uuid AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = sqlc.narg('viewer_id')::uuid AND mutes.muted_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = sqlc.narg('viewer_id')::uuid AND follows.followee_id = users.id
+    )
+);
+
+-- name: ListChirpsByAuthor :many
+SELECT * FROM chirps
+WHERE user_id = sqlc.arg('user_id')
+AND deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND (sqlc.narg('language')::text IS NULL OR language = sqlc.narg('language'))
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = sqlc.narg('viewer_id')::uuid AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = sqlc.narg('viewer_id')::uuid AND follows.followee_id = users.id
+    )
+)
+AND (
+    sqlc.narg('after_created_at')::timestamp IS NULL
+    OR (created_at, id) > (sqlc.narg('after_created_at')::timestamp, sqlc.narg('after_id')::uuid)
+)
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.narg('max_results');
+
+-- name: CountChirpsByAuthor :one
+SELECT COUNT(*) FROM chirps
+WHERE user_id = sqlc.arg('user_id')
+AND deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND (sqlc.narg('language')::text IS NULL OR language = sqlc.narg('language'))
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = sqlc.narg('viewer_id')::uuid AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = sqlc.narg('viewer_id')::uuid AND follows.followee_id = users.id
+    )
+);
+
+-- name: ListChirpsDesc :many
+SELECT * FROM chirps
+WHERE deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND (sqlc.narg('language')::text IS NULL OR language = sqlc.narg('language'))
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = sqlc.narg('viewer_id')::uuid AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = sqlc.narg('viewer_id')::uuid AND mutes.muted_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = sqlc.narg('viewer_id')::uuid AND follows.followee_id = users.id
+    )
+)
+AND (
+    sqlc.narg('after_created_at')::timestamp IS NULL
+    OR (created_at, id) < (sqlc.narg('after_created_at')::timestamp, sqlc.narg('after_id')::uuid)
+)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.narg('max_results');
+
+-- name: ListChirpsByAuthorDesc :many
+SELECT * FROM chirps
+WHERE user_id = sqlc.arg('user_id')
+AND deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND (sqlc.narg('language')::text IS NULL OR language = sqlc.narg('language'))
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = sqlc.narg('viewer_id')::uuid AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = sqlc.narg('viewer_id')::uuid AND follows.followee_id = users.id
+    )
+)
+AND (
+    sqlc.narg('after_created_at')::timestamp IS NULL
+    OR (created_at, id) < (sqlc.narg('after_created_at')::timestamp, sqlc.narg('after_id')::uuid)
+)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.narg('max_results');
+
+-- name: SearchChirps :many
+SELECT sqlc.embed(chirps), ts_rank(search_vector, websearch_to_tsquery('english', sqlc.arg('query')))::real AS rank
+FROM chirps
+WHERE search_vector @@ websearch_to_tsquery('english', sqlc.arg('query'))
+AND deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND (sqlc.narg('language')::text IS NULL OR language = sqlc.narg('language'))
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = sqlc.narg('viewer_id')::uuid AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = sqlc.narg('viewer_id')::uuid AND mutes.muted_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = sqlc.narg('viewer_id')::uuid AND follows.followee_id = users.id
+    )
+)
+ORDER BY rank DESC, created_at DESC, id DESC
+LIMIT sqlc.arg('max_results')
+OFFSET sqlc.arg('skip');
+
+-- name: CountSearchChirps :one
+SELECT COUNT(*) FROM chirps
+WHERE search_vector @@ websearch_to_tsquery('english', sqlc.arg('query'))
+AND deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND (sqlc.narg('language')::text IS NULL OR language = sqlc.narg('language'))
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = sqlc.narg('viewer_id')::uuid AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = sqlc.narg('viewer_id')::uuid AND mutes.muted_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = sqlc.narg('viewer_id')::uuid AND follows.followee_id = users.id
+    )
+);
+
+-- name: HideChirp :exec
+UPDATE chirps
+SET hidden_at = NOW(), deleted_at = COALESCE(deleted_at, NOW())
+WHERE id = $1;
+
+-- name: AddChirpViews :exec
+UPDATE chirps
+SET view_count = chirps.view_count + views.n
+FROM (
+    SELECT UNNEST(sqlc.arg('chirp_ids')::uuid[]) AS id, UNNEST(sqlc.arg('counts')::bigint[]) AS n
+) AS views
+WHERE chirps.id = views.id;
+
+-- name: SoftDeleteChirpsByUser :exec
+UPDATE chirps
+SET deleted_at = NOW()
+WHERE user_id = $1 AND deleted_at IS NULL;
+
+-- name: ListFeed :many
+SELECT * FROM chirps
+WHERE (
+    chirps.user_id = sqlc.arg('user_id')
+    OR chirps.user_id IN (SELECT followee_id FROM follows WHERE follower_id = sqlc.arg('user_id'))
+)
+AND chirps.deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = sqlc.arg('user_id') AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = sqlc.arg('user_id') AND mutes.muted_id = chirps.user_id
+)
+AND (
+    sqlc.narg('after_created_at')::timestamp IS NULL
+    OR (chirps.created_at, chirps.id) < (sqlc.narg('after_created_at')::timestamp, sqlc.narg('after_id')::uuid)
+)
+ORDER BY chirps.created_at DESC, chirps.id DESC
+LIMIT sqlc.arg('max_results');
+
+-- name: CountFeed :one
+SELECT COUNT(*) FROM chirps
+WHERE (
+    chirps.user_id = sqlc.arg('user_id')
+    OR chirps.user_id IN (SELECT followee_id FROM follows WHERE follower_id = sqlc.arg('user_id'))
+)
+AND chirps.deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = sqlc.arg('user_id') AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = sqlc.arg('user_id') AND mutes.muted_id = chirps.user_id
+);
+
+-- name: ListAllChirpsByUser :many
+SELECT * FROM chirps
+WHERE user_id = $1
+ORDER BY created_at ASC, id ASC;
@@ -0,0 +1,14 @@
+-- name: CreateEmailChangeToken :one
+INSERT INTO email_change_tokens (token_hash, created_at, user_id, email, expires_at, used_at)
+
+VALUES ($1, NOW(), $2, $3, $4, NULL)
+
+RETURNING *;
+
+-- name: ConsumeEmailChangeToken :one
+UPDATE email_change_tokens
+SET used_at = NOW()
+WHERE token_hash = $1
+AND used_at IS NULL
+AND expires_at > NOW()
+RETURNING user_id, email;
@@ -0,0 +1,25 @@
+-- name: CreateExport :one
+INSERT INTO exports (id, created_at, updated_at, user_id)
+
+VALUES (gen_random_uuid(), NOW(), NOW(), $1)
+
+RETURNING *;
+
+-- name: GetExport :one
+SELECT * FROM exports
+WHERE id = $1 AND user_id = $2;
+
+-- name: CompleteExport :exec
+UPDATE exports
+SET status = 'ready', storage_key = $2, updated_at = NOW()
+WHERE id = $1;
+
+-- name: FailExport :exec
+UPDATE exports
+SET status = 'failed', error = $2, updated_at = NOW()
+WHERE id = $1;
+
+-- name: DeleteExportsByUser :many
+DELETE FROM exports
+WHERE user_id = $1
+RETURNING *;
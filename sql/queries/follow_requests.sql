@@ -0,0 +1,47 @@
+-- name: CreateFollowRequest :exec
+INSERT INTO follow_requests (requester_id, target_id, created_at)
+
+VALUES ($1, $2, NOW())
+
+ON CONFLICT (requester_id, target_id) DO NOTHING;
+
+-- name: DeleteFollowRequest :execrows
+DELETE FROM follow_requests
+WHERE requester_id = $1 AND target_id = $2;
+
+-- name: ListFollowRequests :many
+SELECT sqlc.embed(users), follow_requests.created_at AS requested_at
+FROM follow_requests
+JOIN users ON users.id = follow_requests.requester_id
+WHERE follow_requests.target_id = sqlc.arg('user_id')
+AND users.deleted_at IS NULL
+AND users.deactivated_at IS NULL
+AND users.suspended_at IS NULL
+AND (
+    sqlc.narg('after_created_at')::timestamp IS NULL
+    OR (follow_requests.created_at, users.id) < (sqlc.narg('after_created_at')::timestamp, sqlc.narg('after_id')::uuid)
+)
+ORDER BY follow_requests.created_at DESC, users.id DESC
+LIMIT sqlc.arg('max_results');
+
+-- name: CountFollowRequests :one
+SELECT COUNT(*) FROM follow_requests
+JOIN users ON users.id = follow_requests.requester_id
+WHERE follow_requests.target_id = sqlc.arg('user_id')
+AND users.deleted_at IS NULL
+AND users.deactivated_at IS NULL
+AND users.suspended_at IS NULL;
+
+-- name: ListFollowRequestsByUser :many
+SELECT * FROM follow_requests
+WHERE requester_id = $1
+ORDER BY created_at ASC;
+
+-- name: DeleteFollowRequestsByUser :exec
+DELETE FROM follow_requests
+WHERE requester_id = $1 OR target_id = $1;
+
+-- name: DeleteFollowRequestsBetween :exec
+DELETE FROM follow_requests
+WHERE (requester_id = sqlc.arg('user_a') AND target_id = sqlc.arg('user_b'))
+OR (requester_id = sqlc.arg('user_b') AND target_id = sqlc.arg('user_a'));
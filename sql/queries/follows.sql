@@ -0,0 +1,90 @@
+-- name: Follow :exec
+INSERT INTO follows (follower_id, followee_id, created_at)
+
+VALUES ($1, $2, NOW())
+
+ON CONFLICT (follower_id, followee_id) DO NOTHING;
+
+-- name: Unfollow :exec
+DELETE FROM follows
+WHERE follower_id = $1 AND followee_id = $2;
+
+-- name: IsFollowing :one
+SELECT EXISTS (
+    SELECT 1 FROM follows
+    WHERE follower_id = $1 AND followee_id = $2
+);
+
+-- name: GetFollowStats :many
+SELECT
+    users.id AS user_id,
+    EXISTS (
+        SELECT 1 FROM follows
+        WHERE follower_id = sqlc.narg('viewer_id')::uuid AND followee_id = users.id
+    ) AS followed_by_me,
+    EXISTS (
+        SELECT 1 FROM follow_requests
+        WHERE requester_id = sqlc.narg('viewer_id')::uuid AND target_id = users.id
+    ) AS follow_requested_by_me
+FROM users
+WHERE users.id = ANY(sqlc.arg('user_ids')::uuid[]);
+
+-- name: ListFollowsByUser :many
+SELECT * FROM follows
+WHERE follower_id = $1
+ORDER BY created_at ASC;
+
+-- name: DeleteFollowsByUser :exec
+DELETE FROM follows
+WHERE follower_id = $1 OR followee_id = $1;
+
+-- name: ListFollowers :many
+SELECT sqlc.embed(users), follows.created_at AS followed_at
+FROM follows
+JOIN users ON users.id = follows.follower_id
+WHERE follows.followee_id = sqlc.arg('user_id')
+AND users.deleted_at IS NULL
+AND users.deactivated_at IS NULL
+AND users.suspended_at IS NULL
+AND (
+    sqlc.narg('after_created_at')::timestamp IS NULL
+    OR (follows.created_at, users.id) < (sqlc.narg('after_created_at')::timestamp, sqlc.narg('after_id')::uuid)
+)
+ORDER BY follows.created_at DESC, users.id DESC
+LIMIT sqlc.arg('max_results');
+
+-- name: CountFollowers :one
+SELECT COUNT(*) FROM follows
+JOIN users ON users.id = follows.follower_id
+WHERE follows.followee_id = sqlc.arg('user_id')
+AND users.deleted_at IS NULL
+AND users.deactivated_at IS NULL
+AND users.suspended_at IS NULL;
+
+-- name: ListFollowing :many
+SELECT sqlc.embed(users), follows.created_at AS followed_at
+FROM follows
+JOIN users ON users.id = follows.followee_id
+WHERE follows.follower_id = sqlc.arg('user_id')
+AND users.deleted_at IS NULL
+AND users.deactivated_at IS NULL
+AND users.suspended_at IS NULL
+AND (
+    sqlc.narg('after_created_at')::timestamp IS NULL
+    OR (follows.created_at, users.id) < (sqlc.narg('after_created_at')::timestamp, sqlc.narg('after_id')::uuid)
+)
+ORDER BY follows.created_at DESC, users.id DESC
+LIMIT sqlc.arg('max_results');
+
+-- name: CountFollowing :one
+SELECT COUNT(*) FROM follows
+JOIN users ON users.id = follows.followee_id
+WHERE follows.follower_id = sqlc.arg('user_id')
+AND users.deleted_at IS NULL
+AND users.deactivated_at IS NULL
+AND users.suspended_at IS NULL;
+
+-- name: DeleteFollowsBetween :exec
+DELETE FROM follows
+WHERE (follower_id = sqlc.arg('user_a') AND followee_id = sqlc.arg('user_b'))
+OR (follower_id = sqlc.arg('user_b') AND followee_id = sqlc.arg('user_a'));
@@ -0,0 +1,23 @@
+-- name: ReserveIdempotencyKey :execrows
+INSERT INTO idempotency_keys (user_id, route, key, request_hash, created_at)
+
+VALUES ($1, $2, $3, $4, NOW())
+
+ON CONFLICT DO NOTHING;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE user_id IS NOT DISTINCT FROM $1 AND route = $2 AND key = $3;
+
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys
+SET status_code = $4, content_type = $5, response_body = $6
+WHERE user_id IS NOT DISTINCT FROM $1 AND route = $2 AND key = $3;
+
+-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE user_id IS NOT DISTINCT FROM $1 AND route = $2 AND key = $3;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE created_at < $1;
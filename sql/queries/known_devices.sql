@@ -0,0 +1,14 @@
+-- name: UpsertKnownDevice :one
+INSERT INTO known_devices (id, user_id, ip_address, user_agent, first_seen_at, last_seen_at)
+
+VALUES (gen_random_uuid(), $1, $2, $3, NOW(), NOW())
+
+ON CONFLICT (user_id, ip_address, user_agent)
+DO UPDATE SET last_seen_at = NOW()
+
+RETURNING (xmax = 0)::boolean AS is_new;
+
+-- name: ListKnownDevicesForUser :many
+SELECT * FROM known_devices
+WHERE user_id = $1
+ORDER BY last_seen_at DESC;
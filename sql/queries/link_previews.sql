@@ -0,0 +1,20 @@
+-- name: SaveLinkPreview :exec
+INSERT INTO link_previews (chirp_id, fetched_at, url, title, description, image_url)
+SELECT chirps.id, NOW(), sqlc.arg('url'), sqlc.arg('title'), sqlc.arg('description'), sqlc.arg('image_url')
+FROM chirps
+WHERE chirps.id = sqlc.arg('chirp_id')
+AND chirps.updated_at = sqlc.arg('chirp_updated_at')
+ON CONFLICT (chirp_id) DO UPDATE
+SET fetched_at = EXCLUDED.fetched_at,
+    url = EXCLUDED.url,
+    title = EXCLUDED.title,
+    description = EXCLUDED.description,
+    image_url = EXCLUDED.image_url;
+
+-- name: DeleteLinkPreview :exec
+DELETE FROM link_previews
+WHERE chirp_id = $1;
+
+-- name: ListLinkPreviews :many
+SELECT * FROM link_previews
+WHERE chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[]);
@@ -0,0 +1,15 @@
+-- name: RecordLoginAttempt :exec
+INSERT INTO login_attempts (id, created_at, user_id, ip_address, succeeded)
+
+VALUES (gen_random_uuid(), NOW(), $1, $2, $3);
+
+-- name: CountRecentFailedLoginAttempts :one
+SELECT COUNT(*) FROM login_attempts
+WHERE user_id = $1
+AND succeeded = FALSE
+AND created_at > $2;
+
+-- name: ClearFailedLoginAttempts :exec
+DELETE FROM login_attempts
+WHERE user_id = $1
+AND succeeded = FALSE;
@@ -0,0 +1,20 @@
+-- name: CreateMediaAttachment :one
+INSERT INTO media_attachments (id, created_at, chirp_id, user_id, storage_key, content_type, size_bytes)
+
+VALUES (gen_random_uuid(), NOW(), $1, $2, $3, $4, $5)
+
+RETURNING *;
+
+-- name: CountMediaAttachments :one
+SELECT COUNT(*) FROM media_attachments
+WHERE chirp_id = $1;
+
+-- name: ListMediaAttachments :many
+SELECT * FROM media_attachments
+WHERE chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[])
+ORDER BY created_at ASC, id ASC;
+
+-- name: ListMediaAttachmentsByUser :many
+SELECT * FROM media_attachments
+WHERE user_id = $1
+ORDER BY created_at ASC, id ASC;
@@ -0,0 +1,72 @@
+-- name: AddMention :exec
+INSERT INTO mentions (chirp_id, user_id, created_at)
+
+VALUES ($1, $2, NOW())
+
+ON CONFLICT DO NOTHING;
+
+-- name: IsMentioned :one
+SELECT EXISTS (
+    SELECT 1 FROM mentions
+    WHERE chirp_id = $1 AND user_id = $2
+);
+
+-- name: DeleteMentions :exec
+DELETE FROM mentions
+WHERE chirp_id = $1;
+
+-- name: ListMentioningChirps :many
+SELECT chirps.* FROM chirps
+JOIN mentions ON mentions.chirp_id = chirps.id
+WHERE mentions.user_id = sqlc.arg('user_id')
+AND chirps.deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = sqlc.arg('user_id') AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = sqlc.arg('user_id') AND mutes.muted_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id <> sqlc.arg('user_id')
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = sqlc.arg('user_id') AND follows.followee_id = users.id
+    )
+)
+AND (
+    sqlc.narg('after_created_at')::timestamp IS NULL
+    OR (chirps.created_at, chirps.id) < (sqlc.narg('after_created_at')::timestamp, sqlc.narg('after_id')::uuid)
+)
+ORDER BY chirps.created_at DESC, chirps.id DESC
+LIMIT sqlc.arg('max_results');
+
+-- name: CountMentioningChirps :one
+SELECT COUNT(*) FROM chirps
+JOIN mentions ON mentions.chirp_id = chirps.id
+WHERE mentions.user_id = sqlc.arg('user_id')
+AND chirps.deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = sqlc.arg('user_id') AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = sqlc.arg('user_id') AND mutes.muted_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id <> sqlc.arg('user_id')
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = sqlc.arg('user_id') AND follows.followee_id = users.id
+    )
+);
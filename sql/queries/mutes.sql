@@ -0,0 +1,36 @@
+-- name: Mute :exec
+INSERT INTO mutes (muter_id, muted_id, created_at)
+
+VALUES ($1, $2, NOW())
+
+ON CONFLICT (muter_id, muted_id) DO NOTHING;
+
+-- name: Unmute :exec
+DELETE FROM mutes
+WHERE muter_id = $1 AND muted_id = $2;
+
+-- name: ListMutes :many
+SELECT sqlc.embed(users), mutes.created_at AS muted_at
+FROM mutes
+JOIN users ON users.id = mutes.muted_id
+WHERE mutes.muter_id = sqlc.arg('user_id')
+AND (
+    sqlc.narg('after_created_at')::timestamp IS NULL
+    OR (mutes.created_at, users.id) < (sqlc.narg('after_created_at')::timestamp, sqlc.narg('after_id')::uuid)
+)
+ORDER BY mutes.created_at DESC, users.id DESC
+LIMIT sqlc.arg('max_results');
+
+-- name: CountMutes :one
+SELECT COUNT(*) FROM mutes
+JOIN users ON users.id = mutes.muted_id
+WHERE mutes.muter_id = sqlc.arg('user_id');
+
+-- name: ListMutesByUser :many
+SELECT * FROM mutes
+WHERE muter_id = $1
+ORDER BY created_at ASC;
+
+-- name: DeleteMutesByUser :exec
+DELETE FROM mutes
+WHERE muter_id = $1 OR muted_id = $1;
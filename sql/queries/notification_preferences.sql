@@ -0,0 +1,16 @@
+-- name: UpsertNotificationPreference :exec
+INSERT INTO notification_preferences (user_id, kind, in_app, email, updated_at)
+
+VALUES ($1, $2, $3, $4, NOW())
+
+ON CONFLICT (user_id, kind)
+DO UPDATE SET in_app = EXCLUDED.in_app, email = EXCLUDED.email, updated_at = NOW();
+
+-- name: GetNotificationPreference :one
+SELECT * FROM notification_preferences
+WHERE user_id = $1 AND kind = $2;
+
+-- name: ListNotificationPreferences :many
+SELECT * FROM notification_preferences
+WHERE user_id = $1
+ORDER BY kind ASC;
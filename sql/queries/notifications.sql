@@ -0,0 +1,17 @@
+-- name: CreateNotification :one
+INSERT INTO notifications (id, created_at, user_id, kind, body, read_at)
+
+VALUES (gen_random_uuid(), NOW(), $1, $2, $3, NULL)
+
+RETURNING *;
+
+-- name: ListNotificationsForUser :many
+SELECT * FROM notifications
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
+
+-- name: ListAllNotificationsForUser :many
+SELECT * FROM notifications
+WHERE user_id = $1
+ORDER BY created_at ASC;
@@ -0,0 +1,94 @@
+-- name: Rechirp :exec
+INSERT INTO rechirps (user_id, chirp_id, created_at)
+
+VALUES ($1, $2, NOW())
+
+ON CONFLICT (user_id, chirp_id) DO NOTHING;
+
+-- name: UndoRechirp :exec
+DELETE FROM rechirps
+WHERE user_id = $1 AND chirp_id = $2;
+
+-- name: GetChirpRechirpStats :many
+SELECT
+    chirp_id,
+    COUNT(*) AS rechirp_count,
+    COALESCE(BOOL_OR(user_id = sqlc.narg('viewer_id')::uuid), false)::boolean AS rechirped_by_me
+FROM rechirps
+WHERE chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[])
+GROUP BY chirp_id;
+
+-- name: ListUserTimeline :many
+SELECT sqlc.embed(chirps), timeline.activity_at::timestamp AS activity_at, timeline.rechirped::boolean AS rechirped
+FROM (
+    SELECT id AS chirp_id, created_at AS activity_at, false AS rechirped
+    FROM chirps
+    WHERE chirps.user_id = sqlc.arg('user_id')
+    UNION ALL
+    SELECT rechirps.chirp_id, rechirps.created_at, true
+    FROM rechirps
+    WHERE rechirps.user_id = sqlc.arg('user_id')
+) AS timeline
+JOIN chirps ON chirps.id = timeline.chirp_id
+WHERE chirps.deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = sqlc.narg('viewer_id')::uuid AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = sqlc.narg('viewer_id')::uuid AND mutes.muted_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = sqlc.narg('viewer_id')::uuid AND follows.followee_id = users.id
+    )
+)
+AND (
+    sqlc.narg('after_created_at')::timestamp IS NULL
+    OR (timeline.activity_at, chirps.id) < (sqlc.narg('after_created_at')::timestamp, sqlc.narg('after_id')::uuid)
+)
+ORDER BY timeline.activity_at DESC, chirps.id DESC
+LIMIT sqlc.arg('max_results');
+
+-- name: CountUserTimeline :one
+SELECT COUNT(*) FROM (
+    SELECT id AS chirp_id
+    FROM chirps
+    WHERE chirps.user_id = sqlc.arg('user_id')
+    UNION ALL
+    SELECT rechirps.chirp_id
+    FROM rechirps
+    WHERE rechirps.user_id = sqlc.arg('user_id')
+) AS timeline
+JOIN chirps ON chirps.id = timeline.chirp_id
+WHERE chirps.deleted_at IS NULL
+AND chirps.user_id NOT IN (SELECT users.id FROM users WHERE users.deactivated_at IS NOT NULL)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = sqlc.narg('viewer_id')::uuid AND blocks.blocked_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = sqlc.narg('viewer_id')::uuid AND mutes.muted_id = chirps.user_id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM users
+    WHERE users.id = chirps.user_id
+    AND users.is_private
+    AND users.id IS DISTINCT FROM sqlc.narg('viewer_id')::uuid
+    AND NOT EXISTS (
+        SELECT 1 FROM follows
+        WHERE follows.follower_id = sqlc.narg('viewer_id')::uuid AND follows.followee_id = users.id
+    )
+);
+
+-- name: DeleteRechirpsByUser :exec
+DELETE FROM rechirps
+WHERE user_id = $1;
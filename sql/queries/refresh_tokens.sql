@@ -0,0 +1,48 @@
+-- name: CreateRefreshToken :one
+INSERT INTO refresh_tokens (id, token, created_at, updated_at, user_id, expires_at, revoked_at, user_agent, ip_address, kind)
+
+VALUES (gen_random_uuid(), $1, NOW(), NOW(), $2, $3, NULL, $4, $5, $6)
+
+RETURNING *;
+
+-- name: GetUserFromRefreshToken :one
+SELECT users.* FROM users
+JOIN refresh_tokens ON users.id = refresh_tokens.user_id
+WHERE refresh_tokens.token = $1
+AND refresh_tokens.revoked_at IS NULL
+AND refresh_tokens.expires_at > NOW()
+AND users.suspended_at IS NULL
+AND users.deactivated_at IS NULL;
+
+-- name: RevokeRefreshToken :one
+UPDATE refresh_tokens
+SET revoked_at = NOW(), updated_at = NOW()
+WHERE token = $1
+AND revoked_at IS NULL
+RETURNING user_id;
+
+-- name: ListActiveRefreshTokensForUser :many
+SELECT * FROM refresh_tokens
+WHERE user_id = $1
+AND revoked_at IS NULL
+AND expires_at > NOW()
+ORDER BY created_at DESC;
+
+-- name: RevokeRefreshTokenByID :execrows
+UPDATE refresh_tokens
+SET revoked_at = NOW(), updated_at = NOW()
+WHERE id = $1
+AND user_id = $2
+AND revoked_at IS NULL;
+
+-- name: RevokeAllRefreshTokensForUser :execrows
+UPDATE refresh_tokens
+SET revoked_at = NOW(), updated_at = NOW()
+WHERE user_id = $1
+AND revoked_at IS NULL;
+
+-- name: RevokeRefreshTokensByKind :execrows
+UPDATE refresh_tokens
+SET revoked_at = NOW(), updated_at = NOW()
+WHERE kind = $1
+AND revoked_at IS NULL;
@@ -0,0 +1,14 @@
+-- name: CreateResetToken :one
+INSERT INTO reset_tokens (token_hash, created_at, user_id, expires_at, used_at)
+
+VALUES ($1, NOW(), $2, $3, NULL)
+
+RETURNING *;
+
+-- name: ConsumeResetToken :one
+UPDATE reset_tokens
+SET used_at = NOW()
+WHERE token_hash = $1
+AND used_at IS NULL
+AND expires_at > NOW()
+RETURNING user_id;
@@ -0,0 +1,30 @@
+-- name: CreateScheduledChirp :one
+INSERT INTO scheduled_chirps (id, created_at, body, user_id, publish_at)
+
+VALUES (gen_random_uuid(), NOW(), $1, $2, $3)
+
+RETURNING *;
+
+-- name: ListScheduledChirps :many
+SELECT * FROM scheduled_chirps
+ORDER BY publish_at ASC, id ASC;
+
+-- name: ListScheduledChirpsByUser :many
+SELECT * FROM scheduled_chirps
+WHERE user_id = $1
+ORDER BY publish_at ASC, id ASC;
+
+-- name: ClaimDueScheduledChirps :many
+DELETE FROM scheduled_chirps
+WHERE id IN (
+    SELECT id FROM scheduled_chirps
+    WHERE publish_at <= NOW()
+    ORDER BY publish_at ASC
+    LIMIT sqlc.arg('max_results')
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: DeleteScheduledChirpsByUser :exec
+DELETE FROM scheduled_chirps
+WHERE user_id = $1;
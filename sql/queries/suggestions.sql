@@ -0,0 +1,82 @@
+-- name: DeleteSuggestions :exec
+DELETE FROM suggestions;
+
+-- name: InsertPersonalSuggestions :exec
+-- Suggests the accounts followed by the people a user follows. Each mutual
+-- follow is worth mutual_weight points and each recent chirp one point.
+INSERT INTO suggestions (user_id, suggested_id, mutual_count, score, computed_at)
+SELECT ranked.user_id, ranked.suggested_id, ranked.mutual_count, ranked.score, NOW()
+FROM (
+    SELECT candidates.*,
+        ROW_NUMBER() OVER (PARTITION BY candidates.user_id ORDER BY candidates.score DESC, candidates.suggested_id) AS position
+    FROM (
+        SELECT
+            mine.follower_id AS user_id,
+            theirs.followee_id AS suggested_id,
+            COUNT(*)::integer AS mutual_count,
+            COUNT(*) * sqlc.arg('mutual_weight')::bigint + COALESCE(MAX(activity.chirp_count), 0) AS score
+        FROM follows mine
+        JOIN follows theirs ON theirs.follower_id = mine.followee_id
+        LEFT JOIN (
+            SELECT chirps.user_id, COUNT(*) AS chirp_count FROM chirps
+            WHERE chirps.created_at > sqlc.arg('active_since')::timestamp AND chirps.deleted_at IS NULL
+            GROUP BY chirps.user_id
+        ) AS activity ON activity.user_id = theirs.followee_id
+        WHERE theirs.followee_id <> mine.follower_id
+        AND NOT EXISTS (
+            SELECT 1 FROM follows
+            WHERE follows.follower_id = mine.follower_id AND follows.followee_id = theirs.followee_id
+        )
+        GROUP BY mine.follower_id, theirs.followee_id
+    ) AS candidates
+) AS ranked
+WHERE ranked.position <= sqlc.arg('per_user')::bigint;
+
+-- name: InsertGeneralSuggestions :exec
+-- Suggests the accounts that chirped the most recently, to everyone.
+INSERT INTO suggestions (user_id, suggested_id, mutual_count, score, computed_at)
+SELECT NULL, chirps.user_id, 0, COUNT(*), NOW()
+FROM chirps
+WHERE chirps.created_at > sqlc.arg('active_since')::timestamp
+AND chirps.deleted_at IS NULL
+GROUP BY chirps.user_id
+ORDER BY COUNT(*) DESC, chirps.user_id
+LIMIT sqlc.arg('max_results');
+
+-- name: ListSuggestions :many
+-- Personal suggestions come first, then general ones. Follows, requests,
+-- blocks and mutes made since the last refresh are applied here.
+SELECT sqlc.embed(users), suggestions.mutual_count
+FROM suggestions
+JOIN users ON users.id = suggestions.suggested_id
+WHERE (suggestions.user_id = sqlc.arg('user_id')::uuid OR suggestions.user_id IS NULL)
+AND users.id <> sqlc.arg('user_id')::uuid
+AND users.deleted_at IS NULL
+AND users.deactivated_at IS NULL
+AND users.suspended_at IS NULL
+AND (
+    suggestions.user_id IS NOT NULL
+    OR NOT EXISTS (
+        SELECT 1 FROM suggestions AS personal
+        WHERE personal.user_id = sqlc.arg('user_id')::uuid AND personal.suggested_id = suggestions.suggested_id
+    )
+)
+AND NOT EXISTS (
+    SELECT 1 FROM follows
+    WHERE follows.follower_id = sqlc.arg('user_id')::uuid AND follows.followee_id = users.id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM follow_requests
+    WHERE follow_requests.requester_id = sqlc.arg('user_id')::uuid AND follow_requests.target_id = users.id
+)
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE (blocks.blocker_id = sqlc.arg('user_id')::uuid AND blocks.blocked_id = users.id)
+    OR (blocks.blocker_id = users.id AND blocks.blocked_id = sqlc.arg('user_id')::uuid)
+)
+AND NOT EXISTS (
+    SELECT 1 FROM mutes
+    WHERE mutes.muter_id = sqlc.arg('user_id')::uuid AND mutes.muted_id = users.id
+)
+ORDER BY suggestions.user_id IS NULL, suggestions.score DESC, users.id
+LIMIT sqlc.arg('max_results');
@@ -1,6 +1,180 @@
 -- name: CreateUser :one
-INSERT INTO users (id, created_at, updated_at, email)
+INSERT INTO users (id, created_at, updated_at, email, hashed_password, handle)
 
-VALUES (gen_random_uuid(), NOW(), NOW(), $1)
+VALUES (gen_random_uuid(), NOW(), NOW(), $1, $2, $3)
+ON CONFLICT DO NOTHING
+RETURNING *;
 
-RETURNING *;
\ No newline at end of file
+-- name: GetUserByEmail :one
+SELECT * FROM users
+WHERE email = $1;
+
+-- name: GetUserByHandle :one
+SELECT * FROM users
+WHERE LOWER(handle) = LOWER(sqlc.arg('handle'));
+
+-- name: UpdateUser :one
+UPDATE users
+SET hashed_password = $2, pending_email = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: ConfirmPendingEmail :one
+UPDATE users
+SET email = pending_email, pending_email = NULL, updated_at = NOW()
+WHERE id = $1 AND pending_email = sqlc.arg('email')::text
+RETURNING *;
+
+-- name: GetUserByID :one
+SELECT * FROM users
+WHERE id = $1;
+
+-- name: UpdateUserPassword :exec
+UPDATE users
+SET hashed_password = $2, updated_at = NOW()
+WHERE id = $1;
+
+-- name: LockUser :exec
+UPDATE users
+SET locked_until = $2, updated_at = NOW()
+WHERE id = $1;
+
+-- name: UnlockUser :execrows
+UPDATE users
+SET locked_until = NULL, updated_at = NOW()
+WHERE id = $1;
+
+-- name: RehashUserPassword :exec
+UPDATE users
+SET hashed_password = sqlc.arg('new_hashed_password')
+WHERE id = sqlc.arg('id')
+AND hashed_password = sqlc.arg('old_hashed_password');
+
+-- name: GetMentionableUserIDs :many
+SELECT id FROM users
+WHERE LOWER(handle) = ANY(sqlc.arg('handles')::text[])
+AND NOT EXISTS (
+    SELECT 1 FROM blocks
+    WHERE blocks.blocker_id = users.id AND blocks.blocked_id = sqlc.arg('author_id')
+);
+
+-- name: SetPinnedChirp :one
+UPDATE users
+SET pinned_chirp_id = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: UnpinChirp :exec
+UPDATE users
+SET pinned_chirp_id = NULL
+WHERE pinned_chirp_id = $1;
+
+-- name: SuspendUser :exec
+UPDATE users
+SET suspended_at = NOW(), updated_at = NOW()
+WHERE id = $1;
+
+-- name: ListUserProfilesByHandles :many
+SELECT * FROM users
+WHERE LOWER(users.handle) = ANY(sqlc.arg('handles')::text[])
+AND users.suspended_at IS NULL
+AND users.deleted_at IS NULL
+AND users.deactivated_at IS NULL
+ORDER BY users.handle;
+
+-- name: AnonymizeUser :execrows
+UPDATE users
+SET email = 'deleted+' || id::text || '@invalid',
+    handle = 'del_' || LEFT(REPLACE(id::text, '-', ''), 10),
+    hashed_password = '',
+    pending_email = NULL,
+    display_name = NULL,
+    bio = NULL,
+    location = NULL,
+    website = NULL,
+    pinned_chirp_id = NULL,
+    avatar_key = NULL,
+    deleted_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: SetUserAvatar :one
+UPDATE users
+SET avatar_key = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateUserProfile :one
+UPDATE users
+SET display_name = $2, bio = $3, location = $4, website = $5, is_private = $6, show_presence = $7, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: SearchUsers :many
+SELECT * FROM users
+WHERE (
+    LOWER(users.handle) LIKE sqlc.arg('pattern')
+    OR LOWER(users.display_name) LIKE sqlc.arg('pattern')
+)
+AND users.deleted_at IS NULL
+AND users.deactivated_at IS NULL
+AND users.suspended_at IS NULL
+ORDER BY
+    CASE
+        WHEN LOWER(users.handle) = sqlc.arg('query') THEN 0
+        WHEN LOWER(users.handle) LIKE sqlc.arg('prefix') THEN 1
+        WHEN LOWER(users.display_name) LIKE sqlc.arg('prefix') THEN 2
+        ELSE 3
+    END,
+    LOWER(users.handle)
+LIMIT sqlc.arg('max_results')
+OFFSET sqlc.arg('skip');
+
+-- name: CountSearchUsers :one
+SELECT COUNT(*) FROM users
+WHERE (
+    LOWER(users.handle) LIKE sqlc.arg('pattern')
+    OR LOWER(users.display_name) LIKE sqlc.arg('pattern')
+)
+AND users.deleted_at IS NULL
+AND users.deactivated_at IS NULL
+AND users.suspended_at IS NULL;
+
+-- name: DeactivateUser :execrows
+UPDATE users
+SET deactivated_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND deactivated_at IS NULL;
+
+-- name: ReactivateUser :exec
+UPDATE users
+SET deactivated_at = NULL, updated_at = NOW()
+WHERE id = $1;
+
+-- name: ListExpiredDeactivatedUsers :many
+SELECT id FROM users
+WHERE deactivated_at < sqlc.arg('cutoff')::timestamp
+AND deleted_at IS NULL
+LIMIT sqlc.arg('max_results');
+
+-- name: UpgradeUserToChirpyRed :execrows
+UPDATE users
+SET is_chirpy_red = TRUE, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: SetUserVerified :execrows
+UPDATE users
+SET is_verified = $2, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: ListUsersByIDs :many
+SELECT * FROM users
+WHERE id = ANY(sqlc.arg('ids')::uuid[]);
+
+-- name: SetUsersLastSeen :exec
+UPDATE users
+SET last_seen_at = seen.at
+FROM (
+    SELECT UNNEST(sqlc.arg('user_ids')::uuid[]) AS id, UNNEST(sqlc.arg('seen_at')::timestamp[]) AS at
+) AS seen
+WHERE users.id = seen.id
+AND (users.last_seen_at IS NULL OR users.last_seen_at < seen.at);
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE login_attempts (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    ip_address TEXT NOT NULL,
+    succeeded BOOLEAN NOT NULL
+);
+
+CREATE INDEX login_attempts_user_id_created_at_idx ON login_attempts (user_id, created_at);
+
+ALTER TABLE users
+ADD COLUMN locked_until TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE users
+DROP COLUMN locked_until;
+
+DROP TABLE login_attempts;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE auth_events (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    event_type TEXT NOT NULL,
+    ip_address TEXT NOT NULL,
+    user_agent TEXT NOT NULL,
+    detail TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX auth_events_created_at_idx ON auth_events (created_at);
+CREATE INDEX auth_events_user_id_idx ON auth_events (user_id);
+
+-- +goose Down
+DROP TABLE auth_events;
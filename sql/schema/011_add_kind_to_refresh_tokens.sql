@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE refresh_tokens
+ADD COLUMN kind TEXT NOT NULL DEFAULT 'standard'
+CHECK (kind IN ('standard', 'remember_me'));
+
+-- +goose Down
+ALTER TABLE refresh_tokens
+DROP COLUMN kind;
@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX chirp_hashtags_created_at_idx ON chirp_hashtags (created_at);
+
+-- +goose Down
+DROP INDEX chirp_hashtags_created_at_idx;
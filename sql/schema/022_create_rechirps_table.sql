@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE rechirps (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    chirp_id UUID NOT NULL REFERENCES chirps(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    UNIQUE (user_id, chirp_id)
+);
+
+CREATE INDEX rechirps_chirp_id_idx ON rechirps (chirp_id);
+CREATE INDEX rechirps_user_id_created_at_idx ON rechirps (user_id, created_at);
+
+-- +goose Down
+DROP TABLE rechirps;
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE media_attachments (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    chirp_id UUID NOT NULL REFERENCES chirps(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    storage_key TEXT NOT NULL UNIQUE,
+    content_type TEXT NOT NULL,
+    size_bytes BIGINT NOT NULL
+);
+
+CREATE INDEX media_attachments_chirp_id_idx ON media_attachments (chirp_id);
+
+-- +goose Down
+DROP TABLE media_attachments;
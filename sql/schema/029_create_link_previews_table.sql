@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE link_previews (
+    chirp_id UUID PRIMARY KEY REFERENCES chirps(id) ON DELETE CASCADE,
+    fetched_at TIMESTAMP NOT NULL,
+    url TEXT NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL,
+    image_url TEXT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE link_previews;
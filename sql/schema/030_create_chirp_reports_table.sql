@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE chirp_reports (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    chirp_id UUID NOT NULL REFERENCES chirps(id) ON DELETE CASCADE,
+    reporter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    details TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'open',
+    UNIQUE (chirp_id, reporter_id)
+);
+
+CREATE INDEX chirp_reports_status_created_at_idx ON chirp_reports (status, created_at);
+
+-- +goose Down
+DROP TABLE chirp_reports;
@@ -0,0 +1,23 @@
+-- +goose Up
+ALTER TABLE chirp_reports
+ADD COLUMN action TEXT,
+ADD COLUMN resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+ADD COLUMN resolved_at TIMESTAMP;
+
+ALTER TABLE chirps
+ADD COLUMN hidden_at TIMESTAMP;
+
+ALTER TABLE users
+ADD COLUMN suspended_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE users
+DROP COLUMN suspended_at;
+
+ALTER TABLE chirps
+DROP COLUMN hidden_at;
+
+ALTER TABLE chirp_reports
+DROP COLUMN resolved_at,
+DROP COLUMN resolved_by,
+DROP COLUMN action;
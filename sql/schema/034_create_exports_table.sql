@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE exports (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'pending',
+    storage_key TEXT,
+    error TEXT
+);
+
+CREATE INDEX exports_user_id_idx ON exports (user_id);
+
+-- +goose Down
+DROP TABLE exports;
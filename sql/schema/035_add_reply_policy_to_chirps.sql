@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE chirps
+ADD COLUMN reply_policy TEXT NOT NULL DEFAULT 'everyone';
+
+-- +goose Down
+ALTER TABLE chirps
+DROP COLUMN reply_policy;
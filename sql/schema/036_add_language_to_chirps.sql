@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE chirps
+ADD COLUMN language TEXT NOT NULL DEFAULT 'und';
+
+CREATE INDEX chirps_language_created_at_idx ON chirps (language, created_at);
+
+-- +goose Down
+DROP INDEX chirps_language_created_at_idx;
+
+ALTER TABLE chirps
+DROP COLUMN language;
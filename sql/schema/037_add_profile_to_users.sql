@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN display_name TEXT,
+ADD COLUMN bio TEXT;
+
+-- +goose Down
+ALTER TABLE users
+DROP COLUMN bio,
+DROP COLUMN display_name;
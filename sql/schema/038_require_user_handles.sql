@@ -0,0 +1,13 @@
+-- +goose Up
+UPDATE users
+SET handle = 'user_' || LEFT(REPLACE(id::text, '-', ''), 10)
+WHERE handle IS NULL;
+
+ALTER TABLE users
+ALTER COLUMN handle SET NOT NULL,
+ADD CONSTRAINT users_handle_format CHECK (handle ~ '^[A-Za-z0-9_]{3,15}$');
+
+-- +goose Down
+ALTER TABLE users
+DROP CONSTRAINT users_handle_format,
+ALTER COLUMN handle DROP NOT NULL;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN location TEXT,
+ADD COLUMN website TEXT;
+
+-- +goose Down
+ALTER TABLE users
+DROP COLUMN website,
+DROP COLUMN location;
@@ -0,0 +1,9 @@
+-- +goose Up
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX users_handle_trgm_idx ON users USING GIN (LOWER(handle) gin_trgm_ops);
+CREATE INDEX users_display_name_trgm_idx ON users USING GIN (LOWER(display_name) gin_trgm_ops);
+
+-- +goose Down
+DROP INDEX users_display_name_trgm_idx;
+DROP INDEX users_handle_trgm_idx;
@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN deactivated_at TIMESTAMP;
+
+CREATE INDEX users_deactivated_at_idx ON users (deactivated_at) WHERE deactivated_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX users_deactivated_at_idx;
+
+ALTER TABLE users
+DROP COLUMN deactivated_at;
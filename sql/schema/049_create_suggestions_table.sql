@@ -0,0 +1,16 @@
+-- +goose Up
+-- suggestions holds the precomputed who-to-follow lists. Rows without a
+-- user_id are the general suggestions shown to everyone, notably new users
+-- who don't follow anyone yet.
+CREATE TABLE suggestions (
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    suggested_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    mutual_count INTEGER NOT NULL,
+    score BIGINT NOT NULL,
+    computed_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX suggestions_user_id_score_idx ON suggestions (user_id, score DESC);
+
+-- +goose Down
+DROP TABLE suggestions;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- A missing row means every channel is on for that kind of notification.
+CREATE TABLE notification_preferences (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    in_app BOOLEAN NOT NULL,
+    email BOOLEAN NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, kind)
+);
+
+-- +goose Down
+DROP TABLE notification_preferences;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN last_seen_at TIMESTAMP,
+ADD COLUMN show_presence BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- +goose Down
+ALTER TABLE users
+DROP COLUMN last_seen_at,
+DROP COLUMN show_presence;
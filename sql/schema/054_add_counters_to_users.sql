@@ -0,0 +1,69 @@
+-- +goose Up
+ALTER TABLE users
+ADD COLUMN follower_count BIGINT NOT NULL DEFAULT 0,
+ADD COLUMN following_count BIGINT NOT NULL DEFAULT 0,
+ADD COLUMN chirp_count BIGINT NOT NULL DEFAULT 0;
+
+UPDATE users
+SET follower_count = (SELECT COUNT(*) FROM follows WHERE follows.followee_id = users.id),
+    following_count = (SELECT COUNT(*) FROM follows WHERE follows.follower_id = users.id),
+    chirp_count = (SELECT COUNT(*) FROM chirps WHERE chirps.user_id = users.id AND chirps.deleted_at IS NULL);
+
+-- The counters are kept up to date by triggers rather than by the queries
+-- that change follows and chirps, so rows removed by ON DELETE CASCADE are
+-- counted too.
+
+-- +goose StatementBegin
+CREATE FUNCTION update_follow_counts() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE users SET following_count = following_count + 1 WHERE id = NEW.follower_id;
+        UPDATE users SET follower_count = follower_count + 1 WHERE id = NEW.followee_id;
+    ELSE
+        UPDATE users SET following_count = following_count - 1 WHERE id = OLD.follower_id;
+        UPDATE users SET follower_count = follower_count - 1 WHERE id = OLD.followee_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER follows_update_counts
+AFTER INSERT OR DELETE ON follows
+FOR EACH ROW EXECUTE FUNCTION update_follow_counts();
+
+-- Only chirps that aren't deleted count, so soft deletes and restores move
+-- the counter as well.
+
+-- +goose StatementBegin
+CREATE FUNCTION update_chirp_count() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND OLD.user_id = NEW.user_id
+        AND (OLD.deleted_at IS NULL) = (NEW.deleted_at IS NULL) THEN
+        RETURN NULL;
+    END IF;
+    IF TG_OP IN ('UPDATE', 'DELETE') AND OLD.deleted_at IS NULL THEN
+        UPDATE users SET chirp_count = chirp_count - 1 WHERE id = OLD.user_id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.deleted_at IS NULL THEN
+        UPDATE users SET chirp_count = chirp_count + 1 WHERE id = NEW.user_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER chirps_update_count
+AFTER INSERT OR DELETE OR UPDATE OF user_id, deleted_at ON chirps
+FOR EACH ROW EXECUTE FUNCTION update_chirp_count();
+
+-- +goose Down
+DROP TRIGGER chirps_update_count ON chirps;
+DROP FUNCTION update_chirp_count();
+DROP TRIGGER follows_update_counts ON follows;
+DROP FUNCTION update_follow_counts();
+
+ALTER TABLE users
+DROP COLUMN follower_count,
+DROP COLUMN following_count,
+DROP COLUMN chirp_count;
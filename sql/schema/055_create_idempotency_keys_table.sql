@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE idempotency_keys (
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    route TEXT NOT NULL,
+    key TEXT NOT NULL,
+    request_hash BYTEA NOT NULL,
+    status_code INTEGER,
+    content_type TEXT,
+    response_body BYTEA,
+    created_at TIMESTAMP NOT NULL
+);
+
+-- Keys sent without authentication, on signup, share one namespace.
+CREATE UNIQUE INDEX idempotency_keys_user_key_idx ON idempotency_keys (user_id, route, key) WHERE user_id IS NOT NULL;
+CREATE UNIQUE INDEX idempotency_keys_anonymous_key_idx ON idempotency_keys (route, key) WHERE user_id IS NULL;
+CREATE INDEX idempotency_keys_created_at_idx ON idempotency_keys (created_at);
+
+-- +goose Down
+DROP TABLE idempotency_keys;
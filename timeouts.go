@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultReadHeaderTimeout = 10 * time.Second
+	defaultReadTimeout       = time.Minute
+	defaultWriteTimeout      = time.Minute
+	defaultIdleTimeout       = 2 * time.Minute
+	// defaultRequestTimeout is well under defaultWriteTimeout, so a handler
+	// whose database calls time out still gets to send its error response.
+	defaultRequestTimeout = 30 * time.Second
+	defaultUploadTimeout  = 50 * time.Second
+)
+
+// serverTimeouts protect the server from slow clients and slow queries. The
+// first four apply to connections; request and upload bound how long a
+// handler may take, through its request context's deadline.
+type serverTimeouts struct {
+	readHeader time.Duration
+	read       time.Duration
+	write      time.Duration
+	idle       time.Duration
+	request    time.Duration
+	upload     time.Duration
+}
+
+// loadServerTimeouts reads SERVER_READ_HEADER_TIMEOUT, SERVER_READ_TIMEOUT,
+// SERVER_WRITE_TIMEOUT, SERVER_IDLE_TIMEOUT, REQUEST_TIMEOUT and
+// UPLOAD_REQUEST_TIMEOUT.
+func loadServerTimeouts() (serverTimeouts, error) {
+	var t serverTimeouts
+	for _, setting := range []struct {
+		key      string
+		fallback time.Duration
+		dst      *time.Duration
+	}{
+		{"SERVER_READ_HEADER_TIMEOUT", defaultReadHeaderTimeout, &t.readHeader},
+		{"SERVER_READ_TIMEOUT", defaultReadTimeout, &t.read},
+		{"SERVER_WRITE_TIMEOUT", defaultWriteTimeout, &t.write},
+		{"SERVER_IDLE_TIMEOUT", defaultIdleTimeout, &t.idle},
+		{"REQUEST_TIMEOUT", defaultRequestTimeout, &t.request},
+		{"UPLOAD_REQUEST_TIMEOUT", defaultUploadTimeout, &t.upload},
+	} {
+		d, err := envDuration(setting.key, setting.fallback)
+		if err != nil {
+			return serverTimeouts{}, err
+		}
+		*setting.dst = d
+	}
+	return t, nil
+}
+
+// apply sets the connection timeouts on the server.
+func (t serverTimeouts) apply(server *http.Server) {
+	server.ReadHeaderTimeout = t.readHeader
+	server.ReadTimeout = t.read
+	server.WriteTimeout = t.write
+	server.IdleTimeout = t.idle
+}
+
+// middleware gives every request context a deadline, longer for the upload
+// routes, so database calls made with it are cancelled rather than left
+// running after the client has given up.
+func (t serverTimeouts) middleware(mux *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timeout := t.request
+		if isUploadRoute(mux, r) {
+			timeout = t.upload
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"os"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+const defaultAutocertCacheDir = "certs"
+
+// tlsConfig says how the server terminates HTTPS. With neither certificate
+// files nor autocert configured it serves plain HTTP, for running behind a
+// proxy.
+type tlsConfig struct {
+	certFile string
+	keyFile  string
+	// autocert obtains and renews certificates from Let's Encrypt for the
+	// allowed hostnames. It needs ports 443 and 80, the latter for HTTP-01
+	// challenges and for redirecting plain HTTP to HTTPS.
+	autocert *autocert.Manager
+}
+
+// loadTLSConfig reads TLS_CERT_FILE and TLS_KEY_FILE, or alternatively
+// TLS_AUTOCERT_HOSTS (a comma-separated hostname allowlist) with the optional
+// TLS_AUTOCERT_EMAIL and TLS_AUTOCERT_CACHE_DIR.
+func loadTLSConfig() (tlsConfig, error) {
+	cfg := tlsConfig{
+		certFile: os.Getenv("TLS_CERT_FILE"),
+		keyFile:  os.Getenv("TLS_KEY_FILE"),
+	}
+	if (cfg.certFile == "") != (cfg.keyFile == "") {
+		return tlsConfig{}, errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+
+	hosts := envList("TLS_AUTOCERT_HOSTS")
+	if len(hosts) == 0 {
+		return cfg, nil
+	}
+	if cfg.certFile != "" {
+		return tlsConfig{}, errors.New("TLS_AUTOCERT_HOSTS can't be combined with TLS_CERT_FILE")
+	}
+	cacheDir := os.Getenv("TLS_AUTOCERT_CACHE_DIR")
+	if cacheDir == "" {
+		cacheDir = defaultAutocertCacheDir
+	}
+	cfg.autocert = &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(hosts...),
+		Cache:      autocert.DirCache(cacheDir),
+		Email:      os.Getenv("TLS_AUTOCERT_EMAIL"),
+	}
+	return cfg, nil
+}
+
+// configure adjusts the server for the TLS mode before it starts.
+func (c tlsConfig) configure(server *http.Server) {
+	if c.autocert != nil {
+		server.Addr = ":443"
+		server.TLSConfig = c.autocert.TLSConfig()
+	}
+}
+
+// listenAndServe starts the server with or without TLS as configured.
+func (c tlsConfig) listenAndServe(server *http.Server) error {
+	switch {
+	case c.autocert != nil:
+		return server.ListenAndServeTLS("", "")
+	case c.certFile != "":
+		return server.ListenAndServeTLS(c.certFile, c.keyFile)
+	default:
+		return server.ListenAndServe()
+	}
+}
+
+// challengeServer returns the plain HTTP server that answers ACME challenges
+// and redirects everything else to HTTPS, or nil when autocert is off. It
+// gets the same connection timeouts as the main server.
+func (c tlsConfig) challengeServer(timeouts serverTimeouts) *http.Server {
+	if c.autocert == nil {
+		return nil
+	}
+	server := &http.Server{
+		Addr:    ":80",
+		Handler: c.autocert.HTTPHandler(nil),
+	}
+	timeouts.apply(server)
+	return server
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ja8mpi/bootdev-chirpy-server-go/internal/database"
+)
+
+const defaultViewFlushInterval = 10 * time.Second
+
+// viewCounter buffers chirp impressions in memory and periodically adds them
+// to the chirps' view counts in one query, so serving a chirp never waits on
+// a write. Views buffered when the process dies are lost, which is fine for
+// a popularity signal.
+type viewCounter struct {
+	db *database.Queries
+
+	mu      sync.Mutex
+	pending map[uuid.UUID]int64
+}
+
+func newViewCounter(db *database.Queries) *viewCounter {
+	return &viewCounter{
+		db:      db,
+		pending: make(map[uuid.UUID]int64),
+	}
+}
+
+// record counts one impression of each chirp.
+func (c *viewCounter) record(chirpIDs ...uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range chirpIDs {
+		c.pending[id]++
+	}
+}
+
+// recordChirps counts one impression of each chirp in a listing.
+func (c *viewCounter) recordChirps(chirps []Chirp) {
+	ids := make([]uuid.UUID, 0, len(chirps))
+	for _, chirp := range chirps {
+		ids = append(ids, chirp.ID)
+	}
+	c.record(ids...)
+}
+
+// run flushes the buffered views every interval until ctx is cancelled,
+// then flushes once more.
+func (c *viewCounter) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.flush(context.Background())
+			return
+		case <-ticker.C:
+			c.flush(ctx)
+		}
+	}
+}
+
+func (c *viewCounter) flush(ctx context.Context) {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = make(map[uuid.UUID]int64)
+	c.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	ids := make([]uuid.UUID, 0, len(pending))
+	counts := make([]int64, 0, len(pending))
+	for id, n := range pending {
+		ids = append(ids, id)
+		counts = append(counts, n)
+	}
+
+	err := c.db.AddChirpViews(ctx, database.AddChirpViewsParams{
+		ChirpIds: ids,
+		Counts:   counts,
+	})
+	if err != nil {
+		log.Printf("Error flushing chirp views: %s", err)
+		// Put the views back so the next flush retries them.
+		c.mu.Lock()
+		for id, n := range pending {
+			c.pending[id] += n
+		}
+		c.mu.Unlock()
+	}
+}